@@ -0,0 +1,144 @@
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/containous/traefik/v2/cmd"
+	"github.com/containous/traefik/v2/pkg/cli"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/flag"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/server"
+	"github.com/sirupsen/logrus"
+)
+
+// Configuration holds the static configuration checked by the check command, and the command options.
+type Configuration struct {
+	cmd.TraefikCmdConfiguration `export:"true"`
+	// Format is the output format of the errors.
+	Format string `description:"Output format of the errors: text or json." export:"true"`
+}
+
+// NewConfiguration creates a Configuration with default values.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		TraefikCmdConfiguration: *cmd.NewTraefikConfiguration(),
+		Format:                  "text",
+	}
+}
+
+// NewCmd builds a new Check command.
+func NewCmd(configuration *Configuration, loaders []cli.ResourceLoader) *cli.Command {
+	return &cli.Command{
+		Name:          "check",
+		Description:   `Checks the dynamic configuration of the file provider, by building its routers, middlewares, services and TLS configurations without starting Traefik.`,
+		Configuration: configuration,
+		Run:           runCmd(configuration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(configuration *Configuration) func(args []string) error {
+	return func(args []string) error {
+		staticConfiguration := &configuration.Configuration
+
+		// The errors are reported by the command, the logs are only shown when a log level is explicitly set.
+		level := logrus.FatalLevel
+		if staticConfiguration.Log != nil && staticConfiguration.Log.Level != "" {
+			if parsed, err := logrus.ParseLevel(strings.ToLower(staticConfiguration.Log.Level)); err == nil {
+				level = parsed
+			}
+		}
+		log.SetLevel(level)
+
+		staticConfiguration.SetEffectiveConfiguration()
+		if err := staticConfiguration.ValidateConfiguration(); err != nil {
+			return err
+		}
+
+		errs, err := Do(*staticConfiguration)
+		if err != nil {
+			return err
+		}
+
+		if err := Print(os.Stdout, outputFormat(args, configuration), errs); err != nil {
+			return err
+		}
+
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		return nil
+	}
+}
+
+// Do loads the dynamic configuration of the file provider, and returns the errors found while building it.
+func Do(staticConfiguration static.Configuration) ([]server.ConfigurationError, error) {
+	if staticConfiguration.Providers == nil || staticConfiguration.Providers.File == nil {
+		return nil, errors.New("please enable the file provider to check its dynamic configuration")
+	}
+
+	conf, err := staticConfiguration.Providers.File.BuildConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the dynamic configuration: %v", err)
+	}
+
+	return server.CheckConfiguration(staticConfiguration, dynamic.Configurations{"file": conf}), nil
+}
+
+// Print writes the errors in the given format, text or json.
+func Print(w io.Writer, format string, errs []server.ConfigurationError) error {
+	switch strings.ToLower(format) {
+	case "json":
+		if errs == nil {
+			errs = []server.ConfigurationError{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Errors []server.ConfigurationError `json:"errors"`
+		}{Errors: errs})
+
+	case "", "text":
+		for _, err := range errs {
+			if _, errW := fmt.Fprintln(w, err.Error()); errW != nil {
+				return errW
+			}
+		}
+
+		if len(errs) == 0 {
+			_, err := fmt.Fprintln(w, "OK: the configuration is valid")
+			return err
+		}
+
+		_, err := fmt.Fprintf(w, "%d error(s) found\n", len(errs))
+		return err
+
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// outputFormat returns the output format of the errors.
+// The flags are not decoded when the configuration is loaded from a file, so the format flag is looked up in the arguments too.
+func outputFormat(args []string, configuration *Configuration) string {
+	ref, err := flag.Parse(args, configuration)
+	if err != nil {
+		return configuration.Format
+	}
+
+	for name, value := range ref {
+		if strings.EqualFold(name, "traefik.format") {
+			return value
+		}
+	}
+
+	return configuration.Format
+}
@@ -0,0 +1,167 @@
+package explain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/cli"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+)
+
+// Configuration holds the request explained by the explain command, and the command options.
+type Configuration struct {
+	URL        string            `description:"URL of the Traefik API." export:"true"`
+	EntryPoint string            `description:"Entry point receiving the request." export:"true"`
+	Method     string            `description:"Method of the request." export:"true"`
+	Host       string            `description:"Host of the request." export:"true"`
+	Path       string            `description:"Path of the request, with its query." export:"true"`
+	Headers    map[string]string `description:"Headers of the request." export:"true"`
+	SNI        string            `description:"Server name sent in the TLS ClientHello. Setting it sends the request over TLS." export:"true"`
+	TLS        bool              `description:"Sends the request over TLS." export:"true"`
+	ClientIP   string            `description:"IP of the client." export:"true"`
+	Verbose    bool              `description:"Lists the routers evaluated before the matching one, and why they were rejected." export:"true"`
+	Format     string            `description:"Output format of the result: text or json." export:"true"`
+}
+
+// NewConfiguration creates a Configuration with default values.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		URL:        "http://localhost:8080",
+		EntryPoint: "web",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Format:     "text",
+	}
+}
+
+// NewCmd builds a new Explain command.
+func NewCmd(configuration *Configuration) *cli.Command {
+	return &cli.Command{
+		Name:          "explain",
+		Description:   `Calls the Traefik API to explain which router, middlewares and service handle a request on an entry point.`,
+		Configuration: configuration,
+		Run:           runCmd(configuration),
+		Resources:     []cli.ResourceLoader{&cli.FlagLoader{}},
+	}
+}
+
+func runCmd(configuration *Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		result, err := Do(*configuration)
+		if err != nil {
+			return err
+		}
+
+		return Print(os.Stdout, configuration.Format, result)
+	}
+}
+
+// Do sends the request to explain to the rule tester of the Traefik API.
+func Do(configuration Configuration) (*runtime.RuleTestResult, error) {
+	body, err := json.Marshal(runtime.RuleTestRequest{
+		EntryPoint: configuration.EntryPoint,
+		Method:     configuration.Method,
+		Host:       configuration.Host,
+		Path:       configuration.Path,
+		Headers:    configuration.Headers,
+		SNI:        configuration.SNI,
+		TLS:        configuration.TLS,
+		ClientIP:   configuration.ClientIP,
+		Verbose:    configuration.Verbose,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Post(strings.TrimSuffix(configuration.URL, "/")+"/api/rules/test", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to call the Traefik API: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return nil, fmt.Errorf("unexpected status from the Traefik API: %s", resp.Status)
+		}
+		return nil, errors.New(apiErr.Message)
+	}
+
+	var result runtime.RuleTestResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid response from the Traefik API: %v", err)
+	}
+
+	return &result, nil
+}
+
+// Print writes the result in the given format, text or json.
+func Print(w io.Writer, format string, result *runtime.RuleTestResult) error {
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(result)
+
+	case "", "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', 0)
+
+		entryPoint := result.EntryPoint
+		if result.TLS {
+			entryPoint += " (TLS)"
+		}
+		fmt.Fprintf(tw, "Entry point:\t%s\n", entryPoint)
+
+		switch {
+		case result.RedirectTo != "":
+			fmt.Fprintf(tw, "Redirected to:\t%s\n", result.RedirectTo)
+		case result.Router == "":
+			fmt.Fprintln(tw, "Router:\tnone, the request is not matched by any router")
+		default:
+			fmt.Fprintf(tw, "Router:\t%s (%s)\n", result.Router, result.Protocol)
+			fmt.Fprintf(tw, "Rule:\t%s\n", result.Rule)
+			fmt.Fprintf(tw, "Priority:\t%d\n", result.Priority)
+			if len(result.Middlewares) > 0 {
+				fmt.Fprintf(tw, "Middlewares:\t%s\n", strings.Join(result.Middlewares, ", "))
+			}
+			fmt.Fprintf(tw, "Service:\t%s\n", result.Service)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		if len(result.Routers) == 0 {
+			return nil
+		}
+
+		fmt.Fprintln(w, "\nEvaluated routers:")
+		for _, router := range result.Routers {
+			status := "matched"
+			if !router.Matched {
+				status = "rejected: " + strings.Join(router.Reasons, ", ")
+			}
+			if _, err := fmt.Fprintf(w, "  %s (%s, priority %d) %s\n", router.Name, router.Protocol, router.Priority, status); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
@@ -1,8 +1,10 @@
 package healthcheck
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"time"
@@ -58,6 +60,20 @@ func Do(staticConfiguration static.Configuration) (*http.Response, error) {
 
 	client := &http.Client{Timeout: 5 * time.Second}
 	protocol := "http"
+	address := pingEntryPoint.Address
+
+	switch network, socket := pingEntryPoint.GetAddress(); network {
+	case "unix":
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+		address = "localhost"
+	case "systemd":
+		return nil, errors.New("the `ping` entrypoint listens on a socket passed by systemd, its address is unknown")
+	}
 
 	// FIXME Handle TLS on ping etc...
 	// if pingEntryPoint.TLS != nil {
@@ -70,5 +86,5 @@ func Do(staticConfiguration static.Configuration) (*http.Response, error) {
 
 	path := "/"
 
-	return client.Head(protocol + "://" + pingEntryPoint.Address + path + "ping")
+	return client.Head(protocol + "://" + address + path + "ping")
 }
@@ -13,6 +13,8 @@ import (
 
 	"github.com/containous/traefik/v2/autogen/genstatic"
 	"github.com/containous/traefik/v2/cmd"
+	"github.com/containous/traefik/v2/cmd/check"
+	"github.com/containous/traefik/v2/cmd/explain"
 	"github.com/containous/traefik/v2/cmd/healthcheck"
 	cmdVersion "github.com/containous/traefik/v2/cmd/version"
 	"github.com/containous/traefik/v2/pkg/cli"
@@ -56,6 +58,18 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(check.NewCmd(check.NewConfiguration(), loaders))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(explain.NewCmd(explain.NewConfiguration()))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cmdTraefik.AddCommand(cmdVersion.NewCmd())
 	if err != nil {
 		stdlog.Println(err)
@@ -142,6 +156,10 @@ func runCmd(staticConfiguration *static.Configuration) error {
 		}
 	})
 
+	if staticConfiguration.Providers.KubernetesCRD != nil {
+		svr.AddRuntimeListener(staticConfiguration.Providers.KubernetesCRD.ListenRuntimeConfiguration)
+	}
+
 	ctx := cmd.ContextWithSignal(context.Background())
 
 	if staticConfiguration.Ping != nil {
@@ -3,6 +3,7 @@ package anonymize
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
 
@@ -17,24 +18,34 @@ const (
 
 // Do configuration.
 func Do(baseConfig interface{}, indent bool) (string, error) {
-	anomConfig, err := copystructure.Copy(baseConfig)
+	anomConfig, err := Copy(baseConfig)
 	if err != nil {
 		return "", err
 	}
 
-	val := reflect.ValueOf(anomConfig)
-
-	err = doOnStruct(val)
+	configJSON, err := marshal(anomConfig, indent)
 	if err != nil {
 		return "", err
 	}
 
-	configJSON, err := marshal(anomConfig, indent)
+	return doOnJSON(string(configJSON)), nil
+}
+
+// Copy returns a copy of the configuration, where the fields not tagged with export are reset,
+// and the credentials of the URLs are masked.
+// Unlike Do, it keeps the URLs and the email addresses of the exported fields, and does not marshal the copy.
+func Copy(baseConfig interface{}) (interface{}, error) {
+	anomConfig, err := copystructure.Copy(baseConfig)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return doOnJSON(string(configJSON)), nil
+	err = doOnStruct(reflect.ValueOf(anomConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	return anomConfig, nil
 }
 
 func doOnJSON(input string) string {
@@ -67,8 +78,18 @@ func doOnStruct(field reflect.Value) error {
 				}
 			}
 		}
+	case reflect.String:
+		if field.CanSet() {
+			field.SetString(maskURLCredentials(field.String()))
+		}
 	case reflect.Map:
 		for _, key := range field.MapKeys() {
+			// The values of a map are not addressable.
+			if field.Type().Elem().Kind() == reflect.String {
+				field.SetMapIndex(key, reflect.ValueOf(maskURLCredentials(field.MapIndex(key).String())).Convert(field.Type().Elem()))
+				continue
+			}
+
 			if err := doOnStruct(field.MapIndex(key)); err != nil {
 				return err
 			}
@@ -99,7 +120,7 @@ func reset(field reflect.Value, name string) error {
 		}
 	case reflect.String:
 		if field.String() != "" {
-			field.Set(reflect.ValueOf(maskShort))
+			field.SetString(maskShort)
 		}
 	case reflect.Map:
 		if field.Len() > 0 {
@@ -120,6 +141,17 @@ func reset(field reflect.Value, name string) error {
 	return nil
 }
 
+// maskURLCredentials masks the user information of the value, if it is a URL holding credentials.
+func maskURLCredentials(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User == nil {
+		return value
+	}
+
+	u.User = url.User(maskShort)
+	return u.String()
+}
+
 // isExported return true is a struct field is exported, else false
 func isExported(f reflect.StructField) bool {
 	if f.PkgPath != "" && !f.Anonymous {
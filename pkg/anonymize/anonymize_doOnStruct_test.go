@@ -16,6 +16,8 @@ type Tomate struct {
 	Ho string
 }
 
+type Radis string
+
 type Carotte struct {
 	Name        string
 	Value       int
@@ -29,6 +31,7 @@ type Carotte struct {
 	ESAubergine map[string]Tomate `export:"true"`
 	PSAubergine map[string]*Tomate
 	EPAubergine map[string]*Tomate `export:"true"`
+	Radis       Radis
 }
 
 func Test_doOnStruct(t *testing.T) {
@@ -48,6 +51,17 @@ func Test_doOnStruct(t *testing.T) {
 				Name: "xxxx",
 			},
 		},
+		{
+			name: "named string",
+			base: &Carotte{
+				Name:  "koko",
+				Radis: "huu",
+			},
+			expected: &Carotte{
+				Name:  "xxxx",
+				Radis: "xxxx",
+			},
+		},
 		{
 			name: "struct",
 			base: &Carotte{
@@ -44,8 +44,10 @@ type RunTimeRepresentation struct {
 
 // Handler serves the configuration and status of Traefik on API endpoints.
 type Handler struct {
-	dashboard bool
-	debug     bool
+	dashboard     bool
+	debug         bool
+	weightsUpdate bool
+	cachePurge    bool
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
 	staticConfig         static.Configuration
@@ -79,6 +81,8 @@ func New(staticConfig static.Configuration, runtimeConfig *runtime.Configuration
 		runtimeConfiguration: rConfig,
 		staticConfig:         staticConfig,
 		debug:                staticConfig.API.Debug,
+		weightsUpdate:        staticConfig.API.WeightsUpdate,
+		cachePurge:           staticConfig.API.CachePurge,
 	}
 }
 
@@ -89,6 +93,8 @@ func (h Handler) Append(router *mux.Router) {
 	}
 
 	router.Methods(http.MethodGet).Path("/api/rawdata").HandlerFunc(h.getRuntimeConfiguration)
+	router.Methods(http.MethodGet).Path("/api/config").HandlerFunc(h.getConfiguration)
+	router.Methods(http.MethodGet).Path("/api/config/history").HandlerFunc(h.getConfigurationHistory)
 
 	// Experimental endpoint
 	router.Methods(http.MethodGet).Path("/api/overview").HandlerFunc(h.getOverview)
@@ -100,14 +106,24 @@ func (h Handler) Append(router *mux.Router) {
 	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
 	router.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
+	if h.weightsUpdate {
+		router.Methods(http.MethodPut).Path("/api/http/services/{serviceID}/weights").HandlerFunc(h.updateServiceWeights)
+	}
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
 
+	if h.cachePurge {
+		router.Methods(http.MethodDelete).Path("/api/http/routers/{routerID}/cache").HandlerFunc(h.purgeRouterCache)
+		router.Methods(http.MethodDelete).Path("/api/http/middlewares/{middlewareID}/cache").HandlerFunc(h.purgeMiddlewareCache)
+	}
+
 	router.Methods(http.MethodGet).Path("/api/tcp/routers").HandlerFunc(h.getTCPRouters)
 	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}").HandlerFunc(h.getTCPRouter)
 	router.Methods(http.MethodGet).Path("/api/tcp/services").HandlerFunc(h.getTCPServices)
 	router.Methods(http.MethodGet).Path("/api/tcp/services/{serviceID}").HandlerFunc(h.getTCPService)
 
+	router.Methods(http.MethodPost).Path("/api/rules/test").HandlerFunc(h.testRule)
+
 	// FIXME stats
 	// health route
 	// router.Methods(http.MethodGet).Path("/health").HandlerFunc(p.getHealthHandler)
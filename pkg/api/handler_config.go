@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/BurntSushi/toml"
+	"github.com/containous/traefik/v2/pkg/anonymize"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+	"gopkg.in/yaml.v2"
+)
+
+// getConfiguration exports the merged dynamic configuration, with its secrets anonymized,
+// in the format given by the format query parameter: json (default), yaml or toml.
+func (h Handler) getConfiguration(rw http.ResponseWriter, request *http.Request) {
+	conf := h.runtimeConfiguration.DynamicConfiguration()
+	if conf == nil {
+		conf = &dynamic.Configuration{}
+	}
+
+	anonymized, err := anonymize.Copy(conf)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var data []byte
+	var contentType string
+
+	format := request.URL.Query().Get("format")
+	switch format {
+	case "", "json":
+		contentType = "application/json"
+		data, err = json.Marshal(anonymized)
+
+	case "yaml":
+		contentType = "application/x-yaml"
+		data, err = yaml.Marshal(anonymized)
+
+	case "toml":
+		contentType = "application/toml"
+		buf := &bytes.Buffer{}
+		err = toml.NewEncoder(buf).Encode(anonymized)
+		data = buf.Bytes()
+
+	default:
+		rw.Header().Set("Content-Type", "application/json")
+		writeError(rw, fmt.Sprintf("unknown format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+	if _, err := rw.Write(data); err != nil {
+		log.FromContext(request.Context()).Error(err)
+	}
+}
+
+// getConfigurationHistory returns the last reloads of the dynamic configuration, the most recent first.
+func (h Handler) getConfigurationHistory(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	reloads := []runtime.Reload{}
+	if history := h.runtimeConfiguration.ReloadHistory(); history != nil {
+		reloads = history.Reloads()
+	}
+
+	err := json.NewEncoder(rw).Encode(reloads)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
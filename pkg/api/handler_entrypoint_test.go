@@ -54,9 +54,16 @@ func TestHandler_EntryPoints(t *testing.T) {
 								GraceTimeOut:              2,
 							},
 							RespondingTimeouts: &static.RespondingTimeouts{
-								ReadTimeout:  3,
-								WriteTimeout: 4,
-								IdleTimeout:  5,
+								ReadTimeout:       3,
+								WriteTimeout:      4,
+								IdleTimeout:       5,
+								ReadHeaderTimeout: 6,
+							},
+							MaxHeaderBytes:      1024,
+							MaxRequestBodyBytes: 2048,
+							HTTP2: &static.HTTP2Config{
+								Enabled:              true,
+								MaxConcurrentStreams: 250,
 							},
 						},
 						ProxyProtocol: &static.ProxyProtocol{
@@ -76,9 +83,16 @@ func TestHandler_EntryPoints(t *testing.T) {
 								GraceTimeOut:              20,
 							},
 							RespondingTimeouts: &static.RespondingTimeouts{
-								ReadTimeout:  30,
-								WriteTimeout: 40,
-								IdleTimeout:  50,
+								ReadTimeout:       30,
+								WriteTimeout:      40,
+								IdleTimeout:       50,
+								ReadHeaderTimeout: 60,
+							},
+							MaxHeaderBytes:      4096,
+							MaxRequestBodyBytes: 8192,
+							HTTP2: &static.HTTP2Config{
+								Enabled:              false,
+								MaxConcurrentStreams: 100,
 							},
 						},
 						ProxyProtocol: &static.ProxyProtocol{
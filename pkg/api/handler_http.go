@@ -30,6 +30,7 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
 	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+	Weights      map[string]int    `json:"weights,omitempty"`
 	Name         string            `json:"name,omitempty"`
 	Provider     string            `json:"provider,omitempty"`
 	Type         string            `json:"type,omitempty"`
@@ -41,6 +42,7 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		Weights:      si.GetWeights(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
@@ -165,6 +167,37 @@ func (h Handler) getService(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) updateServiceWeights(rw http.ResponseWriter, request *http.Request) {
+	serviceID := mux.Vars(request)["serviceID"]
+
+	rw.Header().Add("Content-Type", "application/json")
+
+	service, ok := h.runtimeConfiguration.Services[serviceID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("service not found: %s", serviceID), http.StatusNotFound)
+		return
+	}
+
+	var weights map[string]int
+	if err := json.NewDecoder(request.Body).Decode(&weights); err != nil {
+		writeError(rw, fmt.Sprintf("invalid weights: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := service.UpdateWeights(weights); err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := newServiceRepresentation(serviceID, service)
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h Handler) getMiddlewares(rw http.ResponseWriter, request *http.Request) {
 	results := make([]middlewareRepresentation, 0, len(h.runtimeConfiguration.Middlewares))
 
@@ -217,6 +250,66 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// purgeMiddlewareCache purges the entries of the URL given in the url query parameter from a cache middleware,
+// or all its entries if there is no url query parameter.
+func (h Handler) purgeMiddlewareCache(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	if err := middleware.PurgeCache(request.URL.Query().Get("url")); err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// purgeRouterCache purges the entries of the URL given in the url query parameter from the cache middlewares of a router,
+// or all their entries if there is no url query parameter.
+func (h Handler) purgeRouterCache(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	router, ok := h.runtimeConfiguration.Routers[routerID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	var purged bool
+	for _, name := range router.Middlewares {
+		if !strings.Contains(name, "@") {
+			name = name + "@" + getProviderName(routerID)
+		}
+
+		middleware, ok := h.runtimeConfiguration.Middlewares[name]
+		if !ok || middleware.Middleware == nil || middleware.Cache == nil {
+			continue
+		}
+
+		if err := middleware.PurgeCache(request.URL.Query().Get("url")); err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		purged = true
+	}
+
+	if !purged {
+		writeError(rw, fmt.Sprintf("no cache middleware on router: %s", routerID), http.StatusBadRequest)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 func keepRouter(name string, item *runtime.RouterInfo, criterion *searchCriterion) bool {
 	if criterion == nil {
 		return true
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
@@ -855,6 +856,241 @@ func TestHandler_HTTP(t *testing.T) {
 	}
 }
 
+func TestHandler_UpdateServiceWeights(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		service         string
+		body            string
+		disabled        bool
+		expectedStatus  int
+		expectedWeights map[string]int
+	}{
+		{
+			desc:            "update weights",
+			service:         "canary@myprovider",
+			body:            `{"v2": 20}`,
+			expectedStatus:  http.StatusOK,
+			expectedWeights: map[string]int{"v2": 20},
+		},
+		{
+			desc:           "endpoint disabled",
+			service:        "canary@myprovider",
+			body:           `{"v2": 20}`,
+			disabled:       true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "unknown service",
+			service:        "nope@myprovider",
+			body:           `{"v2": 20}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "not a weighted service",
+			service:        "bar@myprovider",
+			body:           `{"v2": 20}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "unknown child service",
+			service:        "canary@myprovider",
+			body:           `{"v3": 20}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "negative weight",
+			service:        "canary@myprovider",
+			body:           `{"v2": -1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "invalid body",
+			service:        "canary@myprovider",
+			body:           `v2=20`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			v1Weight, v2Weight := 95, 5
+
+			var updated map[string]int
+			canary := &runtime.ServiceInfo{
+				Service: &dynamic.Service{
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{
+							{Name: "v1", Weight: &v1Weight},
+							{Name: "v2", Weight: &v2Weight},
+						},
+					},
+				},
+			}
+			canary.AddWeightUpdater(func(service string, weight int) error {
+				if updated == nil {
+					updated = make(map[string]int)
+				}
+				updated[service] = weight
+				return nil
+			})
+
+			rtConf := &runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"canary@myprovider": canary,
+					"bar@myprovider": {
+						Service: &dynamic.Service{
+							LoadBalancer: &dynamic.ServersLoadBalancer{},
+						},
+					},
+				},
+			}
+
+			handler := New(static.Configuration{API: &static.API{WeightsUpdate: !test.disabled}, Global: &static.Global{}}, rtConf)
+			router := mux.NewRouter()
+			handler.Append(router)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPut, server.URL+"/api/http/services/"+test.service+"/weights", strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, test.expectedWeights, updated)
+
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result serviceRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, test.expectedWeights, result.Weights)
+		})
+	}
+}
+
+func TestHandler_PurgeCache(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		path           string
+		disabled       bool
+		expectedStatus int
+		expectedPurges []string
+	}{
+		{
+			desc:           "endpoints disabled",
+			path:           "/api/http/middlewares/cache@myprovider/cache",
+			disabled:       true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "purge a middleware",
+			path:           "/api/http/middlewares/cache@myprovider/cache",
+			expectedStatus: http.StatusNoContent,
+			expectedPurges: []string{""},
+		},
+		{
+			desc:           "purge a URL of a middleware",
+			path:           "/api/http/middlewares/cache@myprovider/cache?url=https%3A%2F%2Fexample.com%2Ffoo",
+			expectedStatus: http.StatusNoContent,
+			expectedPurges: []string{"https://example.com/foo"},
+		},
+		{
+			desc:           "unknown middleware",
+			path:           "/api/http/middlewares/nope@myprovider/cache",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "not a cache middleware",
+			path:           "/api/http/middlewares/auth@myprovider/cache",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "purge a URL of a router",
+			path:           "/api/http/routers/foo@myprovider/cache?url=https%3A%2F%2Fexample.com%2Ffoo",
+			expectedStatus: http.StatusNoContent,
+			expectedPurges: []string{"https://example.com/foo"},
+		},
+		{
+			desc:           "unknown router",
+			path:           "/api/http/routers/nope@myprovider/cache",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "router without cache middleware",
+			path:           "/api/http/routers/bar@myprovider/cache",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var purges []string
+			cache := &runtime.MiddlewareInfo{
+				Middleware: &dynamic.Middleware{
+					Cache: &dynamic.Cache{},
+				},
+			}
+			cache.AddCachePurger(func(url string) error {
+				purges = append(purges, url)
+				return nil
+			})
+
+			rtConf := &runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"foo@myprovider": {
+						Router: &dynamic.Router{
+							Service:     "foo-service@myprovider",
+							Middlewares: []string{"auth", "cache"},
+						},
+					},
+					"bar@myprovider": {
+						Router: &dynamic.Router{
+							Service:     "foo-service@myprovider",
+							Middlewares: []string{"auth"},
+						},
+					},
+				},
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"cache@myprovider": cache,
+					"auth@myprovider": {
+						Middleware: &dynamic.Middleware{
+							BasicAuth: &dynamic.BasicAuth{Users: []string{"admin:admin"}},
+						},
+					},
+				},
+			}
+
+			handler := New(static.Configuration{API: &static.API{CachePurge: !test.disabled}, Global: &static.Global{}}, rtConf)
+			router := mux.NewRouter()
+			handler.Append(router)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodDelete, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, test.expectedPurges, purges)
+		})
+	}
+}
+
 func generateHTTPRouters(nbRouters int) map[string]*runtime.RouterInfo {
 	routers := make(map[string]*runtime.RouterInfo, nbRouters)
 	for i := 0; i < nbRouters; i++ {
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+)
+
+func (h Handler) testRule(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	var ruleRequest runtime.RuleTestRequest
+	if err := json.NewDecoder(request.Body).Decode(&ruleRequest); err != nil {
+		writeError(rw, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.staticConfig.EntryPoints[ruleRequest.EntryPoint]; !ok {
+		writeError(rw, fmt.Sprintf("entry point not found: %s", ruleRequest.EntryPoint), http.StatusNotFound)
+		return
+	}
+
+	result, err := h.runtimeConfiguration.TestRule(ruleRequest)
+	if err == runtime.ErrNoRuleTester {
+		writeError(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_TestRule(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		body           string
+		noTester       bool
+		expectedStatus int
+		expected       *runtime.RuleTestResult
+	}{
+		{
+			desc:           "matching router",
+			body:           `{"entryPoint": "web", "host": "foo.bar", "path": "/"}`,
+			expectedStatus: http.StatusOK,
+			expected: &runtime.RuleTestResult{
+				EntryPoint:  "web",
+				Protocol:    "http",
+				Router:      "foo@myprovider",
+				Rule:        "Host(`foo.bar`)",
+				Priority:    15,
+				Middlewares: []string{"auth@myprovider"},
+				Service:     "foo@myprovider",
+			},
+		},
+		{
+			desc:           "unknown entry point",
+			body:           `{"entryPoint": "nope"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "routers not built",
+			body:           `{"entryPoint": "web"}`,
+			noTester:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:           "invalid body",
+			body:           `entryPoint=web`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := &runtime.Configuration{}
+			if !test.noTester {
+				rtConf.SetRuleTester(func(request runtime.RuleTestRequest) (*runtime.RuleTestResult, error) {
+					result := &runtime.RuleTestResult{EntryPoint: request.EntryPoint}
+					if request.Host == "foo.bar" {
+						result.Protocol = "http"
+						result.Router = "foo@myprovider"
+						result.Rule = "Host(`foo.bar`)"
+						result.Priority = 15
+						result.Middlewares = []string{"auth@myprovider"}
+						result.Service = "foo@myprovider"
+					}
+					return result, nil
+				})
+			}
+
+			staticConfig := static.Configuration{
+				API:         &static.API{},
+				Global:      &static.Global{},
+				EntryPoints: static.EntryPoints{"web": {Address: ":80"}},
+			}
+
+			handler := New(staticConfig, rtConf)
+			router := mux.NewRouter()
+			handler.Append(router)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/api/rules/test", "application/json", strings.NewReader(test.body))
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatus, resp.StatusCode)
+
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result runtime.RuleTestResult
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, test.expected, &result)
+		})
+	}
+}
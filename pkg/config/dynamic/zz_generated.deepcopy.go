@@ -101,6 +101,53 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyReplacement) DeepCopyInto(out *BodyReplacement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyReplacement.
+func (in *BodyReplacement) DeepCopy() *BodyReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BodyRewrite) DeepCopyInto(out *BodyRewrite) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = make([]BodyReplacement, len(*in))
+		copy(*out, *in)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = make([]BodyReplacement, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentTypes != nil {
+		in, out := &in.ContentTypes, &out.ContentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BodyRewrite.
+func (in *BodyRewrite) DeepCopy() *BodyRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(BodyRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Buffering) DeepCopyInto(out *Buffering) {
 	*out = *in
@@ -117,6 +164,22 @@ func (in *Buffering) DeepCopy() *Buffering {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cache) DeepCopyInto(out *Cache) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cache.
+func (in *Cache) DeepCopy() *Cache {
+	if in == nil {
+		return nil
+	}
+	out := new(Cache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chain) DeepCopyInto(out *Chain) {
 	*out = *in
@@ -336,6 +399,43 @@ func (in *ForwardAuth) DeepCopy() *ForwardAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForwardingTimeouts) DeepCopyInto(out *ForwardingTimeouts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForwardingTimeouts.
+func (in *ForwardingTimeouts) DeepCopy() *ForwardingTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(ForwardingTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcWeb) DeepCopyInto(out *GrpcWeb) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcWeb.
+func (in *GrpcWeb) DeepCopy() *GrpcWeb {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcWeb)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPConfiguration) DeepCopyInto(out *HTTPConfiguration) {
 	*out = *in
@@ -384,6 +484,21 @@ func (in *HTTPConfiguration) DeepCopyInto(out *HTTPConfiguration) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ServersTransports != nil {
+		in, out := &in.ServersTransports, &out.ServersTransports
+		*out = make(map[string]*ServersTransport, len(*in))
+		for key, val := range *in {
+			var outVal *ServersTransport
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(ServersTransport)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -639,6 +754,11 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(BasicAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BodyRewrite != nil {
+		in, out := &in.BodyRewrite, &out.BodyRewrite
+		*out = new(BodyRewrite)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DigestAuth != nil {
 		in, out := &in.DigestAuth, &out.DigestAuth
 		*out = new(DigestAuth)
@@ -659,6 +779,11 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(Buffering)
 		**out = **in
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(Cache)
+		**out = **in
+	}
 	if in.CircuitBreaker != nil {
 		in, out := &in.CircuitBreaker, &out.CircuitBreaker
 		*out = new(CircuitBreaker)
@@ -669,6 +794,11 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(Compress)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrpcWeb != nil {
+		in, out := &in.GrpcWeb, &out.GrpcWeb
+		*out = new(GrpcWeb)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PassTLSClientCert != nil {
 		in, out := &in.PassTLSClientCert, &out.PassTLSClientCert
 		*out = new(PassTLSClientCert)
@@ -711,6 +841,16 @@ func (in *MirrorService) DeepCopy() *MirrorService {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mirroring) DeepCopyInto(out *Mirroring) {
 	*out = *in
+	if in.MaxBodySize != nil {
+		in, out := &in.MaxBodySize, &out.MaxBodySize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Compare != nil {
+		in, out := &in.Compare, &out.Compare
+		*out = new(MirroringCompare)
+		**out = **in
+	}
 	if in.Mirrors != nil {
 		in, out := &in.Mirrors, &out.Mirrors
 		*out = make([]MirrorService, len(*in))
@@ -729,6 +869,22 @@ func (in *Mirroring) DeepCopy() *Mirroring {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringCompare) DeepCopyInto(out *MirroringCompare) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringCompare.
+func (in *MirroringCompare) DeepCopy() *MirroringCompare {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringCompare)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
 	*out = *in
@@ -750,6 +906,22 @@ func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
+func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyProtocol)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RateLimit) DeepCopyInto(out *RateLimit) {
 	*out = *in
@@ -1001,6 +1173,37 @@ func (in *ServersLoadBalancer) DeepCopy() *ServersLoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServersTransport) DeepCopyInto(out *ServersTransport) {
+	*out = *in
+	if in.RootCAs != nil {
+		in, out := &in.RootCAs, &out.RootCAs
+		*out = make([]tls.FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make(tls.Certificates, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForwardingTimeouts != nil {
+		in, out := &in.ForwardingTimeouts, &out.ForwardingTimeouts
+		*out = new(ForwardingTimeouts)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServersTransport.
+func (in *ServersTransport) DeepCopy() *ServersTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(ServersTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -1061,6 +1264,21 @@ func (in *Sticky) DeepCopyInto(out *Sticky) {
 		*out = new(Cookie)
 		**out = **in
 	}
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(StickyHeader)
+		**out = **in
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(StickyQuery)
+		**out = **in
+	}
+	if in.SourceIP != nil {
+		in, out := &in.SourceIP, &out.SourceIP
+		*out = new(StickySourceIP)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1074,6 +1292,59 @@ func (in *Sticky) DeepCopy() *Sticky {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StickyHeader) DeepCopyInto(out *StickyHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StickyHeader.
+func (in *StickyHeader) DeepCopy() *StickyHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(StickyHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StickyQuery) DeepCopyInto(out *StickyQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StickyQuery.
+func (in *StickyQuery) DeepCopy() *StickyQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(StickyQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StickySourceIP) DeepCopyInto(out *StickySourceIP) {
+	*out = *in
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StickySourceIP.
+func (in *StickySourceIP) DeepCopy() *StickySourceIP {
+	if in == nil {
+		return nil
+	}
+	out := new(StickySourceIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StripPrefix) DeepCopyInto(out *StripPrefix) {
 	*out = *in
@@ -1217,6 +1488,16 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 		*out = make([]TCPServer, len(*in))
 		copy(*out, *in)
 	}
+	if in.Sticky != nil {
+		in, out := &in.Sticky, &out.Sticky
+		*out = new(TCPSticky)
+		**out = **in
+	}
+	if in.ProxyProtocol != nil {
+		in, out := &in.ProxyProtocol, &out.ProxyProtocol
+		*out = new(ProxyProtocol)
+		**out = **in
+	}
 	return
 }
 
@@ -1256,6 +1537,22 @@ func (in *TCPService) DeepCopy() *TCPService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPSticky) DeepCopyInto(out *TCPSticky) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSticky.
+func (in *TCPSticky) DeepCopy() *TCPSticky {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPSticky)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPWRRService) DeepCopyInto(out *TCPWRRService) {
 	*out = *in
@@ -1287,6 +1584,11 @@ func (in *TCPWeightedRoundRobin) DeepCopyInto(out *TCPWeightedRoundRobin) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sticky != nil {
+		in, out := &in.Sticky, &out.Sticky
+		*out = new(TCPSticky)
+		**out = **in
+	}
 	return
 }
 
@@ -1403,6 +1705,36 @@ func (in Users) DeepCopy() Users {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WRRMatch) DeepCopyInto(out *WRRMatch) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WRRMatch.
+func (in *WRRMatch) DeepCopy() *WRRMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(WRRMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WRRService) DeepCopyInto(out *WRRService) {
 	*out = *in
@@ -1411,6 +1743,11 @@ func (in *WRRService) DeepCopyInto(out *WRRService) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(WRRMatch)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3,8 +3,10 @@ package label
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,6 +120,17 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware17.stripprefix.prefixes":                               "foobar, fiibar",
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress":                                           "true",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.request[0].literal":                     "foobar",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.request[0].replacement":                 "fiibar",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.response[0].regex":                      "foobar",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.response[0].replacement":                "fiibar",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.contenttypes":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware20.bodyrewrite.maxbodysize":                            "42",
+		"traefik.http.middlewares.Middleware21.cache.maxsize":                                      "42",
+		"traefik.http.middlewares.Middleware21.cache.maxentrysize":                                 "42",
+		"traefik.http.middlewares.Middleware21.cache.defaultttl":                                   "42s",
+		"traefik.http.middlewares.Middleware21.cache.stalewhilerevalidate":                         "42s",
+		"traefik.http.middlewares.Middleware21.cache.path":                                         "foobar",
 
 		"traefik.http.routers.Router0.entrypoints": "foobar, fiibar",
 		"traefik.http.routers.Router0.middlewares": "foobar, fiibar",
@@ -160,17 +173,21 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.services.Service1.loadbalancer.sticky":                           "false",
 		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":               "fui",
 		"traefik.tcp.routers.Router0.rule":                                             "foobar",
+		"traefik.tcp.routers.Router0.priority":                                         "42",
 		"traefik.tcp.routers.Router0.entrypoints":                                      "foobar, fiibar",
 		"traefik.tcp.routers.Router0.service":                                          "foobar",
 		"traefik.tcp.routers.Router0.tls.passthrough":                                  "false",
 		"traefik.tcp.routers.Router0.tls.options":                                      "foo",
 		"traefik.tcp.routers.Router1.rule":                                             "foobar",
+		"traefik.tcp.routers.Router1.priority":                                         "42",
 		"traefik.tcp.routers.Router1.entrypoints":                                      "foobar, fiibar",
 		"traefik.tcp.routers.Router1.service":                                          "foobar",
 		"traefik.tcp.routers.Router1.tls.options":                                      "foo",
 		"traefik.tcp.routers.Router1.tls.passthrough":                                  "false",
 		"traefik.tcp.services.Service0.loadbalancer.server.Port":                       "42",
 		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":                  "42",
+		"traefik.tcp.services.Service0.loadbalancer.sticky.sourceip":                   "true",
+		"traefik.tcp.services.Service0.loadbalancer.proxyprotocol.version":             "1",
 		"traefik.tcp.services.Service1.loadbalancer.server.Port":                       "42",
 		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":                  "42",
 	}
@@ -186,8 +203,9 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
-					Rule:    "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 					TLS: &dynamic.RouterTCPTLSConfig{
 						Passthrough: false,
 						Options:     "foo",
@@ -198,8 +216,9 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
-					Rule:    "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 					TLS: &dynamic.RouterTCPTLSConfig{
 						Passthrough: false,
 						Options:     "foo",
@@ -215,6 +234,8 @@ func TestDecodeConfiguration(t *testing.T) {
 							},
 						},
 						TerminationDelay: func(i int) *int { return &i }(42),
+						Sticky:           &dynamic.TCPSticky{SourceIP: true},
+						ProxyProtocol:    &dynamic.ProxyProtocol{Version: 1},
 					},
 				},
 				"Service1": {
@@ -381,6 +402,27 @@ func TestDecodeConfiguration(t *testing.T) {
 				"Middleware19": {
 					Compress: &dynamic.Compress{},
 				},
+				"Middleware20": {
+					BodyRewrite: &dynamic.BodyRewrite{
+						Request: []dynamic.BodyReplacement{
+							{Literal: "foobar", Replacement: "fiibar"},
+						},
+						Response: []dynamic.BodyReplacement{
+							{Regex: "foobar", Replacement: "fiibar"},
+						},
+						ContentTypes: []string{"foobar", "fiibar"},
+						MaxBodySize:  42,
+					},
+				},
+				"Middleware21": {
+					Cache: &dynamic.Cache{
+						MaxSize:              42,
+						MaxEntrySize:         42,
+						DefaultTTL:           types.Duration(42 * time.Second),
+						StaleWhileRevalidate: types.Duration(42 * time.Second),
+						Path:                 "foobar",
+					},
+				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
 						MaxRequestBodyBytes:  42,
@@ -592,8 +634,9 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
-					Rule:    "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 					TLS: &dynamic.RouterTCPTLSConfig{
 						Passthrough: false,
 						Options:     "foo",
@@ -604,8 +647,9 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
-					Rule:    "foobar",
+					Service:  "foobar",
+					Rule:     "foobar",
+					Priority: 42,
 					TLS: &dynamic.RouterTCPTLSConfig{
 						Passthrough: false,
 						Options:     "foo",
@@ -620,6 +664,8 @@ func TestEncodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						Sticky:        &dynamic.TCPSticky{SourceIP: true},
+						ProxyProtocol: &dynamic.ProxyProtocol{Version: 1},
 					},
 				},
 				"Service1": {
@@ -784,6 +830,27 @@ func TestEncodeConfiguration(t *testing.T) {
 				"Middleware19": {
 					Compress: &dynamic.Compress{},
 				},
+				"Middleware20": {
+					BodyRewrite: &dynamic.BodyRewrite{
+						Request: []dynamic.BodyReplacement{
+							{Literal: "foobar", Replacement: "fiibar"},
+						},
+						Response: []dynamic.BodyReplacement{
+							{Regex: "foobar", Replacement: "fiibar"},
+						},
+						ContentTypes: []string{"foobar", "fiibar"},
+						MaxBodySize:  42,
+					},
+				},
+				"Middleware21": {
+					Cache: &dynamic.Cache{
+						MaxSize:              42,
+						MaxEntrySize:         42,
+						DefaultTTL:           types.Duration(42 * time.Second),
+						StaleWhileRevalidate: types.Duration(42 * time.Second),
+						Path:                 "foobar",
+					},
+				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
 						MaxRequestBodyBytes:  42,
@@ -1093,6 +1160,17 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.Request[0].Literal":                     "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.Request[0].Replacement":                 "fiibar",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.Response[0].Regex":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.Response[0].Replacement":                "fiibar",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.ContentTypes":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware20.BodyRewrite.MaxBodySize":                            "42",
+		"traefik.HTTP.Middlewares.Middleware21.Cache.MaxSize":                                      "42",
+		"traefik.HTTP.Middlewares.Middleware21.Cache.MaxEntrySize":                                 "42",
+		"traefik.HTTP.Middlewares.Middleware21.Cache.DefaultTTL":                                   "42000000000",
+		"traefik.HTTP.Middlewares.Middleware21.Cache.StaleWhileRevalidate":                         "42000000000",
+		"traefik.HTTP.Middlewares.Middleware21.Cache.Path":                                         "foobar",
 
 		"traefik.HTTP.Routers.Router0.EntryPoints": "foobar, fiibar",
 		"traefik.HTTP.Routers.Router0.Middlewares": "foobar, fiibar",
@@ -1134,18 +1212,22 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
 
-		"traefik.TCP.Routers.Router0.Rule":                       "foobar",
-		"traefik.TCP.Routers.Router0.EntryPoints":                "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                    "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":            "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                "foo",
-		"traefik.TCP.Routers.Router1.Rule":                       "foobar",
-		"traefik.TCP.Routers.Router1.EntryPoints":                "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                    "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":            "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.TCP.Routers.Router0.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router0.Priority":                             "42",
+		"traefik.TCP.Routers.Router0.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                              "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                          "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router1.Priority":                             "42",
+		"traefik.TCP.Routers.Router1.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                              "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                          "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":           "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.Sticky.SourceIP":       "true",
+		"traefik.TCP.Services.Service0.LoadBalancer.ProxyProtocol.Version": "1",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":           "42",
 	}
 
 	for key, val := range expected {
@@ -22,6 +22,10 @@ type Configuration struct {
 	Services    map[string]*ServiceInfo    `json:"services,omitempty"`
 	TCPRouters  map[string]*TCPRouterInfo  `json:"tcpRouters,omitempty"`
 	TCPServices map[string]*TCPServiceInfo `json:"tcpServices,omitempty"`
+
+	ruleTester           func(RuleTestRequest) (*RuleTestResult, error)
+	dynamicConfiguration *dynamic.Configuration
+	reloadHistory        *ReloadHistory
 }
 
 // NewConfig returns a Configuration initialized with the given conf. It never returns nil.
@@ -77,6 +81,27 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 	return runtimeConfig
 }
 
+// SetDynamicConfiguration sets the merged dynamic configuration the configuration was built from.
+// The dynamic configuration must not be modified afterwards.
+func (c *Configuration) SetDynamicConfiguration(conf *dynamic.Configuration) {
+	c.dynamicConfiguration = conf
+}
+
+// DynamicConfiguration returns the merged dynamic configuration the configuration was built from, if known.
+func (c *Configuration) DynamicConfiguration() *dynamic.Configuration {
+	return c.dynamicConfiguration
+}
+
+// SetReloadHistory sets the history of the reloads of the dynamic configuration.
+func (c *Configuration) SetReloadHistory(history *ReloadHistory) {
+	c.reloadHistory = history
+}
+
+// ReloadHistory returns the history of the reloads of the dynamic configuration, if known.
+func (c *Configuration) ReloadHistory() *ReloadHistory {
+	return c.reloadHistory
+}
+
 // PopulateUsedBy populates all the UsedBy lists of the underlying fields of r,
 // based on the relations between the included services, routers, and middlewares.
 func (c *Configuration) PopulateUsedBy() {
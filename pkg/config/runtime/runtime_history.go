@@ -0,0 +1,180 @@
+package runtime
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// Actions of a ConfigurationChange.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeUpdated = "updated"
+)
+
+// ConfigurationChange is the change of an element of the dynamic configuration.
+type ConfigurationChange struct {
+	// Kind is the kind of the element: router, middleware, service, serversTransport, tcpRouter, tcpService, tlsOptions, tlsStore or certificate.
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+// Reload is a reload of the dynamic configuration, triggered by a provider.
+type Reload struct {
+	Provider string                `json:"provider"`
+	Date     time.Time             `json:"date"`
+	Changes  []ConfigurationChange `json:"changes,omitempty"`
+}
+
+// ReloadHistory holds the last reloads of the dynamic configuration.
+type ReloadHistory struct {
+	mu      sync.RWMutex
+	size    int
+	last    *dynamic.Configuration
+	reloads []Reload
+}
+
+// NewReloadHistory creates a ReloadHistory keeping the given number of reloads.
+func NewReloadHistory(size int) *ReloadHistory {
+	return &ReloadHistory{size: size}
+}
+
+// Add records the reload of the merged dynamic configuration, triggered by the provider,
+// with the changes from the previously recorded configuration.
+func (h *ReloadHistory) Add(provider string, conf *dynamic.Configuration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	reload := Reload{
+		Provider: provider,
+		Date:     time.Now(),
+		Changes:  DiffConfigurations(h.last, conf),
+	}
+	h.last = conf
+
+	if h.size <= 0 {
+		return
+	}
+
+	h.reloads = append(h.reloads, reload)
+	if len(h.reloads) > h.size {
+		h.reloads = h.reloads[len(h.reloads)-h.size:]
+	}
+}
+
+// Reloads returns the recorded reloads, the most recent first.
+func (h *ReloadHistory) Reloads() []Reload {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	reloads := make([]Reload, 0, len(h.reloads))
+	for i := len(h.reloads) - 1; i >= 0; i-- {
+		reloads = append(reloads, h.reloads[i])
+	}
+	return reloads
+}
+
+// DiffConfigurations returns the changes of the elements between the previous and the current merged dynamic configurations,
+// sorted by kind and name.
+func DiffConfigurations(previous, current *dynamic.Configuration) []ConfigurationChange {
+	var changes []ConfigurationChange
+
+	diff := func(kind string, previous, current map[string]interface{}) {
+		for name, element := range current {
+			previousElement, ok := previous[name]
+			switch {
+			case !ok:
+				changes = append(changes, ConfigurationChange{Kind: kind, Name: name, Action: ChangeAdded})
+			case !reflect.DeepEqual(previousElement, element):
+				changes = append(changes, ConfigurationChange{Kind: kind, Name: name, Action: ChangeUpdated})
+			}
+		}
+		for name := range previous {
+			if _, ok := current[name]; !ok {
+				changes = append(changes, ConfigurationChange{Kind: kind, Name: name, Action: ChangeRemoved})
+			}
+		}
+	}
+
+	previousElements := configurationElements(previous)
+	currentElements := configurationElements(current)
+	for _, kind := range configurationKinds {
+		diff(kind, previousElements[kind], currentElements[kind])
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return kindIndex(changes[i].Kind) < kindIndex(changes[j].Kind)
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+var configurationKinds = []string{"router", "middleware", "service", "serversTransport", "tcpRouter", "tcpService", "tlsOptions", "tlsStore", "certificate"}
+
+func kindIndex(kind string) int {
+	for i, k := range configurationKinds {
+		if k == kind {
+			return i
+		}
+	}
+	return len(configurationKinds)
+}
+
+// configurationElements returns the elements of the configuration by kind and name.
+func configurationElements(conf *dynamic.Configuration) map[string]map[string]interface{} {
+	elements := make(map[string]map[string]interface{})
+	for _, kind := range configurationKinds {
+		elements[kind] = make(map[string]interface{})
+	}
+
+	if conf == nil {
+		return elements
+	}
+
+	if conf.HTTP != nil {
+		for name, router := range conf.HTTP.Routers {
+			elements["router"][name] = router
+		}
+		for name, middleware := range conf.HTTP.Middlewares {
+			elements["middleware"][name] = middleware
+		}
+		for name, service := range conf.HTTP.Services {
+			elements["service"][name] = service
+		}
+		for name, transport := range conf.HTTP.ServersTransports {
+			elements["serversTransport"][name] = transport
+		}
+	}
+
+	if conf.TCP != nil {
+		for name, router := range conf.TCP.Routers {
+			elements["tcpRouter"][name] = router
+		}
+		for name, service := range conf.TCP.Services {
+			elements["tcpService"][name] = service
+		}
+	}
+
+	if conf.TLS != nil {
+		for name, options := range conf.TLS.Options {
+			elements["tlsOptions"][name] = options
+		}
+		for name, store := range conf.TLS.Stores {
+			elements["tlsStore"][name] = store
+		}
+		for _, cert := range conf.TLS.Certificates {
+			elements["certificate"][strings.TrimSpace(cert.GetTruncatedCertificateName())] = cert
+		}
+	}
+
+	return elements
+}
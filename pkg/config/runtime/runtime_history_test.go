@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigurations(t *testing.T) {
+	previous := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {Rule: "Host(`foo.bar`)", Service: "foo"},
+				"bar@file": {Rule: "Host(`bar.foo`)", Service: "bar"},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@file": {LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: "http://10.0.0.1"}}}},
+			},
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Options: map[string]tls.Options{
+				"default": {MinVersion: "VersionTLS12"},
+			},
+		},
+	}
+
+	current := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file":   {Rule: "Host(`foo.bar`)", Service: "foo"},
+				"baz@docker": {Rule: "Host(`baz.foo`)", Service: "baz"},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@file": {LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: "http://10.0.0.2"}}}},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"db@file": {Rule: "HostSNI(`*`)", Service: "db"},
+			},
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Options: map[string]tls.Options{
+				"default": {MinVersion: "VersionTLS13"},
+			},
+		},
+	}
+
+	expected := []ConfigurationChange{
+		{Kind: "router", Name: "bar@file", Action: ChangeRemoved},
+		{Kind: "router", Name: "baz@docker", Action: ChangeAdded},
+		{Kind: "service", Name: "foo@file", Action: ChangeUpdated},
+		{Kind: "tcpRouter", Name: "db@file", Action: ChangeAdded},
+		{Kind: "tlsOptions", Name: "default", Action: ChangeUpdated},
+	}
+
+	assert.Equal(t, expected, DiffConfigurations(previous, current))
+	assert.Nil(t, DiffConfigurations(current, current.DeepCopy()))
+}
+
+func TestReloadHistory(t *testing.T) {
+	history := NewReloadHistory(2)
+
+	history.Add("file", &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{Routers: map[string]*dynamic.Router{"foo@file": {Service: "foo"}}},
+	})
+	history.Add("docker", &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{Routers: map[string]*dynamic.Router{"foo@file": {Service: "foo"}, "bar@docker": {Service: "bar"}}},
+	})
+	history.Add("file", &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{Routers: map[string]*dynamic.Router{"bar@docker": {Service: "bar"}}},
+	})
+
+	reloads := history.Reloads()
+	require.Len(t, reloads, 2)
+
+	assert.Equal(t, "file", reloads[0].Provider)
+	assert.Equal(t, []ConfigurationChange{{Kind: "router", Name: "foo@file", Action: ChangeRemoved}}, reloads[0].Changes)
+
+	assert.Equal(t, "docker", reloads[1].Provider)
+	assert.Equal(t, []ConfigurationChange{{Kind: "router", Name: "bar@docker", Action: ChangeAdded}}, reloads[1].Changes)
+
+	assert.False(t, reloads[0].Date.Before(reloads[1].Date))
+}
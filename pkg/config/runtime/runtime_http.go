@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -113,6 +114,37 @@ type MiddlewareInfo struct {
 	Err    []string `json:"error,omitempty"`
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers and services using that middleware.
+
+	cachePurgersMu sync.RWMutex
+	cachePurgers   []func(url string) error
+}
+
+// AddCachePurger registers a function purging the entries of a cache middleware,
+// for one of the handlers built from it.
+func (m *MiddlewareInfo) AddCachePurger(purger func(url string) error) {
+	m.cachePurgersMu.Lock()
+	defer m.cachePurgersMu.Unlock()
+
+	m.cachePurgers = append(m.cachePurgers, purger)
+}
+
+// PurgeCache purges the entries of the given URL from a cache middleware, or all its entries if url is empty.
+// It is the responsibility of the caller to check that m is not nil.
+func (m *MiddlewareInfo) PurgeCache(url string) error {
+	if m.Middleware == nil || m.Cache == nil {
+		return errors.New("not a cache middleware")
+	}
+
+	m.cachePurgersMu.RLock()
+	defer m.cachePurgersMu.RUnlock()
+
+	for _, purge := range m.cachePurgers {
+		if err := purge(url); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -149,6 +181,10 @@ type ServiceInfo struct {
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
+
+	weightsMu      sync.RWMutex
+	weights        map[string]int // keyed by child service name
+	weightUpdaters []func(service string, weight int) error
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -200,3 +236,73 @@ func (s *ServiceInfo) GetAllStatus() map[string]string {
 	}
 	return allStatus
 }
+
+// AddWeightUpdater registers a function updating the weights of the children services of a weighted service,
+// for one of the handlers built from it.
+func (s *ServiceInfo) AddWeightUpdater(updater func(service string, weight int) error) {
+	s.weightsMu.Lock()
+	defer s.weightsMu.Unlock()
+
+	s.weightUpdaters = append(s.weightUpdaters, updater)
+}
+
+// UpdateWeights updates at runtime, without configuration reload, the weights of the given children services of a weighted service.
+// The children services are named as in the configuration of the weighted service.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateWeights(weights map[string]int) error {
+	if s.Service == nil || s.Weighted == nil {
+		return errors.New("not a weighted service")
+	}
+
+	for name, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("invalid weight %d for %s: it must not be negative", weight, name)
+		}
+
+		var found bool
+		for _, service := range s.Weighted.Services {
+			if service.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown service %s in the weighted service", name)
+		}
+	}
+
+	s.weightsMu.Lock()
+	defer s.weightsMu.Unlock()
+
+	for name, weight := range weights {
+		for _, update := range s.weightUpdaters {
+			if err := update(name, weight); err != nil {
+				return err
+			}
+		}
+
+		if s.weights == nil {
+			s.weights = make(map[string]int)
+		}
+		s.weights[name] = weight
+	}
+
+	return nil
+}
+
+// GetWeights returns the weights of the children services updated at runtime.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetWeights() map[string]int {
+	s.weightsMu.RLock()
+	defer s.weightsMu.RUnlock()
+
+	if len(s.weights) == 0 {
+		return nil
+	}
+
+	weights := make(map[string]int, len(s.weights))
+	for k, v := range s.weights {
+		weights[k] = v
+	}
+	return weights
+}
@@ -0,0 +1,64 @@
+package runtime
+
+import "errors"
+
+// RuleTestRequest is a synthetic request, or connection, evaluated against the routers of an entry point.
+type RuleTestRequest struct {
+	EntryPoint string            `json:"entryPoint"`
+	Method     string            `json:"method,omitempty"`
+	Host       string            `json:"host,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	// SNI is the server name sent in the TLS ClientHello. Setting it makes the request a TLS one.
+	SNI string `json:"sni,omitempty"`
+	// TLS tells whether the request is sent over TLS, without SNI if none is set.
+	TLS      bool   `json:"tls,omitempty"`
+	ClientIP string `json:"clientIP,omitempty"`
+	// Verbose lists, in the result, the routers evaluated before the matching one and why they were rejected.
+	Verbose bool `json:"verbose,omitempty"`
+}
+
+// RuleTestResult describes how a RuleTestRequest is routed.
+type RuleTestResult struct {
+	EntryPoint string `json:"entryPoint"`
+	TLS        bool   `json:"tls"`
+	// RedirectTo is the entry point the request is redirected to, if no router matches it and the entry point redirects its non-TLS requests.
+	RedirectTo string `json:"redirectTo,omitempty"`
+	// Protocol is the protocol of the matching router: http or tcp.
+	Protocol    string   `json:"protocol,omitempty"`
+	Router      string   `json:"router,omitempty"`
+	Rule        string   `json:"rule,omitempty"`
+	Priority    int      `json:"priority,omitempty"`
+	Middlewares []string `json:"middlewares,omitempty"`
+	Service     string   `json:"service,omitempty"`
+	// Routers are the routers evaluated, in order, when the request is verbose.
+	Routers []RuleTestRouter `json:"routers,omitempty"`
+}
+
+// RuleTestRouter is the evaluation of a router against a RuleTestRequest.
+type RuleTestRouter struct {
+	Name     string   `json:"name"`
+	Protocol string   `json:"protocol"`
+	Rule     string   `json:"rule"`
+	Priority int      `json:"priority"`
+	Matched  bool     `json:"matched"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// SetRuleTester sets the function evaluating the synthetic requests against the routers built from the configuration.
+// It must be called once the routers are built, before the configuration is served by the API.
+func (c *Configuration) SetRuleTester(tester func(RuleTestRequest) (*RuleTestResult, error)) {
+	c.ruleTester = tester
+}
+
+// TestRule evaluates the synthetic request against the routers built from the configuration.
+func (c *Configuration) TestRule(request RuleTestRequest) (*RuleTestResult, error) {
+	if c.ruleTester == nil {
+		return nil, ErrNoRuleTester
+	}
+
+	return c.ruleTester(request)
+}
+
+// ErrNoRuleTester is returned when the routers built from the configuration are not available.
+var ErrNoRuleTester = errors.New("the routers are not built yet")
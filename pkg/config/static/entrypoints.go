@@ -1,11 +1,19 @@
 package static
 
+import (
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/types"
+)
+
 // EntryPoint holds the entry point configuration.
 type EntryPoint struct {
 	Address          string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
 	Transport        *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty"`
 	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty"`
 	ForwardedHeaders *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty"`
+	HTTP             *HTTPConfig           `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
+	HTTP3            *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -15,6 +23,58 @@ func (e *EntryPoint) SetDefaults() {
 	e.ForwardedHeaders = &ForwardedHeaders{}
 }
 
+// GetAddress returns the network and the address the entry point listens on:
+// "unix" and the path of the socket for a unix:///path address,
+// "systemd" and the name of the socket passed by systemd for a systemd:name address,
+// and "tcp" and the address as-is otherwise.
+func (e *EntryPoint) GetAddress() (string, string) {
+	switch {
+	case strings.HasPrefix(e.Address, "unix://"):
+		return "unix", strings.TrimPrefix(e.Address, "unix://")
+	case strings.HasPrefix(e.Address, "systemd:"):
+		return "systemd", strings.TrimPrefix(e.Address, "systemd:")
+	default:
+		return "tcp", e.Address
+	}
+}
+
+// HTTPConfig holds the defaults applied to the HTTP routers of an entry point.
+type HTTPConfig struct {
+	Redirections *Redirections `description:"Set of redirection." json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
+	Middlewares  []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	TLS          *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" export:"true"`
+}
+
+// Redirections is a set of redirection for an entry point.
+type Redirections struct {
+	EntryPoint *RedirectEntryPoint `description:"Set of redirection for an entry point." json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty" export:"true"`
+}
+
+// RedirectEntryPoint is the definition of an entry point redirection.
+type RedirectEntryPoint struct {
+	To        string `description:"Targeted entry point of the redirection." json:"to,omitempty" toml:"to,omitempty" yaml:"to,omitempty" export:"true"`
+	Scheme    string `description:"Scheme used for the redirection." json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
+	Permanent bool   `description:"Applies a permanent redirection." json:"permanent,omitempty" toml:"permanent,omitempty" yaml:"permanent,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *RedirectEntryPoint) SetDefaults() {
+	r.Scheme = "https"
+	r.Permanent = true
+}
+
+// TLSConfig is the default TLS configuration for the routers linked to an entry point.
+type TLSConfig struct {
+	Options      string         `description:"Default TLS options for the routers linked to the entry point." json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
+	CertResolver string         `description:"Default certificate resolver for the routers linked to the entry point." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
+	Domains      []types.Domain `description:"Default TLS domains for the routers linked to the entry point." json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+}
+
+// HTTP3Config holds the HTTP/3 configuration of an entry point.
+type HTTP3Config struct {
+	AdvertisedPort int `description:"UDP port to advertise, on which HTTP/3 is available. If zero, the port of the entry point is advertised." json:"advertisedPort,omitempty" toml:"advertisedPort,omitempty" yaml:"advertisedPort,omitempty" export:"true"`
+}
+
 // ForwardedHeaders Trust client forwarding headers.
 type ForwardedHeaders struct {
 	Insecure   bool     `description:"Trust all forwarded headers." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
@@ -32,8 +92,14 @@ type EntryPoints map[string]*EntryPoint
 
 // EntryPointsTransport configures communication between clients and Traefik.
 type EntryPointsTransport struct {
-	LifeCycle          *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
-	RespondingTimeouts *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	LifeCycle           *LifeCycle           `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
+	RespondingTimeouts  *RespondingTimeouts  `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	ClientHelloTimeout  types.Duration       `description:"ClientHelloTimeout is the maximum duration for reading the first bytes of a connection, which are used to route it. If zero, no timeout is set." json:"clientHelloTimeout,omitempty" toml:"clientHelloTimeout,omitempty" yaml:"clientHelloTimeout,omitempty" export:"true"`
+	MaxConnections      int                  `description:"Maximum number of concurrent connections on the entry point. If zero, the connections are not limited." json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty" export:"true"`
+	ConnectionRateLimit *ConnectionRateLimit `description:"Limits the rate of the connections accepted from each source IP." json:"connectionRateLimit,omitempty" toml:"connectionRateLimit,omitempty" yaml:"connectionRateLimit,omitempty" export:"true"`
+	MaxHeaderBytes      int                  `description:"Maximum size of the request headers, in bytes. If zero, 1MB is used." json:"maxHeaderBytes,omitempty" toml:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" export:"true"`
+	MaxRequestBodyBytes int64                `description:"Maximum size of the request bodies, in bytes, above which the requests are answered with a 413 status code. If zero, the global maxRequestBodyBytes is used. If negative, the bodies are not limited." json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	HTTP2               *HTTP2Config         `description:"HTTP/2 configuration." json:"http2,omitempty" toml:"http2,omitempty" yaml:"http2,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -42,4 +108,29 @@ func (t *EntryPointsTransport) SetDefaults() {
 	t.LifeCycle.SetDefaults()
 	t.RespondingTimeouts = &RespondingTimeouts{}
 	t.RespondingTimeouts.SetDefaults()
+	t.HTTP2 = &HTTP2Config{}
+	t.HTTP2.SetDefaults()
+}
+
+// ConnectionRateLimit holds the rate limit of the connections accepted from each source IP.
+type ConnectionRateLimit struct {
+	Average int64 `description:"Maximum average number of connections per second accepted from a source IP. If zero, the connections are not limited." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst   int64 `description:"Maximum number of connections accepted from a source IP in a burst." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ConnectionRateLimit) SetDefaults() {
+	c.Burst = 1
+}
+
+// HTTP2Config holds the HTTP/2 configuration of an entry point.
+type HTTP2Config struct {
+	Enabled              bool  `description:"Enables HTTP/2, over TLS and in clear text (h2c)." json:"enabled" toml:"enabled,omitempty" yaml:"enabled,omitempty" export:"true"`
+	MaxConcurrentStreams int32 `description:"Maximum number of concurrent streams per HTTP/2 connection." json:"maxConcurrentStreams,omitempty" toml:"maxConcurrentStreams,omitempty" yaml:"maxConcurrentStreams,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *HTTP2Config) SetDefaults() {
+	c.Enabled = true
+	c.MaxConcurrentStreams = 250
 }
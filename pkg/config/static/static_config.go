@@ -13,6 +13,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/provider/docker"
 	"github.com/containous/traefik/v2/pkg/provider/file"
 	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway"
 	"github.com/containous/traefik/v2/pkg/provider/kubernetes/ingress"
 	"github.com/containous/traefik/v2/pkg/provider/marathon"
 	"github.com/containous/traefik/v2/pkg/provider/rancher"
@@ -72,8 +73,9 @@ type CertificateResolver struct {
 
 // Global holds the global configuration.
 type Global struct {
-	CheckNewVersion    bool `description:"Periodically check if a new version has been released." json:"checkNewVersion,omitempty" toml:"checkNewVersion,omitempty" yaml:"checkNewVersion,omitempty" label:"allowEmpty" export:"true"`
-	SendAnonymousUsage bool `description:"Periodically send anonymous usage statistics. If the option is not specified, it will be enabled by default." json:"sendAnonymousUsage,omitempty" toml:"sendAnonymousUsage,omitempty" yaml:"sendAnonymousUsage,omitempty" label:"allowEmpty" export:"true"`
+	CheckNewVersion     bool  `description:"Periodically check if a new version has been released." json:"checkNewVersion,omitempty" toml:"checkNewVersion,omitempty" yaml:"checkNewVersion,omitempty" label:"allowEmpty" export:"true"`
+	SendAnonymousUsage  bool  `description:"Periodically send anonymous usage statistics. If the option is not specified, it will be enabled by default." json:"sendAnonymousUsage,omitempty" toml:"sendAnonymousUsage,omitempty" yaml:"sendAnonymousUsage,omitempty" label:"allowEmpty" export:"true"`
+	MaxRequestBodyBytes int64 `description:"Maximum size of the request bodies, in bytes, on the entry points not setting their own. If zero, the bodies are not limited." json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
 }
 
 // ServersTransport options to configure communication between Traefik and the servers
@@ -86,9 +88,12 @@ type ServersTransport struct {
 
 // API holds the API configuration
 type API struct {
-	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
-	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Insecure          bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Dashboard         bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
+	Debug             bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	WeightsUpdate     bool `description:"Enable the endpoint updating at runtime the weights of the weighted services." json:"weightsUpdate,omitempty" toml:"weightsUpdate,omitempty" yaml:"weightsUpdate,omitempty" export:"true"`
+	CachePurge        bool `description:"Enable the endpoints purging the cache middlewares." json:"cachePurge,omitempty" toml:"cachePurge,omitempty" yaml:"cachePurge,omitempty" export:"true"`
+	ReloadHistorySize int  `description:"Number of reloads of the dynamic configuration kept in the history." json:"reloadHistorySize,omitempty" toml:"reloadHistorySize,omitempty" yaml:"reloadHistorySize,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-"`
@@ -97,13 +102,15 @@ type API struct {
 // SetDefaults sets the default values.
 func (a *API) SetDefaults() {
 	a.Dashboard = true
+	a.ReloadHistorySize = 10
 }
 
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
 type RespondingTimeouts struct {
-	ReadTimeout  types.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
-	WriteTimeout types.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
-	IdleTimeout  types.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	ReadTimeout       types.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
+	WriteTimeout      types.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
+	IdleTimeout       types.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	ReadHeaderTimeout types.Duration `description:"ReadHeaderTimeout is the maximum duration for reading the request headers. If zero, the ReadTimeout is used." json:"readHeaderTimeout,omitempty" toml:"readHeaderTimeout,omitempty" yaml:"readHeaderTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -160,6 +167,7 @@ type Providers struct {
 	Marathon                  *marathon.Provider      `description:"Enable Marathon backend with default settings." json:"marathon,omitempty" toml:"marathon,omitempty" yaml:"marathon,omitempty" export:"true" label:"allowEmpty"`
 	KubernetesIngress         *ingress.Provider       `description:"Enable Kubernetes backend with default settings." json:"kubernetesIngress,omitempty" toml:"kubernetesIngress,omitempty" yaml:"kubernetesIngress,omitempty" export:"true" label:"allowEmpty"`
 	KubernetesCRD             *crd.Provider           `description:"Enable Kubernetes backend with default settings." json:"kubernetesCRD,omitempty" toml:"kubernetesCRD,omitempty" yaml:"kubernetesCRD,omitempty" export:"true" label:"allowEmpty"`
+	KubernetesGateway         *gateway.Provider       `description:"Enable Kubernetes Gateway API provider with default settings." json:"kubernetesGateway,omitempty" toml:"kubernetesGateway,omitempty" yaml:"kubernetesGateway,omitempty" export:"true" label:"allowEmpty"`
 	Rest                      *rest.Provider          `description:"Enable Rest backend with default settings." json:"rest,omitempty" toml:"rest,omitempty" yaml:"rest,omitempty" export:"true" label:"allowEmpty"`
 	Rancher                   *rancher.Provider       `description:"Enable Rancher backend with default settings." json:"rancher,omitempty" toml:"rancher,omitempty" yaml:"rancher,omitempty" export:"true" label:"allowEmpty"`
 	ConsulCatalog             *consulcatalog.Provider `description:"Enable ConsulCatalog backend with default settings." json:"consulCatalog,omitempty" toml:"consulCatalog,omitempty" yaml:"consulCatalog,omitempty"`
@@ -187,6 +195,15 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		}
 	}
 
+	// The entry points not setting their own limit inherit the global one.
+	if c.Global != nil && c.Global.MaxRequestBodyBytes > 0 {
+		for _, entryPoint := range c.EntryPoints {
+			if entryPoint.Transport != nil && entryPoint.Transport.MaxRequestBodyBytes == 0 {
+				entryPoint.Transport.MaxRequestBodyBytes = c.Global.MaxRequestBodyBytes
+			}
+		}
+	}
+
 	if c.Providers.Docker != nil {
 		if c.Providers.Docker.SwarmModeRefreshSeconds <= 0 {
 			c.Providers.Docker.SwarmModeRefreshSeconds = types.Duration(15 * time.Second)
@@ -199,6 +216,14 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		}
 	}
 
+	if c.Providers.KubernetesGateway != nil {
+		entryPoints := make(map[string]gateway.Entrypoint)
+		for epName, entryPoint := range c.EntryPoints {
+			entryPoints[epName] = gateway.Entrypoint{Address: entryPoint.Address}
+		}
+		c.Providers.KubernetesGateway.EntryPoints = entryPoints
+	}
+
 	c.initACMEProvider()
 }
 
@@ -230,6 +255,17 @@ func (c *Configuration) ValidateConfiguration() error {
 		acmeEmail = resolver.ACME.Email
 	}
 
+	for name, entryPoint := range c.EntryPoints {
+		if entryPoint.HTTP == nil || entryPoint.HTTP.Redirections == nil || entryPoint.HTTP.Redirections.EntryPoint == nil {
+			continue
+		}
+
+		to := entryPoint.HTTP.Redirections.EntryPoint.To
+		if _, ok := c.EntryPoints[to]; !ok || to == name {
+			return fmt.Errorf("unable to redirect the entry point %q to the entry point %q: the target must be another existing entry point", name, to)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,105 @@
+package hashring
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// pointsPerWeight is the number of points a node of weight 1 gets on the ring.
+const pointsPerWeight = 160
+
+// Ring is a consistent hash ring.
+// Each node gets a number of points on the ring proportional to its weight,
+// and a key belongs to the first node found clockwise from the hash of the key,
+// so that adding or removing a node only remaps the keys of this node.
+// It is safe for concurrent use.
+type Ring struct {
+	lock    sync.RWMutex
+	weights map[string]int
+	hashes  []uint32
+	nodes   map[uint32]string
+}
+
+// New creates an empty ring.
+func New() *Ring {
+	return &Ring{
+		weights: make(map[string]int),
+		nodes:   make(map[uint32]string),
+	}
+}
+
+// Add adds the node to the ring, or updates its weight.
+// A node with a weight lower than 1 never gets any key.
+func (r *Ring) Add(node string, weight int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.weights[node] = weight
+	r.build()
+}
+
+// Remove removes the node from the ring.
+func (r *Ring) Remove(node string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.weights[node]; !ok {
+		return
+	}
+
+	delete(r.weights, node)
+	r.build()
+}
+
+// Get returns the node owning the key, and false if the ring has no node.
+func (r *Ring) Get(key string) (string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.nodes[r.hashes[i]], true
+}
+
+// build computes the points of the ring from the node weights.
+// The nodes are processed in order, so that the ring only depends on its nodes, even on hash collisions.
+func (r *Ring) build() {
+	names := make([]string, 0, len(r.weights))
+	for name := range r.weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r.hashes = r.hashes[:0]
+	r.nodes = make(map[uint32]string)
+
+	for _, name := range names {
+		for i := 0; i < r.weights[name]*pointsPerWeight; i++ {
+			h := hash(name + "-" + strconv.Itoa(i))
+			if _, ok := r.nodes[h]; ok {
+				continue
+			}
+
+			r.nodes[h] = name
+			r.hashes = append(r.hashes, h)
+		}
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,91 @@
+package hashring
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing_empty(t *testing.T) {
+	ring := New()
+
+	_, ok := ring.Get("foo")
+	assert.False(t, ok)
+
+	ring.Add("a", 1)
+	ring.Remove("a")
+
+	_, ok = ring.Get("foo")
+	assert.False(t, ok)
+}
+
+func TestRing_stable(t *testing.T) {
+	ring := New()
+	ring.Add("a", 1)
+	ring.Add("b", 1)
+	ring.Add("c", 1)
+
+	other := New()
+	other.Add("c", 1)
+	other.Add("a", 1)
+	other.Add("b", 1)
+
+	for i := 0; i < 100; i++ {
+		key := strconv.Itoa(i)
+
+		node, ok := ring.Get(key)
+		require.True(t, ok)
+
+		otherNode, ok := other.Get(key)
+		require.True(t, ok)
+
+		assert.Equal(t, node, otherNode)
+	}
+}
+
+func TestRing_remove(t *testing.T) {
+	ring := New()
+	ring.Add("a", 1)
+	ring.Add("b", 1)
+	ring.Add("c", 1)
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		before[key], _ = ring.Get(key)
+	}
+
+	ring.Remove("b")
+
+	for key, node := range before {
+		after, ok := ring.Get(key)
+		require.True(t, ok)
+
+		if node == "b" {
+			assert.NotEqual(t, "b", after)
+			continue
+		}
+		// Only the keys of the removed node are remapped.
+		assert.Equal(t, node, after)
+	}
+}
+
+func TestRing_weights(t *testing.T) {
+	ring := New()
+	ring.Add("a", 3)
+	ring.Add("b", 1)
+	ring.Add("zero", 0)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		node, ok := ring.Get(strconv.Itoa(i))
+		require.True(t, ok)
+		counts[node]++
+	}
+
+	assert.Equal(t, 0, counts["zero"])
+	assert.InDelta(t, 7500, counts["a"], 750)
+	assert.InDelta(t, 2500, counts["b"], 750)
+}
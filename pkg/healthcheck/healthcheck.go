@@ -22,6 +22,13 @@ const (
 	serverDown = "DOWN"
 )
 
+const (
+	// ModeHTTP is the mode of the health checks sending HTTP GET requests, expecting a 2xx or 3xx status code.
+	ModeHTTP = "http"
+	// ModeGRPC is the mode of the health checks calling the gRPC health checking protocol.
+	ModeGRPC = "grpc"
+)
+
 var singleton *HealthCheck
 var once sync.Once
 
@@ -41,6 +48,7 @@ type metricsRegistry interface {
 
 // Options are the public health check options.
 type Options struct {
+	Mode      string
 	Headers   map[string]string
 	Hostname  string
 	Scheme    string
@@ -53,7 +61,7 @@ type Options struct {
 }
 
 func (opt Options) String() string {
-	return fmt.Sprintf("[Hostname: %s Headers: %v Path: %s Port: %d Interval: %s Timeout: %s]", opt.Hostname, opt.Headers, opt.Path, opt.Port, opt.Interval, opt.Timeout)
+	return fmt.Sprintf("[Mode: %s Hostname: %s Headers: %v Path: %s Port: %d Interval: %s Timeout: %s]", opt.Mode, opt.Hostname, opt.Headers, opt.Path, opt.Port, opt.Interval, opt.Timeout)
 }
 
 type backendURL struct {
@@ -69,7 +77,17 @@ type BackendConfig struct {
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
-	u, err := serverURL.Parse(b.Path)
+	u, err := b.healthCheckURL(serverURL, b.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+}
+
+// healthCheckURL returns the URL of the given path on the server, with the scheme and port overrides.
+func (b *BackendConfig) healthCheckURL(serverURL *url.URL, path string) (*url.URL, error) {
+	u, err := serverURL.Parse(path)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +100,7 @@ func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
 		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(b.Port))
 	}
 
-	return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	return u, nil
 }
 
 // this function adds additional http headers and hostname to http.request
@@ -102,20 +120,34 @@ type HealthCheck struct {
 	Backends map[string]*BackendConfig
 	metrics  metricsRegistry
 	cancel   context.CancelFunc
+	// running tracks the health check goroutines of the current backends.
+	running sync.WaitGroup
 }
 
-// SetBackendsConfiguration set backends configuration
+// SetBackendsConfiguration set backends configuration.
+// The servers disabled by the previous health checks of a load-balancer which is still in use stay disabled until they recover.
 func (hc *HealthCheck) SetBackendsConfiguration(parentCtx context.Context, backends map[string]*BackendConfig) {
-	hc.Backends = backends
 	if hc.cancel != nil {
 		hc.cancel()
+		// The servers disabled by the previous health checks are only final once their goroutines have returned.
+		hc.running.Wait()
+	}
+
+	for name, backend := range backends {
+		if previous, ok := hc.Backends[name]; ok && previous.LB == backend.LB {
+			backend.disabledURLs = previous.disabledURLs
+		}
 	}
+
+	hc.Backends = backends
 	ctx, cancel := context.WithCancel(parentCtx)
 	hc.cancel = cancel
 
 	for _, backend := range backends {
 		currentBackend := backend
+		hc.running.Add(1)
 		safe.Go(func() {
+			defer hc.running.Done()
 			hc.execute(ctx, currentBackend)
 		})
 	}
@@ -146,9 +178,21 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 	enabledURLs := backend.LB.Servers()
 	var newDisabledURLs []backendURL
 	// FIXME re enable metrics
-	for _, disableURL := range backend.disabledURLs {
+	for i, disableURL := range backend.disabledURLs {
+		// Once canceled, the remaining servers are kept disabled, for the next health checks of the load-balancer.
+		if ctx.Err() != nil {
+			newDisabledURLs = append(newDisabledURLs, backend.disabledURLs[i:]...)
+			break
+		}
+
 		// FIXME serverUpMetricValue := float64(0)
-		if err := checkHealth(disableURL.url, backend); err == nil {
+		err := checkHealth(ctx, disableURL.url, backend)
+		if ctx.Err() != nil {
+			newDisabledURLs = append(newDisabledURLs, backend.disabledURLs[i:]...)
+			break
+		}
+
+		if err == nil {
 			logger.Warnf("Health check up: Returning to server list. Backend: %q URL: %q Weight: %d",
 				backend.name, disableURL.url.String(), disableURL.weight)
 			if err = backend.LB.UpsertServer(disableURL.url, roundrobin.Weight(disableURL.weight)); err != nil {
@@ -166,8 +210,18 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 
 	// FIXME re enable metrics
 	for _, enableURL := range enabledURLs {
+		if ctx.Err() != nil {
+			return
+		}
+
 		// FIXME serverUpMetricValue := float64(1)
-		if err := checkHealth(enableURL, backend); err != nil {
+		err := checkHealth(ctx, enableURL, backend)
+		if ctx.Err() != nil {
+			// The check was interrupted: the server is not known to be unhealthy.
+			return
+		}
+
+		if err != nil {
 			weight := 1
 			rr, ok := backend.LB.(*roundrobin.RoundRobin)
 			if ok {
@@ -220,13 +274,18 @@ func NewBackendConfig(options Options, backendName string) *BackendConfig {
 
 // checkHealth returns a nil error in case it was successful and otherwise
 // a non-nil error with a meaningful description why the health check failed.
-func checkHealth(serverURL *url.URL, backend *BackendConfig) error {
+// The request is canceled with the given context, so that the health checks of a previous configuration do not outlive it.
+func checkHealth(ctx context.Context, serverURL *url.URL, backend *BackendConfig) error {
+	if backend.Mode == ModeGRPC {
+		return checkHealthGRPC(ctx, serverURL, backend)
+	}
+
 	req, err := backend.newRequest(serverURL)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %s", err)
 	}
 
-	req = backend.addHeadersAndHost(req)
+	req = backend.addHeadersAndHost(req.WithContext(ctx))
 
 	client := http.Client{
 		Timeout:   backend.Options.Timeout,
@@ -252,6 +311,7 @@ func NewLBStatusUpdater(bh BalancerHandler, info *runtime.ServiceInfo) *LbStatus
 	return &LbStatusUpdater{
 		BalancerHandler: bh,
 		serviceInfo:     info,
+		statuses:        make(map[string]string),
 	}
 }
 
@@ -259,15 +319,33 @@ func NewLBStatusUpdater(bh BalancerHandler, info *runtime.ServiceInfo) *LbStatus
 // so it can keep track of the status of a server in the ServiceInfo.
 type LbStatusUpdater struct {
 	BalancerHandler
+	lock        sync.Mutex
 	serviceInfo *runtime.ServiceInfo // can be nil
+	statuses    map[string]string    // keyed by server URL
+}
+
+// SetServiceInfo sets the ServiceInfo keeping track of the status of the servers,
+// when the BalancerHandler is reused by a new runtime configuration, and reports the current statuses in it.
+func (lb *LbStatusUpdater) SetServiceInfo(info *runtime.ServiceInfo) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+
+	lb.serviceInfo = info
+	if info == nil {
+		return
+	}
+
+	for serverURL, status := range lb.statuses {
+		info.UpdateServerStatus(serverURL, status)
+	}
 }
 
 // RemoveServer removes the given server from the BalancerHandler,
 // and updates the status of the server to "DOWN".
 func (lb *LbStatusUpdater) RemoveServer(u *url.URL) error {
 	err := lb.BalancerHandler.RemoveServer(u)
-	if err == nil && lb.serviceInfo != nil {
-		lb.serviceInfo.UpdateServerStatus(u.String(), serverDown)
+	if err == nil {
+		lb.updateServerStatus(u.String(), serverDown)
 	}
 	return err
 }
@@ -276,8 +354,18 @@ func (lb *LbStatusUpdater) RemoveServer(u *url.URL) error {
 // and updates the status of the server to "UP".
 func (lb *LbStatusUpdater) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
 	err := lb.BalancerHandler.UpsertServer(u, options...)
-	if err == nil && lb.serviceInfo != nil {
-		lb.serviceInfo.UpdateServerStatus(u.String(), serverUp)
+	if err == nil {
+		lb.updateServerStatus(u.String(), serverUp)
 	}
 	return err
 }
+
+func (lb *LbStatusUpdater) updateServerStatus(serverURL, status string) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+
+	lb.statuses[serverURL] = status
+	if lb.serviceInfo != nil {
+		lb.serviceInfo.UpdateServerStatus(serverURL, status)
+	}
+}
@@ -0,0 +1,121 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/golang/protobuf/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// grpcHealthCheckPath is the path of the Check method of the standard gRPC health service.
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// maxGRPCResponseSize is the maximum size of the health check response read from the server.
+const maxGRPCResponseSize = 4096
+
+// checkHealthGRPC calls the grpc.health.v1.Health/Check method on the server,
+// and returns a non-nil error if the server is not serving.
+// The call is made through the transport of the backend, hence the server must be reached with HTTP/2 (https or h2c).
+func checkHealthGRPC(ctx context.Context, serverURL *url.URL, backend *BackendConfig) error {
+	u, err := backend.healthCheckURL(serverURL, grpcHealthCheckPath)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	message, err := proto.Marshal(&healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(encodeGRPCFrame(message)))
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC request: %s", err)
+	}
+
+	req = backend.addHeadersAndHost(req.WithContext(ctx))
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+
+	client := http.Client{
+		Timeout:   backend.Options.Timeout,
+		Transport: backend.Options.Transport,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gRPC request failed: %s", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("received error status code: %v", resp.StatusCode)
+	}
+
+	// The body is read up to EOF, for the trailers to be received.
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxGRPCResponseSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read gRPC response: %s", err)
+	}
+	if len(body) > maxGRPCResponseSize {
+		return fmt.Errorf("gRPC response exceeds %d bytes", maxGRPCResponseSize)
+	}
+
+	// The status is sent in the trailers, or in the headers for the trailers-only responses.
+	status := resp.Trailer.Get("Grpc-Status")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+	}
+	if status != "0" {
+		return fmt.Errorf("received gRPC status %q: %s", status, resp.Trailer.Get("Grpc-Message")+resp.Header.Get("Grpc-Message"))
+	}
+
+	message, err = decodeGRPCFrame(body)
+	if err != nil {
+		return fmt.Errorf("failed to read gRPC response: %s", err)
+	}
+
+	healthResp := &healthpb.HealthCheckResponse{}
+	if err := proto.Unmarshal(message, healthResp); err != nil {
+		return fmt.Errorf("failed to decode gRPC response: %s", err)
+	}
+
+	if healthResp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("received gRPC serving status: %v", healthResp.Status)
+	}
+
+	return nil
+}
+
+// encodeGRPCFrame returns the uncompressed gRPC frame holding the given message.
+func encodeGRPCFrame(message []byte) []byte {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// decodeGRPCFrame returns the message of the first gRPC frame of the given data.
+func decodeGRPCFrame(data []byte) ([]byte, error) {
+	if len(data) < 5 {
+		return nil, errors.New("no gRPC message")
+	}
+
+	if data[0] != 0 {
+		return nil, errors.New("compressed gRPC messages are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return nil, errors.New("truncated gRPC message")
+	}
+
+	return data[5 : 5+length], nil
+}
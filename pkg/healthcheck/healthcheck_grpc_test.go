@@ -0,0 +1,157 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckHealthGRPC(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		status        healthpb.HealthCheckResponse_ServingStatus
+		expectedError bool
+	}{
+		{
+			desc:   "serving",
+			status: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			desc:          "not serving",
+			status:        healthpb.HealthCheckResponse_NOT_SERVING,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			healthServer := health.NewServer()
+			healthServer.SetServingStatus("", test.status)
+
+			server := grpc.NewServer()
+			healthpb.RegisterHealthServer(server, healthServer)
+			go func() { _ = server.Serve(listener) }()
+			defer server.Stop()
+
+			serverURL, err := url.Parse("http://" + listener.Addr().String())
+			require.NoError(t, err)
+
+			backend := NewBackendConfig(Options{
+				Mode:    ModeGRPC,
+				Timeout: 5 * time.Second,
+				Transport: &http2.Transport{
+					AllowHTTP: true,
+					DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+						return net.Dial(network, addr)
+					},
+				},
+			}, "backend")
+
+			err = checkHealth(context.Background(), serverURL, backend)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckHealthGRPC_notGRPCServer(t *testing.T) {
+	ts := newTestServer(func() {}, []int{200})
+	defer ts.Close()
+
+	serverURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	backend := NewBackendConfig(Options{
+		Mode:    ModeGRPC,
+		Timeout: 5 * time.Second,
+	}, "backend")
+
+	assert.Error(t, checkHealth(context.Background(), serverURL, backend))
+}
+
+func TestCheckHealthGRPC_trailers(t *testing.T) {
+	serving, err := proto.Marshal(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		body          []byte
+		expectedError string
+	}{
+		{
+			desc: "serving",
+			body: encodeGRPCFrame(serving),
+		},
+		{
+			desc:          "response too large",
+			body:          encodeGRPCFrame(make([]byte, maxGRPCResponseSize)),
+			expectedError: "gRPC response exceeds 4096 bytes",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "application/grpc")
+				rw.Header().Set("Trailer", "Grpc-Status")
+				_, _ = rw.Write(test.body)
+				rw.Header().Set("Grpc-Status", "0")
+			}))
+			defer ts.Close()
+
+			serverURL, err := url.Parse(ts.URL)
+			require.NoError(t, err)
+
+			backend := NewBackendConfig(Options{
+				Mode:    ModeGRPC,
+				Timeout: 5 * time.Second,
+			}, "backend")
+
+			err = checkHealth(context.Background(), serverURL, backend)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeGRPCFrame(t *testing.T) {
+	message, err := decodeGRPCFrame(encodeGRPCFrame([]byte("foo")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo"), message)
+
+	_, err = decodeGRPCFrame([]byte{0, 0, 0, 0, 4, 'f'})
+	assert.Error(t, err)
+
+	_, err = decodeGRPCFrame([]byte{1, 0, 0, 0, 1, 'f'})
+	assert.Error(t, err)
+}
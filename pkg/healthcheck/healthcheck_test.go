@@ -96,11 +96,14 @@ func TestSetBackendsConfiguration(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			// The context is passed to the health check and canonically canceled by
-			// the test server once all expected requests have been received.
+			// The context is passed to the health check, and canceled once the test server
+			// has received all the expected requests and the last one has been handled.
+			// Canceling it from the test server would interrupt the last request.
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
-			ts := newTestServer(cancel, test.healthSequence)
+			received, receivedAll := context.WithCancel(context.Background())
+			defer receivedAll()
+			ts := newTestServer(receivedAll, test.healthSequence)
 			defer ts.Close()
 
 			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
@@ -138,7 +141,14 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			select {
 			case <-time.After(timeout):
 				t.Fatal("test did not complete in time")
-			case <-ctx.Done():
+			case <-received.Done():
+				assert.Eventually(t, func() bool {
+					lb.RLock()
+					defer lb.RUnlock()
+					return lb.numRemovedServers == test.expectedNumRemovedServers && lb.numUpsertedServers == test.expectedNumUpsertedServers
+				}, time.Second, 10*time.Millisecond)
+
+				cancel()
 				wg.Wait()
 			}
 
@@ -153,6 +163,102 @@ func TestSetBackendsConfiguration(t *testing.T) {
 	}
 }
 
+func TestSetBackendsConfiguration_keepsDisabledServers(t *testing.T) {
+	var once sync.Once
+
+	// The first health check disables the server.
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		first := false
+		once.Do(func() { first = true })
+
+		if first {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}, servers: []*url.URL{testhelpers.MustParseURL(ts.URL)}}
+	options := Options{
+		Path:     "/path",
+		Interval: healthCheckInterval,
+		Timeout:  time.Second,
+		LB:       lb,
+	}
+
+	check := newHealthCheck()
+	check.SetBackendsConfiguration(ctx, map[string]*BackendConfig{"backendName": NewBackendConfig(options, "backendName")})
+
+	assert.Eventually(t, func() bool {
+		lb.RLock()
+		defer lb.RUnlock()
+		return lb.numRemovedServers == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	check.SetBackendsConfiguration(ctx, map[string]*BackendConfig{"backendName": NewBackendConfig(options, "backendName")})
+
+	// The server disabled by the previous health check is checked again, and returns to the load-balancer.
+	assert.Eventually(t, func() bool {
+		lb.RLock()
+		defer lb.RUnlock()
+		return lb.numUpsertedServers == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	lb.RLock()
+	defer lb.RUnlock()
+	assert.Equal(t, 1, lb.numRemovedServers)
+}
+
+func TestSetBackendsConfiguration_hangingServer(t *testing.T) {
+	requested := make(chan struct{})
+	var once sync.Once
+
+	// The server does not answer until the health check request is canceled.
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		once.Do(func() { close(requested) })
+		<-req.Context().Done()
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}, servers: []*url.URL{testhelpers.MustParseURL(ts.URL)}}
+	options := Options{
+		Path:     "/path",
+		Interval: time.Minute,
+		Timeout:  10 * time.Second,
+		LB:       lb,
+	}
+
+	check := newHealthCheck()
+	check.SetBackendsConfiguration(ctx, map[string]*BackendConfig{"backendName": NewBackendConfig(options, "backendName")})
+
+	<-requested
+
+	// The reload cancels the pending health check instead of waiting for its timeout.
+	reloaded := make(chan struct{})
+	go func() {
+		check.SetBackendsConfiguration(ctx, map[string]*BackendConfig{"backendName": NewBackendConfig(options, "backendName")})
+		close(reloaded)
+	}()
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reload waited for the pending health check")
+	}
+
+	// The interrupted health check does not disable the server.
+	lb.RLock()
+	defer lb.RUnlock()
+	assert.Equal(t, 0, lb.numRemovedServers)
+}
+
 func TestNewRequest(t *testing.T) {
 	type expected struct {
 		err   bool
@@ -469,3 +575,27 @@ func TestLBStatusUpdater(t *testing.T) {
 		break
 	}
 }
+
+func TestLBStatusUpdater_SetServiceInfo(t *testing.T) {
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	lbsu := NewLBStatusUpdater(lb, &runtime.ServiceInfo{})
+
+	upServer, err := url.Parse("http://foo.com")
+	require.NoError(t, err)
+	downServer, err := url.Parse("http://bar.com")
+	require.NoError(t, err)
+
+	require.NoError(t, lbsu.UpsertServer(upServer, roundrobin.Weight(1)))
+	require.NoError(t, lbsu.UpsertServer(downServer, roundrobin.Weight(1)))
+	require.NoError(t, lbsu.RemoveServer(downServer))
+
+	// The load-balancer is reused by a new runtime configuration.
+	svInfo := &runtime.ServiceInfo{}
+	lbsu.SetServiceInfo(svInfo)
+
+	expected := map[string]string{
+		upServer.String():   serverUp,
+		downServer.String(): serverDown,
+	}
+	assert.Equal(t, expected, svInfo.GetAllStatus())
+}
@@ -30,8 +30,12 @@ const (
 	ddEntryPointReqsName          = "entrypoint.request.total"
 	ddEntryPointReqDurationName   = "entrypoint.request.duration"
 	ddEntryPointOpenConnsName     = "entrypoint.connections.open"
+	ddEntryPointRejectedConnsName = "entrypoint.connections.rejected.total"
 	ddOpenConnsName               = "service.connections.open"
 	ddServerUpName                = "service.server.up"
+	ddMirrorMismatchesTotalName   = "service.mirror.mismatches.total"
+	ddCacheHitsTotalName          = "cache.hits.total"
+	ddCacheMissesTotalName        = "cache.misses.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -45,6 +49,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		configReloadsFailureCounter:  datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
 		lastConfigReloadSuccessGauge: datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge: datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		cacheHitsCounter:             datadogClient.NewCounter(ddCacheHitsTotalName, 1.0),
+		cacheMissesCounter:           datadogClient.NewCounter(ddCacheMissesTotalName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -52,6 +58,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsCounter = datadogClient.NewCounter(ddEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram = datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.entryPointRejectedConnsCounter = datadogClient.NewCounter(ddEntryPointRejectedConnsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -61,6 +68,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceMirrorMismatchCounter = datadogClient.NewCounter(ddMirrorMismatchesTotalName, 1.0)
 	}
 
 	return registry
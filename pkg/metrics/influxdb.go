@@ -36,8 +36,12 @@ const (
 	influxDBEntryPointReqsName          = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqDurationName   = "traefik.entrypoint.request.duration"
 	influxDBEntryPointOpenConnsName     = "traefik.entrypoint.connections.open"
+	influxDBEntryPointRejectedConnsName = "traefik.entrypoint.connections.rejected.total"
 	influxDBOpenConnsName               = "traefik.service.connections.open"
 	influxDBServerUpName                = "traefik.service.server.up"
+	influxDBMirrorMismatchesTotalName   = "traefik.service.mirror.mismatches.total"
+	influxDBCacheHitsTotalName          = "traefik.cache.hits.total"
+	influxDBCacheMissesTotalName        = "traefik.cache.misses.total"
 )
 
 const (
@@ -59,6 +63,8 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		configReloadsFailureCounter:  influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
 		lastConfigReloadSuccessGauge: influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge: influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		cacheHitsCounter:             influxDBClient.NewCounter(influxDBCacheHitsTotalName),
+		cacheMissesCounter:           influxDBClient.NewCounter(influxDBCacheMissesTotalName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -66,6 +72,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
 		registry.entryPointReqDurationHistogram = influxDBClient.NewHistogram(influxDBEntryPointReqDurationName)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.entryPointRejectedConnsCounter = influxDBClient.NewCounter(influxDBEntryPointRejectedConnsName)
 	}
 
 	if config.AddServicesLabels {
@@ -75,6 +82,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServerUpName)
+		registry.serviceMirrorMismatchCounter = influxDBClient.NewCounter(influxDBMirrorMismatchesTotalName)
 	}
 
 	return registry
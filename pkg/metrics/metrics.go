@@ -22,6 +22,7 @@ type Registry interface {
 	EntryPointReqsCounter() metrics.Counter
 	EntryPointReqDurationHistogram() metrics.Histogram
 	EntryPointOpenConnsGauge() metrics.Gauge
+	EntryPointRejectedConnsCounter() metrics.Counter
 
 	// service metrics
 	ServiceReqsCounter() metrics.Counter
@@ -29,6 +30,11 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceMirrorMismatchCounter() metrics.Counter
+
+	// cache metrics
+	CacheHitsCounter() metrics.Counter
+	CacheMissesCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -48,11 +54,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqDurationHistogram []metrics.Histogram
 	var entryPointOpenConnsGauge []metrics.Gauge
+	var entryPointRejectedConnsCounter []metrics.Counter
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqDurationHistogram []metrics.Histogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceMirrorMismatchCounter []metrics.Counter
+	var cacheHitsCounter []metrics.Counter
+	var cacheMissesCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -76,6 +86,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointOpenConnsGauge() != nil {
 			entryPointOpenConnsGauge = append(entryPointOpenConnsGauge, r.EntryPointOpenConnsGauge())
 		}
+		if r.EntryPointRejectedConnsCounter() != nil {
+			entryPointRejectedConnsCounter = append(entryPointRejectedConnsCounter, r.EntryPointRejectedConnsCounter())
+		}
 		if r.ServiceReqsCounter() != nil {
 			serviceReqsCounter = append(serviceReqsCounter, r.ServiceReqsCounter())
 		}
@@ -91,11 +104,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceMirrorMismatchCounter() != nil {
+			serviceMirrorMismatchCounter = append(serviceMirrorMismatchCounter, r.ServiceMirrorMismatchCounter())
+		}
+		if r.CacheHitsCounter() != nil {
+			cacheHitsCounter = append(cacheHitsCounter, r.CacheHitsCounter())
+		}
+		if r.CacheMissesCounter() != nil {
+			cacheMissesCounter = append(cacheMissesCounter, r.CacheMissesCounter())
+		}
 	}
 
 	return &standardRegistry{
 		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0 || len(serviceMirrorMismatchCounter) > 0,
 		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
@@ -103,11 +125,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
 		entryPointReqDurationHistogram: multi.NewHistogram(entryPointReqDurationHistogram...),
 		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
+		entryPointRejectedConnsCounter: multi.NewCounter(entryPointRejectedConnsCounter...),
 		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
 		serviceReqDurationHistogram:    multi.NewHistogram(serviceReqDurationHistogram...),
 		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		serviceMirrorMismatchCounter:   multi.NewCounter(serviceMirrorMismatchCounter...),
+		cacheHitsCounter:               multi.NewCounter(cacheHitsCounter...),
+		cacheMissesCounter:             multi.NewCounter(cacheMissesCounter...),
 	}
 }
 
@@ -121,11 +147,15 @@ type standardRegistry struct {
 	entryPointReqsCounter          metrics.Counter
 	entryPointReqDurationHistogram metrics.Histogram
 	entryPointOpenConnsGauge       metrics.Gauge
+	entryPointRejectedConnsCounter metrics.Counter
 	serviceReqsCounter             metrics.Counter
 	serviceReqDurationHistogram    metrics.Histogram
 	serviceOpenConnsGauge          metrics.Gauge
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	serviceMirrorMismatchCounter   metrics.Counter
+	cacheHitsCounter               metrics.Counter
+	cacheMissesCounter             metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -164,6 +194,10 @@ func (r *standardRegistry) EntryPointOpenConnsGauge() metrics.Gauge {
 	return r.entryPointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntryPointRejectedConnsCounter() metrics.Counter {
+	return r.entryPointRejectedConnsCounter
+}
+
 func (r *standardRegistry) ServiceReqsCounter() metrics.Counter {
 	return r.serviceReqsCounter
 }
@@ -183,3 +217,15 @@ func (r *standardRegistry) ServiceRetriesCounter() metrics.Counter {
 func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
+
+func (r *standardRegistry) ServiceMirrorMismatchCounter() metrics.Counter {
+	return r.serviceMirrorMismatchCounter
+}
+
+func (r *standardRegistry) CacheHitsCounter() metrics.Counter {
+	return r.cacheHitsCounter
+}
+
+func (r *standardRegistry) CacheMissesCounter() metrics.Counter {
+	return r.cacheMissesCounter
+}
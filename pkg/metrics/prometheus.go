@@ -29,21 +29,28 @@ const (
 	configLastReloadSuccessName    = metricConfigPrefix + "last_reload_success"
 	configLastReloadFailureName    = metricConfigPrefix + "last_reload_failure"
 
+	// cache
+	metricCachePrefix    = MetricNamePrefix + "cache_"
+	cacheHitsTotalName   = metricCachePrefix + "hits_total"
+	cacheMissesTotalName = metricCachePrefix + "misses_total"
+
 	// entry point
-	metricEntryPointPrefix    = MetricNamePrefix + "entrypoint_"
-	entryPointReqsTotalName   = metricEntryPointPrefix + "requests_total"
-	entryPointReqDurationName = metricEntryPointPrefix + "request_duration_seconds"
-	entryPointOpenConnsName   = metricEntryPointPrefix + "open_connections"
+	metricEntryPointPrefix           = MetricNamePrefix + "entrypoint_"
+	entryPointReqsTotalName          = metricEntryPointPrefix + "requests_total"
+	entryPointReqDurationName        = metricEntryPointPrefix + "request_duration_seconds"
+	entryPointOpenConnsName          = metricEntryPointPrefix + "open_connections"
+	entryPointRejectedConnsTotalName = metricEntryPointPrefix + "rejected_connections_total"
 
 	// service level.
 
 	// MetricServicePrefix prefix of all service metric names
-	MetricServicePrefix     = MetricNamePrefix + "service_"
-	serviceReqsTotalName    = MetricServicePrefix + "requests_total"
-	serviceReqDurationName  = MetricServicePrefix + "request_duration_seconds"
-	serviceOpenConnsName    = MetricServicePrefix + "open_connections"
-	serviceRetriesTotalName = MetricServicePrefix + "retries_total"
-	serviceServerUpName     = MetricServicePrefix + "server_up"
+	MetricServicePrefix            = MetricNamePrefix + "service_"
+	serviceReqsTotalName           = MetricServicePrefix + "requests_total"
+	serviceReqDurationName         = MetricServicePrefix + "request_duration_seconds"
+	serviceOpenConnsName           = MetricServicePrefix + "open_connections"
+	serviceRetriesTotalName        = MetricServicePrefix + "retries_total"
+	serviceServerUpName            = MetricServicePrefix + "server_up"
+	serviceMirrorMismatchTotalName = MetricServicePrefix + "mirror_mismatches_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -120,11 +127,22 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Help: "Last config reload failure",
 	}, []string{})
 
+	cacheHits := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheHitsTotalName,
+		Help: "How many requests were served from a cache, partitioned by middleware.",
+	}, []string{"middleware"})
+	cacheMisses := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: cacheMissesTotalName,
+		Help: "How many cacheable requests were not served from a cache, partitioned by middleware.",
+	}, []string{"middleware"})
+
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		cacheHits.cv.Describe,
+		cacheMisses.cv.Describe,
 	}
 
 	reg := &standardRegistry{
@@ -134,6 +152,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloadsFailureCounter:  configReloadsFailures,
 		lastConfigReloadSuccessGauge: lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge: lastConfigReloadFailure,
+		cacheHitsCounter:             cacheHits,
+		cacheMissesCounter:           cacheMisses,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -150,15 +170,21 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		entryPointRejectedConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointRejectedConnsTotalName,
+			Help: "How many connections were rejected on an entrypoint before being routed, partitioned by reason.",
+		}, []string{"reason", "entrypoint"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			entryPointRejectedConns.cv.Describe,
 		}...)
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqDurationHistogram = entryPointReqDurations
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.entryPointRejectedConnsCounter = entryPointRejectedConns
 	}
 	if config.AddServicesLabels {
 		serviceReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
@@ -182,6 +208,10 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceMirrorMismatches := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceMirrorMismatchTotalName,
+			Help: "How many mirrored responses differed from the main response, partitioned by mirror and reason.",
+		}, []string{"service", "mirror", "reason"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -189,6 +219,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceMirrorMismatches.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -196,6 +227,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceMirrorMismatchCounter = serviceMirrorMismatches
 	}
 
 	return reg
@@ -116,6 +116,9 @@ func TestPrometheus(t *testing.T) {
 	prometheusRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 	prometheusRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
 
+	prometheusRegistry.CacheHitsCounter().With("middleware", "cache1").Add(1)
+	prometheusRegistry.CacheMissesCounter().With("middleware", "cache1").Add(1)
+
 	prometheusRegistry.
 		EntryPointReqsCounter().
 		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
@@ -128,6 +131,10 @@ func TestPrometheus(t *testing.T) {
 		EntryPointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntryPointRejectedConnsCounter().
+		With("reason", "max_connections", "entrypoint", "http").
+		Add(1)
 
 	prometheusRegistry.
 		ServiceReqsCounter().
@@ -149,6 +156,10 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceMirrorMismatchCounter().
+		With("service", "service1", "mirror", "mirror1", "reason", "status").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -204,6 +215,14 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entryPointOpenConnsName, 1),
 		},
+		{
+			name: entryPointRejectedConnsTotalName,
+			labels: map[string]string{
+				"reason":     "max_connections",
+				"entrypoint": "http",
+			},
+			assert: buildCounterAssert(t, entryPointRejectedConnsTotalName, 1),
+		},
 		{
 			name: serviceReqsTotalName,
 			labels: map[string]string{
@@ -248,6 +267,29 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceMirrorMismatchTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"mirror":  "mirror1",
+				"reason":  "status",
+			},
+			assert: buildCounterAssert(t, serviceMirrorMismatchTotalName, 1),
+		},
+		{
+			name: cacheHitsTotalName,
+			labels: map[string]string{
+				"middleware": "cache1",
+			},
+			assert: buildCounterAssert(t, cacheHitsTotalName, 1),
+		},
+		{
+			name: cacheMissesTotalName,
+			labels: map[string]string{
+				"middleware": "cache1",
+			},
+			assert: buildCounterAssert(t, cacheMissesTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -29,8 +29,12 @@ const (
 	statsdEntryPointReqsName          = "entrypoint.request.total"
 	statsdEntryPointReqDurationName   = "entrypoint.request.duration"
 	statsdEntryPointOpenConnsName     = "entrypoint.connections.open"
+	statsdEntryPointRejectedConnsName = "entrypoint.connections.rejected.total"
 	statsdOpenConnsName               = "service.connections.open"
 	statsdServerUpName                = "service.server.up"
+	statsdMirrorMismatchesTotalName   = "service.mirror.mismatches.total"
+	statsdCacheHitsTotalName          = "cache.hits.total"
+	statsdCacheMissesTotalName        = "cache.misses.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -44,6 +48,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		configReloadsFailureCounter:  statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
 		lastConfigReloadSuccessGauge: statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge: statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		cacheHitsCounter:             statsdClient.NewCounter(statsdCacheHitsTotalName, 1.0),
+		cacheMissesCounter:           statsdClient.NewCounter(statsdCacheMissesTotalName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -51,6 +57,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsCounter = statsdClient.NewCounter(statsdEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram = statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.entryPointRejectedConnsCounter = statsdClient.NewCounter(statsdEntryPointRejectedConnsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -60,6 +67,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServerUpName)
+		registry.serviceMirrorMismatchCounter = statsdClient.NewCounter(statsdMirrorMismatchesTotalName, 1.0)
 	}
 
 	return registry
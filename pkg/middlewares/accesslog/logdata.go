@@ -68,6 +68,8 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// CacheStatus is the map key used for the status of the request in the cache (HIT, STALE, REVALIDATED, MISS or BYPASS).
+	CacheStatus = "CacheStatus"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -108,6 +110,7 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[CacheStatus] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
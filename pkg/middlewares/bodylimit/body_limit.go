@@ -0,0 +1,62 @@
+// Package bodylimit limits the size of the request bodies.
+package bodylimit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned when reading a request body larger than the limit.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// BodyLimit is an HTTP handler wrapper that answers with a 413 status code the requests whose body is larger than the limit.
+// The requests without a Content-Length are read up to the limit, after which reading their body fails with ErrBodyTooLarge.
+type BodyLimit struct {
+	maxBytes int64
+	next     http.Handler
+}
+
+// New creates a new BodyLimit.
+func New(maxBytes int64, next http.Handler) *BodyLimit {
+	return &BodyLimit{
+		maxBytes: maxBytes,
+		next:     next,
+	}
+}
+
+func (b *BodyLimit) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.ContentLength > b.maxBytes {
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &limitedBody{ReadCloser: req.Body, remaining: b.maxBytes}
+	}
+
+	b.next.ServeHTTP(rw, req)
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+
+	// Reads one byte more than the limit, to tell a body of exactly the limit from a larger one.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrBodyTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,77 @@
+package bodylimit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		body          string
+		contentLength int64
+		expectedCode  int
+		expectedBody  string
+		expectedErr   error
+	}{
+		{
+			desc:          "body within the limit",
+			body:          "0123456789",
+			contentLength: 10,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "0123456789",
+		},
+		{
+			desc:          "Content-Length over the limit",
+			body:          "0123456789a",
+			contentLength: 11,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "unknown length within the limit",
+			body:          "0123456789",
+			contentLength: -1,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "0123456789",
+		},
+		{
+			desc:          "unknown length over the limit",
+			body:          "0123456789a",
+			contentLength: -1,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "0123456789",
+			expectedErr:   ErrBodyTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var body string
+			var err error
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				var raw []byte
+				raw, err = ioutil.ReadAll(req.Body)
+				body = string(raw)
+			})
+
+			req := testhelpers.MustNewRequest(http.MethodPost, "http://localhost", ioutil.NopCloser(strings.NewReader(test.body)))
+			req.ContentLength = test.contentLength
+
+			rw := httptest.NewRecorder()
+			New(10, next).ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedBody, body)
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package bodyrewrite
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "BodyRewrite"
+
+	// defaultMaxBodySize is the size of the largest body rewritten when no maxBodySize is configured (1 MiB).
+	defaultMaxBodySize = 1 << 20
+)
+
+var defaultContentTypes = []string{"text/html", "application/json"}
+
+type replacement struct {
+	regex       *regexp.Regexp
+	literal     []byte
+	replacement []byte
+}
+
+func newReplacement(conf dynamic.BodyReplacement) (replacement, error) {
+	switch {
+	case conf.Regex != "" && conf.Literal != "":
+		return replacement{}, errors.New("only one of regex or literal can be set")
+
+	case conf.Regex != "":
+		regex, err := regexp.Compile(conf.Regex)
+		if err != nil {
+			return replacement{}, fmt.Errorf("invalid regex %q: %v", conf.Regex, err)
+		}
+		return replacement{regex: regex, replacement: []byte(conf.Replacement)}, nil
+
+	case conf.Literal != "":
+		return replacement{literal: []byte(conf.Literal), replacement: []byte(conf.Replacement)}, nil
+
+	default:
+		return replacement{}, errors.New("one of regex or literal is required")
+	}
+}
+
+func (r replacement) apply(body []byte) []byte {
+	if r.regex != nil {
+		return r.regex.ReplaceAll(body, r.replacement)
+	}
+	return bytes.ReplaceAll(body, r.literal, r.replacement)
+}
+
+// bodyRewrite is a middleware rewriting the bodies of the requests and of the responses.
+type bodyRewrite struct {
+	next         http.Handler
+	name         string
+	request      []replacement
+	response     []replacement
+	contentTypes []string
+	maxBodySize  int64
+}
+
+// New creates a new body rewrite middleware.
+func New(ctx context.Context, next http.Handler, conf dynamic.BodyRewrite, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(conf.Request) == 0 && len(conf.Response) == 0 {
+		return nil, errors.New("at least one request or response replacement is required")
+	}
+
+	rewrite := &bodyRewrite{
+		next:         next,
+		name:         name,
+		contentTypes: defaultContentTypes,
+		maxBodySize:  conf.MaxBodySize,
+	}
+
+	if rewrite.maxBodySize <= 0 {
+		rewrite.maxBodySize = defaultMaxBodySize
+	}
+
+	for _, replacementConf := range conf.Request {
+		r, err := newReplacement(replacementConf)
+		if err != nil {
+			return nil, err
+		}
+		rewrite.request = append(rewrite.request, r)
+	}
+
+	for _, replacementConf := range conf.Response {
+		r, err := newReplacement(replacementConf)
+		if err != nil {
+			return nil, err
+		}
+		rewrite.response = append(rewrite.response, r)
+	}
+
+	if len(conf.ContentTypes) > 0 {
+		rewrite.contentTypes = nil
+		for _, v := range conf.ContentTypes {
+			mediaType, _, err := mime.ParseMediaType(v)
+			if err != nil {
+				return nil, err
+			}
+			rewrite.contentTypes = append(rewrite.contentTypes, mediaType)
+		}
+	}
+
+	return rewrite, nil
+}
+
+func (b *bodyRewrite) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return b.name, tracing.SpanKindNoneEnum
+}
+
+func (b *bodyRewrite) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), b.name, typeName)
+
+	if len(b.request) > 0 {
+		if err := b.rewriteRequest(req); err != nil {
+			log.FromContext(ctx).Debugf("Error while reading the request body: %v", err)
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if len(b.response) == 0 || req.Method == http.MethodHead {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	writer := &responseWriter{rw: rw, rewrite: b}
+	b.next.ServeHTTP(writer, req)
+	writer.finish(ctx)
+}
+
+// rewriteRequest rewrites the body of the request if it has a rewritable content type and size.
+func (b *bodyRewrite) rewriteRequest(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength > b.maxBodySize {
+		return nil
+	}
+
+	if !b.isRewritable(req.Header) || req.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+
+	original := req.Body
+
+	body, err := ioutil.ReadAll(io.LimitReader(original, b.maxBodySize+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(body)) > b.maxBodySize {
+		// The body is too large: it is forwarded as is.
+		req.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+		return nil
+	}
+
+	body = apply(b.request, body)
+
+	req.Body = &readCloser{Reader: bytes.NewReader(body), Closer: original}
+	req.ContentLength = int64(len(body))
+	req.TransferEncoding = nil
+	if req.Header.Get("Content-Length") != "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	return nil
+}
+
+func (b *bodyRewrite) isRewritable(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	for _, contentType := range b.contentTypes {
+		if contentType == mediaType {
+			return true
+		}
+
+		// Matches all the subtypes of a type, e.g. text/*.
+		if strings.HasSuffix(contentType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(contentType, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func apply(replacements []replacement, body []byte) []byte {
+	for _, r := range replacements {
+		body = r.apply(body)
+	}
+	return body
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseWriter buffers the response while it can be rewritten,
+// and forwards it unchanged as soon as it cannot.
+type responseWriter struct {
+	rw      http.ResponseWriter
+	rewrite *bodyRewrite
+
+	code          int
+	headerWritten bool
+	buffering     bool
+	gzipped       bool
+	buf           bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.headerWritten {
+		return
+	}
+
+	w.headerWritten = true
+	w.code = code
+
+	header := w.rw.Header()
+	encoding := header.Get("Content-Encoding")
+
+	// The partial contents are not rewritten, as their ranges refer to the original body.
+	w.buffering = bodyAllowed(code) && code != http.StatusPartialContent && w.rewrite.isRewritable(header) &&
+		(encoding == "" || encoding == "identity" || encoding == "gzip")
+	w.gzipped = encoding == "gzip"
+
+	if w.buffering {
+		if length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && length > w.rewrite.maxBodySize {
+			w.buffering = false
+		}
+	}
+
+	if !w.buffering {
+		w.rw.WriteHeader(code)
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.buffering {
+		return w.rw.Write(p)
+	}
+
+	if int64(w.buf.Len()+len(p)) > w.rewrite.maxBodySize {
+		// The body is too large: the buffered part is sent, and the rest is forwarded as is.
+		w.buffering = false
+		w.rw.WriteHeader(w.code)
+
+		if _, err := w.rw.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		w.buf.Reset()
+
+		return w.rw.Write(p)
+	}
+
+	return w.buf.Write(p)
+}
+
+// Flush sends the response only if it is not buffered for the rewrite.
+func (w *responseWriter) Flush() {
+	if w.buffering {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+	return hijacker.Hijack()
+}
+
+// finish rewrites and sends the buffered response.
+func (w *responseWriter) finish(ctx context.Context) {
+	if !w.headerWritten || !w.buffering {
+		return
+	}
+
+	body, err := w.rewrittenBody()
+	if err != nil {
+		log.FromContext(ctx).Debugf("Response body not rewritten: %v", err)
+		body = w.buf.Bytes()
+	}
+
+	if !bytes.Equal(body, w.buf.Bytes()) {
+		// The rewritten body is not byte-for-byte the one identified by the validators of the server,
+		// and can't be served by ranges.
+		if etag := w.rw.Header().Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			w.rw.Header().Set("ETag", "W/"+etag)
+		}
+		w.rw.Header().Del("Accept-Ranges")
+	}
+
+	w.rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.rw.WriteHeader(w.code)
+
+	if _, err := w.rw.Write(body); err != nil {
+		log.FromContext(ctx).Debugf("Error while writing the response body: %v", err)
+	}
+}
+
+func (w *responseWriter) rewrittenBody() ([]byte, error) {
+	if !w.gzipped {
+		return apply(w.rewrite.response, w.buf.Bytes()), nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(w.buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(reader, w.rewrite.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > w.rewrite.maxBodySize {
+		return nil, errors.New("the uncompressed body is too large")
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+
+	if _, err := writer.Write(apply(w.rewrite.response, body)); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+// bodyAllowed reports whether a response with the given status code can have a body.
+func bodyAllowed(code int) bool {
+	return code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified
+}
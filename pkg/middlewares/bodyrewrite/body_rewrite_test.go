@@ -0,0 +1,378 @@
+package bodyrewrite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		conf          dynamic.BodyRewrite
+		expectedError bool
+	}{
+		{
+			desc: "literal and regex replacements",
+			conf: dynamic.BodyRewrite{
+				Request:  []dynamic.BodyReplacement{{Literal: "foo", Replacement: "bar"}},
+				Response: []dynamic.BodyReplacement{{Regex: "fo+", Replacement: "bar"}},
+			},
+		},
+		{
+			desc:          "no replacement",
+			conf:          dynamic.BodyRewrite{},
+			expectedError: true,
+		},
+		{
+			desc: "replacement without regex nor literal",
+			conf: dynamic.BodyRewrite{
+				Response: []dynamic.BodyReplacement{{Replacement: "bar"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "replacement with regex and literal",
+			conf: dynamic.BodyRewrite{
+				Response: []dynamic.BodyReplacement{{Regex: "fo+", Literal: "foo", Replacement: "bar"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid regex",
+			conf: dynamic.BodyRewrite{
+				Response: []dynamic.BodyReplacement{{Regex: "fo(", Replacement: "bar"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid content type",
+			conf: dynamic.BodyRewrite{
+				Response:     []dynamic.BodyReplacement{{Literal: "foo", Replacement: "bar"}},
+				ContentTypes: []string{"text/html;;"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "test")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBodyRewrite_response(t *testing.T) {
+	conf := dynamic.BodyRewrite{
+		Response: []dynamic.BodyReplacement{
+			{Regex: `http://legacy\.internal(:\d+)?/`, Replacement: "https://example.com/"},
+			{Literal: "</body>", Replacement: "<script src=\"/snippet.js\"></script></body>"},
+		},
+		ContentTypes: []string{"text/html", "application/*"},
+		MaxBodySize:  100,
+	}
+
+	testCases := []struct {
+		desc         string
+		method       string
+		contentType  string
+		encoding     string
+		body         string
+		flush        bool
+		expectedBody string
+	}{
+		{
+			desc:         "html",
+			contentType:  "text/html; charset=utf-8",
+			body:         `<a href="http://legacy.internal:8080/foo">foo</a></body>`,
+			expectedBody: `<a href="https://example.com/foo">foo</a><script src="/snippet.js"></script></body>`,
+		},
+		{
+			desc:         "json matching a wildcard content type",
+			contentType:  "application/json",
+			body:         `{"url":"http://legacy.internal/bar"}`,
+			expectedBody: `{"url":"https://example.com/bar"}`,
+		},
+		{
+			desc:         "gzip-encoded",
+			contentType:  "text/html",
+			encoding:     "gzip",
+			body:         `<a href="http://legacy.internal/foo">foo</a></body>`,
+			expectedBody: `<a href="https://example.com/foo">foo</a><script src="/snippet.js"></script></body>`,
+		},
+		{
+			desc:         "flushed while buffered",
+			contentType:  "text/html",
+			flush:        true,
+			body:         `<a href="http://legacy.internal/foo">foo</a>`,
+			expectedBody: `<a href="https://example.com/foo">foo</a>`,
+		},
+		{
+			desc:         "other content type",
+			contentType:  "text/plain",
+			body:         `http://legacy.internal/foo`,
+			expectedBody: `http://legacy.internal/foo`,
+		},
+		{
+			desc:         "other encoding",
+			contentType:  "text/html",
+			encoding:     "br",
+			body:         `http://legacy.internal/foo`,
+			expectedBody: `http://legacy.internal/foo`,
+		},
+		{
+			desc:         "body too large",
+			contentType:  "text/html",
+			body:         `http://legacy.internal/` + strings.Repeat("a", 100),
+			expectedBody: `http://legacy.internal/` + strings.Repeat("a", 100),
+		},
+		{
+			desc:         "HEAD request",
+			method:       http.MethodHead,
+			contentType:  "text/html",
+			body:         `http://legacy.internal/foo`,
+			expectedBody: `http://legacy.internal/foo`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body := []byte(test.body)
+				if test.encoding == "gzip" {
+					body = gzipped(t, body)
+				}
+
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				if test.encoding != "" {
+					rw.Header().Set("Content-Encoding", test.encoding)
+				}
+
+				// Written in two parts to check the buffering.
+				_, err := rw.Write(body[:len(body)/2])
+				require.NoError(t, err)
+
+				if test.flush {
+					rw.(http.Flusher).Flush()
+				}
+
+				_, err = rw.Write(body[len(body)/2:])
+				require.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, conf, "test")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(method, "http://localhost", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, strconv.Itoa(recorder.Body.Len()), recorder.Header().Get("Content-Length"))
+
+			body := recorder.Body.Bytes()
+			if test.encoding == "gzip" {
+				reader, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+
+				body, err = ioutil.ReadAll(reader)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
+
+func TestBodyRewrite_responseStatus(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		rw.WriteHeader(http.StatusNotFound)
+		_, _ = rw.Write([]byte("foo not found"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.BodyRewrite{
+		Response: []dynamic.BodyReplacement{{Literal: "foo", Replacement: "bar"}},
+	}, "test")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "bar not found", recorder.Body.String())
+}
+
+func TestBodyRewrite_responseValidators(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		code                 int
+		body                 string
+		expectedBody         string
+		expectedETag         string
+		expectedAcceptRanges string
+	}{
+		{
+			desc:         "rewritten",
+			code:         http.StatusOK,
+			body:         "foo",
+			expectedBody: "bar",
+			expectedETag: `W/"foo"`,
+		},
+		{
+			desc:                 "unchanged",
+			code:                 http.StatusOK,
+			body:                 "baz",
+			expectedBody:         "baz",
+			expectedETag:         `"foo"`,
+			expectedAcceptRanges: "bytes",
+		},
+		{
+			desc:                 "partial content",
+			code:                 http.StatusPartialContent,
+			body:                 "foo",
+			expectedBody:         "foo",
+			expectedETag:         `"foo"`,
+			expectedAcceptRanges: "bytes",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "text/html")
+				rw.Header().Set("ETag", `"foo"`)
+				rw.Header().Set("Accept-Ranges", "bytes")
+				rw.WriteHeader(test.code)
+				_, _ = rw.Write([]byte(test.body))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.BodyRewrite{
+				Response: []dynamic.BodyReplacement{{Literal: "foo", Replacement: "bar"}},
+			}, "test")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.code, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedETag, recorder.Header().Get("ETag"))
+			assert.Equal(t, test.expectedAcceptRanges, recorder.Header().Get("Accept-Ranges"))
+		})
+	}
+}
+
+func TestBodyRewrite_request(t *testing.T) {
+	conf := dynamic.BodyRewrite{
+		Request: []dynamic.BodyReplacement{
+			{Literal: "https://example.com/", Replacement: "http://legacy.internal/"},
+		},
+		MaxBodySize: 100,
+	}
+
+	testCases := []struct {
+		desc         string
+		contentType  string
+		encoding     string
+		body         string
+		expectedBody string
+	}{
+		{
+			desc:         "json",
+			contentType:  "application/json",
+			body:         `{"url":"https://example.com/foo"}`,
+			expectedBody: `{"url":"http://legacy.internal/foo"}`,
+		},
+		{
+			desc:         "other content type",
+			contentType:  "text/plain",
+			body:         `https://example.com/foo`,
+			expectedBody: `https://example.com/foo`,
+		},
+		{
+			desc:         "encoded",
+			contentType:  "application/json",
+			encoding:     "gzip",
+			body:         `https://example.com/foo`,
+			expectedBody: `https://example.com/foo`,
+		},
+		{
+			desc:         "body too large",
+			contentType:  "application/json",
+			body:         `"https://example.com/` + strings.Repeat("a", 100) + `"`,
+			expectedBody: `"https://example.com/` + strings.Repeat("a", 100) + `"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, test.expectedBody, string(body))
+				assert.Equal(t, int64(len(body)), req.ContentLength)
+			})
+
+			handler, err := New(context.Background(), next, conf, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			if test.encoding != "" {
+				req.Header.Set("Content-Encoding", test.encoding)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,513 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "Cache"
+
+	defaultMaxSize      = 64 << 20 // 64 MiB
+	defaultMaxEntrySize = 1 << 20  // 1 MiB
+)
+
+// Statuses of the requests in the cache, reported in the access logs.
+const (
+	statusHit         = "HIT"
+	statusStale       = "STALE"
+	statusRevalidated = "REVALIDATED"
+	statusMiss        = "MISS"
+	statusBypass      = "BYPASS"
+)
+
+// Manager keeps the stores of the cache middlewares across the configuration reloads,
+// and reports their hits and misses in the metrics.
+type Manager struct {
+	hits   gokitmetrics.Counter
+	misses gokitmetrics.Counter
+
+	lock   sync.Mutex
+	stores map[string]*managedStore
+}
+
+type managedStore struct {
+	conf  dynamic.Cache
+	store store
+}
+
+// NewManager creates a new Manager.
+func NewManager(registry metrics.Registry) *Manager {
+	manager := &Manager{stores: make(map[string]*managedStore)}
+
+	if registry != nil {
+		manager.hits = registry.CacheHitsCounter()
+		manager.misses = registry.CacheMissesCounter()
+	}
+
+	return manager
+}
+
+// getStore returns the store of the middleware, which is kept as long as the configuration of the middleware does not change.
+func (m *Manager) getStore(name string, conf dynamic.Cache) (store, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if managed, ok := m.stores[name]; ok && reflect.DeepEqual(managed.conf, conf) {
+		return managed.store, nil
+	}
+
+	s, err := newStore(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	m.stores[name] = &managedStore{conf: conf, store: s}
+	return s, nil
+}
+
+func newStore(conf dynamic.Cache) (store, error) {
+	maxSize := conf.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+
+	if conf.Path != "" {
+		return newDiskStore(conf.Path, maxSize)
+	}
+	return newMemoryStore(maxSize), nil
+}
+
+// Handler is a middleware caching the responses, following the caching headers of the requests and of the responses.
+type Handler struct {
+	next                 http.Handler
+	name                 string
+	store                store
+	maxEntrySize         int64
+	defaultTTL           time.Duration
+	staleWhileRevalidate time.Duration
+	hits                 gokitmetrics.Counter
+	misses               gokitmetrics.Counter
+
+	revalidationsLock sync.Mutex
+	revalidations     map[string]struct{}
+}
+
+// New creates a new cache middleware.
+// If manager is not nil, the store of the middleware is kept across the configuration reloads,
+// and the hits and misses are reported in the metrics.
+func New(ctx context.Context, next http.Handler, conf dynamic.Cache, name string, manager *Manager) (*Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	handler := &Handler{
+		next:                 next,
+		name:                 name,
+		maxEntrySize:         conf.MaxEntrySize,
+		defaultTTL:           time.Duration(conf.DefaultTTL),
+		staleWhileRevalidate: time.Duration(conf.StaleWhileRevalidate),
+		revalidations:        make(map[string]struct{}),
+	}
+
+	if handler.maxEntrySize <= 0 {
+		handler.maxEntrySize = defaultMaxEntrySize
+	}
+
+	var err error
+	if manager != nil {
+		handler.store, err = manager.getStore(name, conf)
+		handler.hits = manager.hits
+		handler.misses = manager.misses
+	} else {
+		handler.store, err = newStore(conf)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return handler, nil
+}
+
+// GetTracingInformation returns the tracing information of the middleware.
+func (h *Handler) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return h.name, tracing.SpanKindNoneEnum
+}
+
+// Purge removes the entries of the given URL (e.g. https://example.com/foo?bar=baz) from the cache,
+// or all the entries if rawURL is empty.
+func (h *Handler) Purge(rawURL string) error {
+	if rawURL == "" {
+		h.store.purge("")
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: the scheme and the host are required", rawURL)
+	}
+
+	h.store.purge(cacheKey(u.Scheme, u.Host, u.RequestURI()))
+	return nil
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key := requestKey(req)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		h.serveUnsafe(rw, req, key)
+		return
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") || req.Header.Get("Range") != "" || req.Header.Get("Upgrade") != "" {
+		h.report(req, statusBypass)
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	noCache := reqCC.has("no-cache") || reqCC["max-age"] == "0" || req.Header.Get("Pragma") == "no-cache"
+	now := time.Now()
+
+	if e, variant, ok := h.lookup(req, key); ok {
+		switch {
+		case e.fresh(now) && !noCache:
+			h.report(req, statusHit)
+			h.serveEntry(rw, req, e, now)
+			return
+
+		case e.staleWhileRevalidate(now) && !noCache:
+			h.report(req, statusStale)
+			h.serveEntry(rw, req, e, now)
+			h.revalidateInBackground(req, key, variant, e)
+			return
+
+		case e.hasValidators():
+			h.revalidate(rw, req, key, variant, e)
+			return
+		}
+	}
+
+	h.report(req, statusMiss)
+
+	if req.Method == http.MethodHead {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	recorder := newRecorder(rw, h.maxEntrySize)
+	h.next.ServeHTTP(recorder, req)
+	h.storeResponse(req, key, recorder, time.Now())
+}
+
+// serveUnsafe forwards the request, and invalidates the entries of its URL if it succeeds.
+func (h *Handler) serveUnsafe(rw http.ResponseWriter, req *http.Request, key string) {
+	h.report(req, statusBypass)
+
+	recorder := newRecorder(rw, 0)
+	h.next.ServeHTTP(recorder, req)
+
+	if recorder.code != 0 && recorder.code < http.StatusBadRequest {
+		h.store.purge(key)
+	}
+}
+
+// lookup returns the entry of the variant of the response matching the request.
+func (h *Handler) lookup(req *http.Request, key string) (*entry, string, bool) {
+	e, ok := h.store.get(key, "")
+	if !ok || len(e.Vary) == 0 {
+		return e, "", ok
+	}
+
+	variant := variantKey(e.Vary, req)
+	e, ok = h.store.get(key, variant)
+	return e, variant, ok
+}
+
+func (h *Handler) serveEntry(rw http.ResponseWriter, req *http.Request, e *entry, now time.Time) {
+	header := rw.Header()
+	for name, values := range e.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("Age", strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+
+	if e.StatusCode == http.StatusOK && e.notModified(req) {
+		header.Del("Content-Length")
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.WriteHeader(e.StatusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(e.Body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.name, typeName)).Debugf("Error while writing the cached response: %v", err)
+	}
+}
+
+// revalidate sends a conditional request to validate the stale entry,
+// and serves either the entry if it is still valid, or the new response.
+func (h *Handler) revalidate(rw http.ResponseWriter, req *http.Request, key, variant string, e *entry) {
+	outReq := conditionalRequest(req.Clone(req.Context()), e)
+
+	recorder := newRecorder(rw, h.maxEntrySize)
+	recorder.interceptNotModified = true
+	h.next.ServeHTTP(recorder, outReq)
+
+	now := time.Now()
+
+	if recorder.notModified {
+		updated := e.revalidated(recorder.snapshot, now, h.defaultTTL, h.staleWhileRevalidate)
+		h.store.set(key, variant, updated)
+
+		h.report(req, statusRevalidated)
+		h.serveEntry(rw, req, updated, now)
+		return
+	}
+
+	h.report(req, statusMiss)
+	h.storeResponse(req, key, recorder, now)
+}
+
+// revalidateInBackground revalidates the stale entry without blocking the request,
+// making sure that only one revalidation of the entry is in progress.
+func (h *Handler) revalidateInBackground(req *http.Request, key, variant string, e *entry) {
+	id := key + "\n" + variant
+
+	h.revalidationsLock.Lock()
+	if _, ok := h.revalidations[id]; ok {
+		h.revalidationsLock.Unlock()
+		return
+	}
+	h.revalidations[id] = struct{}{}
+	h.revalidationsLock.Unlock()
+
+	// The request is cloned before the end of ServeHTTP, as it must not be used after.
+	outReq := conditionalRequest(req.Clone(context.Background()), e)
+	outReq.Method = http.MethodGet
+
+	safe.Go(func() {
+		defer func() {
+			h.revalidationsLock.Lock()
+			delete(h.revalidations, id)
+			h.revalidationsLock.Unlock()
+		}()
+
+		recorder := newRecorder(&discardResponseWriter{header: make(http.Header)}, h.maxEntrySize)
+		recorder.interceptNotModified = true
+		h.next.ServeHTTP(recorder, outReq)
+
+		now := time.Now()
+
+		if recorder.notModified {
+			h.store.set(key, variant, e.revalidated(recorder.snapshot, now, h.defaultTTL, h.staleWhileRevalidate))
+			return
+		}
+
+		h.storeResponse(outReq, key, recorder, now)
+	})
+}
+
+// storeResponse stores the recorded response to the request, if it can be stored.
+func (h *Handler) storeResponse(req *http.Request, key string, recorder *recorder, now time.Time) {
+	if req.Method != http.MethodGet || recorder.code == 0 || recorder.tooLarge || recorder.hijacked {
+		return
+	}
+
+	e, ok := newEntry(req, recorder.code, recorder.snapshot, recorder.body.Bytes(), now, h.defaultTTL, h.staleWhileRevalidate)
+	if !ok {
+		return
+	}
+
+	if len(e.Vary) == 0 {
+		h.store.set(key, "", e)
+		return
+	}
+
+	// The variants are reached through an entry holding the headers the response varies on.
+	h.store.set(key, "", &entry{Vary: e.Vary, Stored: now})
+	h.store.set(key, variantKey(e.Vary, req), e)
+}
+
+func (h *Handler) report(req *http.Request, status string) {
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.CacheStatus] = status
+	}
+
+	switch status {
+	case statusHit, statusStale, statusRevalidated:
+		if h.hits != nil {
+			h.hits.With("middleware", h.name).Add(1)
+		}
+	case statusMiss:
+		if h.misses != nil {
+			h.misses.With("middleware", h.name).Add(1)
+		}
+	}
+}
+
+func requestKey(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return cacheKey(scheme, req.Host, req.URL.RequestURI())
+}
+
+func cacheKey(scheme, host, requestURI string) string {
+	return scheme + "://" + strings.ToLower(host) + requestURI
+}
+
+// conditionalRequest makes the request conditional on the validators of the entry.
+func conditionalRequest(req *http.Request, e *entry) *http.Request {
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+
+	if etag := e.Header.Get("ETag"); etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := e.Header.Get("Last-Modified"); lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return req
+}
+
+// recorder forwards the response to the underlying response writer while recording it, up to maxSize bytes.
+// The headers written by the next handlers are applied to the underlying response writer only when the status code is written,
+// so that a 304 response to a revalidation can be intercepted.
+type recorder struct {
+	rw                   http.ResponseWriter
+	maxSize              int64
+	interceptNotModified bool
+
+	header      http.Header
+	snapshot    http.Header
+	code        int
+	body        bytes.Buffer
+	tooLarge    bool
+	hijacked    bool
+	notModified bool
+}
+
+func newRecorder(rw http.ResponseWriter, maxSize int64) *recorder {
+	return &recorder{rw: rw, maxSize: maxSize, header: make(http.Header)}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.code != 0 {
+		return
+	}
+
+	r.code = code
+	r.snapshot = r.header.Clone()
+
+	if r.interceptNotModified && code == http.StatusNotModified {
+		r.notModified = true
+		return
+	}
+
+	header := r.rw.Header()
+	for name, values := range r.header {
+		header[name] = values
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.notModified {
+		return len(p), nil
+	}
+
+	if !r.tooLarge {
+		if int64(r.body.Len()+len(p)) > r.maxSize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(p)
+		}
+	}
+
+	return r.rw.Write(p)
+}
+
+func (r *recorder) Flush() {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.notModified {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+	}
+
+	// The headers are applied before the connection is taken over.
+	header := r.rw.Header()
+	for name, values := range r.header {
+		header[name] = values
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		r.hijacked = true
+	}
+	return conn, rw, err
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *discardResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (d *discardResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,386 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		cacheControl     string
+		vary             string
+		method           string
+		requestHeader    http.Header
+		expectedStatuses []string
+		expectedCalls    int32
+	}{
+		{
+			desc:             "cacheable response",
+			cacheControl:     "max-age=60",
+			expectedStatuses: []string{statusMiss, statusHit, statusHit},
+			expectedCalls:    1,
+		},
+		{
+			desc:             "not cacheable response",
+			cacheControl:     "no-store",
+			expectedStatuses: []string{statusMiss, statusMiss, statusMiss},
+			expectedCalls:    3,
+		},
+		{
+			desc:             "request without cache",
+			cacheControl:     "max-age=60",
+			requestHeader:    http.Header{"Cache-Control": {"no-store"}},
+			expectedStatuses: []string{statusBypass, statusBypass},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "range request",
+			cacheControl:     "max-age=60",
+			requestHeader:    http.Header{"Range": {"bytes=0-1"}},
+			expectedStatuses: []string{statusBypass, statusBypass},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "unsafe method",
+			cacheControl:     "max-age=60",
+			method:           http.MethodPost,
+			expectedStatuses: []string{statusBypass, statusBypass},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "HEAD request",
+			cacheControl:     "max-age=60",
+			method:           http.MethodHead,
+			expectedStatuses: []string{statusMiss, statusMiss},
+			expectedCalls:    2,
+		},
+		{
+			desc:             "vary",
+			cacheControl:     "max-age=60",
+			vary:             "Accept-Encoding",
+			requestHeader:    http.Header{"Accept-Encoding": {"gzip"}},
+			expectedStatuses: []string{statusMiss, statusHit},
+			expectedCalls:    1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				rw.Header().Set("Cache-Control", test.cacheControl)
+				if test.vary != "" {
+					rw.Header().Set("Vary", test.vary)
+				}
+				_, _ = rw.Write([]byte("foo"))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			for _, expectedStatus := range test.expectedStatuses {
+				req := httptest.NewRequest(method, "http://localhost/foo", nil)
+				for name, values := range test.requestHeader {
+					req.Header[name] = values
+				}
+
+				recorder, status := serve(handler, req)
+
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, expectedStatus, status)
+				if method != http.MethodHead {
+					assert.Equal(t, "foo", recorder.Body.String())
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestCache_vary(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Vary", "Accept-Language")
+		_, _ = rw.Write([]byte(req.Header.Get("Accept-Language")))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+	require.NoError(t, err)
+
+	for _, language := range []string{"en", "fr", "en", "fr"} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+		req.Header.Set("Accept-Language", language)
+
+		recorder, _ := serve(handler, req)
+		assert.Equal(t, language, recorder.Body.String())
+	}
+}
+
+func TestCache_conditionalRequest(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+	require.NoError(t, err)
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+
+	recorder, status := serve(handler, req)
+
+	assert.Equal(t, statusHit, status)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+	assert.Equal(t, `"v1"`, recorder.Header().Get("ETag"))
+}
+
+func TestCache_revalidation(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		etag           string
+		expectedStatus string
+		expectedBody   string
+	}{
+		{
+			desc:           "not modified",
+			etag:           `"v1"`,
+			expectedStatus: statusRevalidated,
+			expectedBody:   "v1",
+		},
+		{
+			desc:           "modified",
+			etag:           `"v2"`,
+			expectedStatus: statusMiss,
+			expectedBody:   "v2",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			etag := `"v1"`
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "no-cache")
+				rw.Header().Set("ETag", etag)
+
+				if req.Header.Get("If-None-Match") == etag {
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				rw.Header().Set("Content-Length", "2")
+				_, _ = rw.Write([]byte(etag[1:3]))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+			require.NoError(t, err)
+
+			_, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+			require.Equal(t, statusMiss, status)
+
+			etag = test.etag
+
+			recorder, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, "2", recorder.Header().Get("Content-Length"))
+		})
+	}
+}
+
+func TestCache_staleWhileRevalidate(t *testing.T) {
+	revalidated := make(chan struct{})
+
+	var version int32 = 1
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60")
+		_, _ = rw.Write([]byte("v" + strconv.Itoa(int(atomic.LoadInt32(&version)))))
+
+		if atomic.LoadInt32(&version) == 2 {
+			close(revalidated)
+		}
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+	require.NoError(t, err)
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	// Makes the entry stale, but still within the stale-while-revalidate window.
+	e, ok := handler.store.get("http://localhost/foo", "")
+	require.True(t, ok)
+
+	stale := *e
+	stale.Expires = time.Now().Add(-time.Second)
+	handler.store.set("http://localhost/foo", "", &stale)
+
+	atomic.StoreInt32(&version, 2)
+
+	recorder, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.Equal(t, statusStale, status)
+	assert.Equal(t, "v1", recorder.Body.String())
+
+	select {
+	case <-revalidated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the entry has not been revalidated")
+	}
+
+	// The store is updated after the response of the revalidation is written.
+	require.Eventually(t, func() bool {
+		recorder, status = serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+		return recorder.Body.String() == "v2"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, statusHit, status)
+}
+
+func TestCache_maxEntrySize(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+		_, _ = rw.Write([]byte("bar"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{MaxEntrySize: 4}, "test", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		recorder, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+		assert.Equal(t, statusMiss, status)
+		assert.Equal(t, "foobar", recorder.Body.String())
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCache_purge(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", nil)
+	require.NoError(t, err)
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil))
+	serve(handler, httptest.NewRequest(http.MethodGet, "https://localhost/foo?bar=baz", nil))
+
+	require.Error(t, handler.Purge("/foo"))
+	require.NoError(t, handler.Purge("http://LOCALHOST/foo?bar=baz"))
+
+	_, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo?bar=baz", nil))
+	assert.Equal(t, statusMiss, status)
+
+	_, status = serve(handler, httptest.NewRequest(http.MethodGet, "https://localhost/foo?bar=baz", nil))
+	assert.Equal(t, statusHit, status)
+
+	require.NoError(t, handler.Purge(""))
+
+	_, status = serve(handler, httptest.NewRequest(http.MethodGet, "https://localhost/foo?bar=baz", nil))
+	assert.Equal(t, statusMiss, status)
+
+	// A successful unsafe request invalidates the entries of its URL.
+	serve(handler, httptest.NewRequest(http.MethodDelete, "https://localhost/foo?bar=baz", nil))
+
+	_, status = serve(handler, httptest.NewRequest(http.MethodGet, "https://localhost/foo?bar=baz", nil))
+	assert.Equal(t, statusMiss, status)
+
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestManager(t *testing.T) {
+	manager := NewManager(nil)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", manager)
+	require.NoError(t, err)
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	// The store is kept while the configuration does not change.
+	handler, err = New(context.Background(), next, dynamic.Cache{}, "test", manager)
+	require.NoError(t, err)
+
+	_, status := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.Equal(t, statusHit, status)
+
+	handler, err = New(context.Background(), next, dynamic.Cache{MaxSize: 1024}, "test", manager)
+	require.NoError(t, err)
+
+	_, status = serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.Equal(t, statusMiss, status)
+}
+
+func TestCache_metrics(t *testing.T) {
+	hits := &testhelpers.CollectingCounter{}
+	misses := &testhelpers.CollectingCounter{}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Cache{}, "test", &Manager{hits: hits, misses: misses, stores: make(map[string]*managedStore)})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	}
+
+	assert.Equal(t, float64(2), hits.CounterValue)
+	assert.Equal(t, []string{"middleware", "test"}, hits.LastLabelValues)
+	assert.Equal(t, float64(1), misses.CounterValue)
+}
+
+func serve(handler http.Handler, req *http.Request) (*httptest.ResponseRecorder, string) {
+	logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+	req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	status, _ := logData.Core[accesslog.CacheStatus].(string)
+	return recorder, status
+}
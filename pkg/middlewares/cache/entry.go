@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheableStatusCodes are the status codes of the responses which can be stored.
+var cacheableStatusCodes = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusGone:                 true,
+}
+
+// hopHeaders are the headers which are not stored.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// entry is a response stored in the cache.
+// Its fields are exported to be encoded by the on-disk store.
+type entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Vary holds the names of the headers the response varies on.
+	// An entry with Vary only points to the variants of the response.
+	Vary []string
+
+	// Stored is the time at which the response has been stored.
+	Stored time.Time
+	// InitialAge is the age of the response when it has been stored.
+	InitialAge time.Duration
+	// Expires is the time at which the response becomes stale.
+	Expires time.Time
+	// StaleUntil is the time until which the stale response can be served while it is revalidated.
+	StaleUntil time.Time
+}
+
+func (e *entry) fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+func (e *entry) staleWhileRevalidate(now time.Time) bool {
+	return now.Before(e.StaleUntil)
+}
+
+func (e *entry) hasValidators() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return e.InitialAge + now.Sub(e.Stored)
+}
+
+func (e *entry) size() int64 {
+	size := int64(len(e.Body))
+	for name, values := range e.Header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	for _, name := range e.Vary {
+		size += int64(len(name))
+	}
+	return size
+}
+
+// newEntry creates an entry from the response to the request,
+// and returns false if the response cannot be stored.
+func newEntry(req *http.Request, statusCode int, header http.Header, body []byte, now time.Time, defaultTTL, staleWhileRevalidate time.Duration) (*entry, bool) {
+	if !cacheableStatusCodes[statusCode] {
+		return nil, false
+	}
+
+	cc := parseCacheControl(header)
+	if cc.has("no-store") || cc.has("private") {
+		return nil, false
+	}
+
+	if header.Get("Set-Cookie") != "" {
+		return nil, false
+	}
+
+	// The responses to authenticated requests are only stored if they are explicitly cacheable.
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return nil, false
+	}
+
+	vary := varyHeaders(header)
+	for _, name := range vary {
+		if name == "*" {
+			return nil, false
+		}
+	}
+
+	e := &entry{
+		StatusCode: statusCode,
+		Header:     header.Clone(),
+		Body:       body,
+		Vary:       vary,
+		Stored:     now,
+		InitialAge: parseSeconds(header.Get("Age")),
+	}
+
+	for _, name := range hopHeaders {
+		e.Header.Del(name)
+	}
+	e.Header.Del("Age")
+
+	e.updateFreshness(header, now, defaultTTL, staleWhileRevalidate)
+
+	if !e.fresh(now) && !e.hasValidators() {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// updateFreshness computes the expiration of the entry from the caching headers of the response.
+func (e *entry) updateFreshness(header http.Header, now time.Time, defaultTTL, staleWhileRevalidate time.Duration) {
+	cc := parseCacheControl(header)
+
+	var lifetime time.Duration
+	switch {
+	case cc.has("no-cache"):
+		lifetime = 0
+	case cc.has("s-maxage"):
+		lifetime = parseSeconds(cc["s-maxage"])
+	case cc.has("max-age"):
+		lifetime = parseSeconds(cc["max-age"])
+	case header.Get("Expires") != "":
+		// An invalid Expires means that the response is already expired.
+		if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+			date, err := http.ParseTime(header.Get("Date"))
+			if err != nil {
+				date = now
+			}
+			lifetime = expires.Sub(date)
+		}
+	default:
+		lifetime = defaultTTL
+	}
+
+	e.Expires = now.Add(lifetime - e.InitialAge)
+	e.StaleUntil = e.Expires
+
+	if cc.has("no-cache") || cc.has("must-revalidate") || cc.has("proxy-revalidate") {
+		return
+	}
+
+	if cc.has("stale-while-revalidate") {
+		staleWhileRevalidate = parseSeconds(cc["stale-while-revalidate"])
+	}
+	e.StaleUntil = e.Expires.Add(staleWhileRevalidate)
+}
+
+// revalidated updates the entry with the headers of the 304 response to its revalidation.
+func (e *entry) revalidated(header http.Header, now time.Time, defaultTTL, staleWhileRevalidate time.Duration) *entry {
+	updated := *e
+	updated.Header = e.Header.Clone()
+
+	for name, values := range header {
+		updated.Header[name] = values
+	}
+	for _, name := range hopHeaders {
+		updated.Header.Del(name)
+	}
+	updated.Header.Del("Age")
+
+	updated.Stored = now
+	updated.InitialAge = parseSeconds(header.Get("Age"))
+	updated.updateFreshness(updated.Header, now, defaultTTL, staleWhileRevalidate)
+
+	return &updated
+}
+
+// notModified reports whether the conditional request is satisfied by the entry.
+func (e *entry) notModified(req *http.Request) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		etag := e.Header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(e.Header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(since)
+}
+
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	cc := make(cacheControl)
+	for _, value := range header["Cache-Control"] {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+
+			name, arg := directive, ""
+			if i := strings.Index(directive, "="); i >= 0 {
+				name, arg = directive[:i], strings.Trim(directive[i+1:], `"`)
+			}
+			cc[strings.ToLower(name)] = arg
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, value := range header["Vary"] {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// variantKey returns the key of the variant of the response matching the request.
+func variantKey(vary []string, req *http.Request) string {
+	var key strings.Builder
+	for _, name := range vary {
+		key.WriteString(name)
+		key.WriteString(":")
+		key.WriteString(strings.Join(req.Header[name], ","))
+		key.WriteString("\n")
+	}
+	return key.String()
+}
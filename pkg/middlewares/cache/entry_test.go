@@ -0,0 +1,261 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntry(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc               string
+		statusCode         int
+		header             http.Header
+		requestHeader      http.Header
+		defaultTTL         time.Duration
+		swr                time.Duration
+		expectedStored     bool
+		expectedExpires    time.Time
+		expectedStaleUntil time.Time
+	}{
+		{
+			desc:               "max-age",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60"}},
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(time.Minute),
+		},
+		{
+			desc:               "s-maxage over max-age",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60, s-maxage=120"}},
+			expectedStored:     true,
+			expectedExpires:    now.Add(2 * time.Minute),
+			expectedStaleUntil: now.Add(2 * time.Minute),
+		},
+		{
+			desc:               "age",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}},
+			expectedStored:     true,
+			expectedExpires:    now.Add(40 * time.Second),
+			expectedStaleUntil: now.Add(40 * time.Second),
+		},
+		{
+			desc:       "expires",
+			statusCode: http.StatusOK,
+			header: http.Header{
+				"Date":    {now.Format(http.TimeFormat)},
+				"Expires": {now.Add(time.Hour).Format(http.TimeFormat)},
+			},
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Hour),
+			expectedStaleUntil: now.Add(time.Hour),
+		},
+		{
+			desc:               "default TTL",
+			statusCode:         http.StatusOK,
+			header:             http.Header{},
+			defaultTTL:         time.Minute,
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(time.Minute),
+		},
+		{
+			desc:               "stale-while-revalidate directive",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60, stale-while-revalidate=30"}},
+			swr:                time.Hour,
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(90 * time.Second),
+		},
+		{
+			desc:               "configured stale-while-revalidate",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60"}},
+			swr:                time.Minute,
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(2 * time.Minute),
+		},
+		{
+			desc:               "must-revalidate disables stale-while-revalidate",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"max-age=60, must-revalidate"}},
+			swr:                time.Minute,
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(time.Minute),
+		},
+		{
+			desc:               "no-cache with validator",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"no-cache"}, "Etag": {`"foo"`}},
+			expectedStored:     true,
+			expectedExpires:    now,
+			expectedStaleUntil: now,
+		},
+		{
+			desc:       "no-cache without validator",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"no-cache"}},
+		},
+		{
+			desc:       "no-store",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"no-store, max-age=60"}},
+		},
+		{
+			desc:       "private",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"private, max-age=60"}},
+		},
+		{
+			desc:       "set-cookie",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"foo=bar"}},
+		},
+		{
+			desc:          "authorization",
+			statusCode:    http.StatusOK,
+			header:        http.Header{"Cache-Control": {"max-age=60"}},
+			requestHeader: http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+		},
+		{
+			desc:               "authorization with public",
+			statusCode:         http.StatusOK,
+			header:             http.Header{"Cache-Control": {"public, max-age=60"}},
+			requestHeader:      http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+			expectedStored:     true,
+			expectedExpires:    now.Add(time.Minute),
+			expectedStaleUntil: now.Add(time.Minute),
+		},
+		{
+			desc:       "vary on all",
+			statusCode: http.StatusOK,
+			header:     http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+		},
+		{
+			desc:       "status code",
+			statusCode: http.StatusInternalServerError,
+			header:     http.Header{"Cache-Control": {"max-age=60"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for name, values := range test.requestHeader {
+				req.Header[name] = values
+			}
+
+			e, ok := newEntry(req, test.statusCode, test.header, []byte("foo"), now, test.defaultTTL, test.swr)
+			require.Equal(t, test.expectedStored, ok)
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, test.expectedExpires, e.Expires)
+			assert.Equal(t, test.expectedStaleUntil, e.StaleUntil)
+			assert.Empty(t, e.Header.Get("Age"))
+		})
+	}
+}
+
+func TestEntry_notModified(t *testing.T) {
+	lastModified := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	e := &entry{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Etag":          {`W/"foo"`},
+			"Last-Modified": {lastModified.Format(http.TimeFormat)},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		header   http.Header
+		expected bool
+	}{
+		{
+			desc:     "not conditional",
+			header:   http.Header{},
+			expected: false,
+		},
+		{
+			desc:     "matching etag",
+			header:   http.Header{"If-None-Match": {`"bar", "foo"`}},
+			expected: true,
+		},
+		{
+			desc:     "not matching etag",
+			header:   http.Header{"If-None-Match": {`"bar"`}},
+			expected: false,
+		},
+		{
+			desc:     "any etag",
+			header:   http.Header{"If-None-Match": {"*"}},
+			expected: true,
+		},
+		{
+			desc:     "not modified since",
+			header:   http.Header{"If-Modified-Since": {lastModified.Format(http.TimeFormat)}},
+			expected: true,
+		},
+		{
+			desc:     "modified since",
+			header:   http.Header{"If-Modified-Since": {lastModified.Add(-time.Hour).Format(http.TimeFormat)}},
+			expected: false,
+		},
+		{
+			desc: "etag over date",
+			header: http.Header{
+				"If-None-Match":     {`"bar"`},
+				"If-Modified-Since": {lastModified.Format(http.TimeFormat)},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header = test.header
+
+			assert.Equal(t, test.expected, e.notModified(req))
+		})
+	}
+}
+
+func TestVariantKey(t *testing.T) {
+	vary := varyHeaders(http.Header{"Vary": {"accept-encoding, Accept-Language"}})
+	assert.Equal(t, []string{"Accept-Encoding", "Accept-Language"}, vary)
+
+	gzipReq := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	gzipReq.Header.Set("Accept-Encoding", "gzip")
+
+	plainReq := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+
+	assert.NotEqual(t, variantKey(vary, gzipReq), variantKey(vary, plainReq))
+
+	otherReq := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	otherReq.Header.Set("Accept-Encoding", "gzip")
+	otherReq.Header.Set("User-Agent", "foo")
+
+	assert.Equal(t, variantKey(vary, gzipReq), variantKey(vary, otherReq))
+}
@@ -0,0 +1,395 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// store stores the entries of a cache, by URL and by variant of the response.
+type store interface {
+	get(url, variant string) (*entry, bool)
+	set(url, variant string, e *entry)
+	// purge removes all the variants of the URL, or all the entries if url is empty.
+	purge(url string)
+}
+
+type memoryItem struct {
+	url     string
+	variant string
+	entry   *entry
+	size    int64
+}
+
+// memoryStore is an in-memory store, evicting the least recently used entries when it is full.
+type memoryStore struct {
+	maxSize int64
+
+	lock  sync.Mutex
+	size  int64
+	lru   *list.List
+	items map[string]map[string]*list.Element
+}
+
+func newMemoryStore(maxSize int64) *memoryStore {
+	return &memoryStore{
+		maxSize: maxSize,
+		lru:     list.New(),
+		items:   make(map[string]map[string]*list.Element),
+	}
+}
+
+func (s *memoryStore) get(url, variant string) (*entry, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	element, ok := s.items[url][variant]
+	if !ok {
+		return nil, false
+	}
+
+	s.lru.MoveToFront(element)
+	return element.Value.(*memoryItem).entry, true
+}
+
+func (s *memoryStore) set(url, variant string, e *entry) {
+	item := &memoryItem{url: url, variant: variant, entry: e, size: e.size() + int64(len(url)+len(variant))}
+	if item.size > s.maxSize {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if element, ok := s.items[url][variant]; ok {
+		s.remove(element)
+	}
+
+	if s.items[url] == nil {
+		s.items[url] = make(map[string]*list.Element)
+	}
+	s.items[url][variant] = s.lru.PushFront(item)
+	s.size += item.size
+
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *memoryStore) purge(url string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if url == "" {
+		s.size = 0
+		s.lru.Init()
+		s.items = make(map[string]map[string]*list.Element)
+		return
+	}
+
+	for _, element := range s.items[url] {
+		s.remove(element)
+	}
+}
+
+func (s *memoryStore) remove(element *list.Element) {
+	item := s.lru.Remove(element).(*memoryItem)
+	s.size -= item.size
+
+	delete(s.items[item.url], item.variant)
+	if len(s.items[item.url]) == 0 {
+		delete(s.items, item.url)
+	}
+}
+
+// diskSweepInterval is the minimum interval between two removals of the expired entries of an on-disk store.
+const diskSweepInterval = time.Minute
+
+type diskItem struct {
+	dir  string
+	file string
+	size int64
+	// expires is the time after which the entry cannot be served anymore, or zero if it can always be revalidated.
+	expires time.Time
+}
+
+// diskStore is an on-disk store, keeping the variants of a URL in a directory named after its hash.
+// It removes the expired entries, and evicts the least recently used entries when it is full.
+type diskStore struct {
+	path    string
+	maxSize int64
+
+	lock      sync.Mutex
+	size      int64
+	lru       *list.List
+	items     map[string]map[string]*list.Element
+	lastSweep time.Time
+}
+
+func newDiskStore(path string, maxSize int64) (*diskStore, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+
+	s := &diskStore{
+		path:      path,
+		maxSize:   maxSize,
+		lru:       list.New(),
+		items:     make(map[string]map[string]*list.Element),
+		lastSweep: time.Now(),
+	}
+
+	if err := s.load(s.lastSweep); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load indexes the entries already in the directory, from the least to the most recently written,
+// and removes the expired, unreadable and partially written ones.
+func (s *diskStore) load(now time.Time) error {
+	dirs, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return err
+	}
+
+	type storedItem struct {
+		item    *diskItem
+		modTime time.Time
+	}
+
+	var stored []storedItem
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(filepath.Join(s.path, dir.Name()))
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			name := filepath.Join(s.path, dir.Name(), file.Name())
+
+			if strings.HasPrefix(file.Name(), ".tmp-") {
+				_ = os.Remove(name)
+				continue
+			}
+
+			e, ok := readEntry(name)
+			if !ok {
+				_ = os.Remove(name)
+				continue
+			}
+
+			item := &diskItem{dir: dir.Name(), file: file.Name(), size: file.Size(), expires: diskExpiry(e)}
+			if item.expired(now) {
+				_ = os.Remove(name)
+				continue
+			}
+
+			stored = append(stored, storedItem{item: item, modTime: file.ModTime()})
+		}
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].modTime.Before(stored[j].modTime)
+	})
+
+	for _, st := range stored {
+		s.add(st.item)
+	}
+
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+	return nil
+}
+
+func (s *diskStore) get(url, variant string) (*entry, bool) {
+	dir, file := hash(url), hash(variant)
+
+	s.lock.Lock()
+	element, ok := s.items[dir][file]
+	if ok {
+		s.lru.MoveToFront(element)
+	}
+	s.lock.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	return readEntry(s.file(url, variant))
+}
+
+func (s *diskStore) set(url, variant string, e *entry) {
+	if e.size() > s.maxSize {
+		return
+	}
+
+	item := &diskItem{dir: hash(url), file: hash(variant), expires: diskExpiry(e)}
+
+	dir := filepath.Join(s.path, item.dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+
+	// The entry is written in a temporary file, then renamed, so that it is never read partially written.
+	file, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return
+	}
+
+	err = gob.NewEncoder(file).Encode(e)
+	if err == nil {
+		var info os.FileInfo
+		if info, err = file.Stat(); err == nil {
+			item.size = info.Size()
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || item.size > s.maxSize {
+		_ = os.Remove(file.Name())
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := os.Rename(file.Name(), filepath.Join(dir, item.file)); err != nil {
+		_ = os.Remove(file.Name())
+		return
+	}
+
+	// The file of the previous entry has been replaced.
+	if element, ok := s.items[item.dir][item.file]; ok {
+		s.forget(element)
+	}
+	s.add(item)
+
+	if now := time.Now(); now.Sub(s.lastSweep) >= diskSweepInterval {
+		s.sweep(now)
+	}
+
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *diskStore) purge(url string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if url != "" {
+		dir := hash(url)
+		for _, element := range s.items[dir] {
+			s.forget(element)
+		}
+		_ = os.RemoveAll(filepath.Join(s.path, dir))
+		return
+	}
+
+	s.size = 0
+	s.lru.Init()
+	s.items = make(map[string]map[string]*list.Element)
+
+	dirs, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		_ = os.RemoveAll(filepath.Join(s.path, dir.Name()))
+	}
+}
+
+// sweep removes the expired entries.
+func (s *diskStore) sweep(now time.Time) {
+	s.lastSweep = now
+
+	for element := s.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*diskItem).expired(now) {
+			s.remove(element)
+		}
+		element = next
+	}
+}
+
+func (s *diskStore) add(item *diskItem) {
+	if s.items[item.dir] == nil {
+		s.items[item.dir] = make(map[string]*list.Element)
+	}
+	s.items[item.dir][item.file] = s.lru.PushFront(item)
+	s.size += item.size
+}
+
+// remove removes the entry from the index and from the disk.
+func (s *diskStore) remove(element *list.Element) {
+	item := s.forget(element)
+
+	_ = os.Remove(filepath.Join(s.path, item.dir, item.file))
+	if _, ok := s.items[item.dir]; !ok {
+		// Only succeeds if the directory is empty.
+		_ = os.Remove(filepath.Join(s.path, item.dir))
+	}
+}
+
+// forget removes the entry from the index only.
+func (s *diskStore) forget(element *list.Element) *diskItem {
+	item := s.lru.Remove(element).(*diskItem)
+	s.size -= item.size
+
+	delete(s.items[item.dir], item.file)
+	if len(s.items[item.dir]) == 0 {
+		delete(s.items, item.dir)
+	}
+	return item
+}
+
+func (i *diskItem) expired(now time.Time) bool {
+	return !i.expires.IsZero() && now.After(i.expires)
+}
+
+// diskExpiry returns the time after which the entry cannot be served anymore:
+// once stale, an entry without validators cannot be revalidated.
+// The entries pointing to the variants of a response do not expire.
+func diskExpiry(e *entry) time.Time {
+	if len(e.Vary) > 0 || e.hasValidators() {
+		return time.Time{}
+	}
+	return e.StaleUntil
+}
+
+func (s *diskStore) file(url, variant string) string {
+	return filepath.Join(s.path, hash(url), hash(variant))
+}
+
+func readEntry(name string) (*entry, bool) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = file.Close() }()
+
+	var e entry
+	if err := gob.NewDecoder(file).Decode(&e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_eviction(t *testing.T) {
+	newTestEntry := func() *entry {
+		return &entry{StatusCode: http.StatusOK, Body: make([]byte, 100)}
+	}
+
+	s := newMemoryStore(250)
+
+	s.set("http://localhost/foo", "", newTestEntry())
+	s.set("http://localhost/bar", "", newTestEntry())
+
+	// foo is used, so bar becomes the least recently used entry.
+	_, ok := s.get("http://localhost/foo", "")
+	require.True(t, ok)
+
+	s.set("http://localhost/baz", "", newTestEntry())
+
+	_, ok = s.get("http://localhost/foo", "")
+	assert.True(t, ok)
+	_, ok = s.get("http://localhost/bar", "")
+	assert.False(t, ok)
+	_, ok = s.get("http://localhost/baz", "")
+	assert.True(t, ok)
+
+	// An entry larger than the store is not stored.
+	s.set("http://localhost/large", "", &entry{Body: make([]byte, 300)})
+	_, ok = s.get("http://localhost/large", "")
+	assert.False(t, ok)
+	_, ok = s.get("http://localhost/foo", "")
+	assert.True(t, ok)
+}
+
+func TestStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	disk, err := newDiskStore(dir, defaultMaxSize)
+	require.NoError(t, err)
+
+	stores := map[string]store{
+		"memory": newMemoryStore(defaultMaxSize),
+		"disk":   disk,
+	}
+
+	for name, s := range stores {
+		s := s
+		t.Run(name, func(t *testing.T) {
+			stored := &entry{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"text/plain"}},
+				Body:       []byte("foo"),
+				Stored:     time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC),
+				Expires:    time.Date(2020, time.January, 1, 13, 0, 0, 0, time.UTC),
+			}
+
+			s.set("http://localhost/foo", "", stored)
+			s.set("http://localhost/foo", "variant", stored)
+			s.set("http://localhost/bar", "", stored)
+
+			e, ok := s.get("http://localhost/foo", "variant")
+			require.True(t, ok)
+			assert.Equal(t, stored.Body, e.Body)
+			assert.Equal(t, stored.Header, e.Header)
+			assert.True(t, stored.Expires.Equal(e.Expires))
+
+			_, ok = s.get("http://localhost/foo", "other")
+			assert.False(t, ok)
+
+			s.purge("http://localhost/foo")
+
+			_, ok = s.get("http://localhost/foo", "")
+			assert.False(t, ok)
+			_, ok = s.get("http://localhost/foo", "variant")
+			assert.False(t, ok)
+			_, ok = s.get("http://localhost/bar", "")
+			assert.True(t, ok)
+
+			s.purge("")
+
+			_, ok = s.get("http://localhost/bar", "")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestDiskStore_eviction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	newTestEntry := func() *entry {
+		return &entry{StatusCode: http.StatusOK, Body: make([]byte, 100)}
+	}
+
+	s, err := newDiskStore(dir, defaultMaxSize)
+	require.NoError(t, err)
+
+	s.set("http://localhost/foo", "", newTestEntry())
+	entrySize := s.size
+	require.NotZero(t, entrySize)
+
+	// The store indexes the entries already on disk.
+	s, err = newDiskStore(dir, entrySize*5/2)
+	require.NoError(t, err)
+	assert.Equal(t, entrySize, s.size)
+
+	s.set("http://localhost/bar", "", newTestEntry())
+
+	// foo is used, so bar becomes the least recently used entry.
+	_, ok := s.get("http://localhost/foo", "")
+	require.True(t, ok)
+
+	s.set("http://localhost/baz", "", newTestEntry())
+
+	_, ok = s.get("http://localhost/foo", "")
+	assert.True(t, ok)
+	_, ok = s.get("http://localhost/bar", "")
+	assert.False(t, ok)
+	_, ok = s.get("http://localhost/baz", "")
+	assert.True(t, ok)
+	assert.Equal(t, 2*entrySize, s.size)
+
+	_, err = os.Stat(s.file("http://localhost/bar", ""))
+	assert.True(t, os.IsNotExist(err))
+
+	// An entry larger than the store is not stored.
+	s.set("http://localhost/large", "", &entry{Body: make([]byte, entrySize*3)})
+	_, ok = s.get("http://localhost/large", "")
+	assert.False(t, ok)
+	_, ok = s.get("http://localhost/foo", "")
+	assert.True(t, ok)
+}
+
+func TestDiskStore_expiration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	s, err := newDiskStore(dir, defaultMaxSize)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	s.set("http://localhost/expired", "", &entry{StatusCode: http.StatusOK, Expires: now.Add(-time.Hour), StaleUntil: now.Add(-time.Minute)})
+	s.set("http://localhost/validators", "", &entry{StatusCode: http.StatusOK, Header: http.Header{"Etag": {`"foo"`}}, Expires: now.Add(-time.Hour), StaleUntil: now.Add(-time.Minute)})
+	s.set("http://localhost/fresh", "", &entry{StatusCode: http.StatusOK, Expires: now.Add(time.Hour), StaleUntil: now.Add(time.Hour)})
+
+	// The expired entries are removed at most once per sweep interval.
+	_, ok := s.get("http://localhost/expired", "")
+	assert.True(t, ok)
+
+	s.lastSweep = now.Add(-diskSweepInterval)
+	s.set("http://localhost/foo", "", &entry{StatusCode: http.StatusOK})
+
+	_, ok = s.get("http://localhost/expired", "")
+	assert.False(t, ok)
+	_, ok = s.get("http://localhost/validators", "")
+	assert.True(t, ok)
+	_, ok = s.get("http://localhost/fresh", "")
+	assert.True(t, ok)
+
+	_, err = os.Stat(s.file("http://localhost/expired", ""))
+	assert.True(t, os.IsNotExist(err))
+}
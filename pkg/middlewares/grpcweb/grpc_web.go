@@ -0,0 +1,300 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/tracing"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	typeName = "GrpcWeb"
+
+	contentTypeGrpc    = "application/grpc"
+	contentTypeWeb     = "application/grpc-web"
+	contentTypeWebText = "application/grpc-web-text"
+
+	// trailerFrameFlag is the flag of the gRPC-Web frame holding the trailers.
+	trailerFrameFlag = 0x80
+)
+
+// grpcWeb is a middleware translating the gRPC-Web requests to native gRPC requests,
+// and the native gRPC responses to gRPC-Web responses.
+type grpcWeb struct {
+	next         http.Handler
+	name         string
+	allowOrigins []string
+}
+
+// New creates a new gRPC-Web middleware.
+func New(ctx context.Context, next http.Handler, conf dynamic.GrpcWeb, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	return &grpcWeb{
+		next:         next,
+		name:         name,
+		allowOrigins: conf.AllowOrigins,
+	}, nil
+}
+
+func (g *grpcWeb) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return g.name, tracing.SpanKindNoneEnum
+}
+
+func (g *grpcWeb) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("Origin")
+
+	if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" && g.isAllowedOrigin(origin) {
+		g.servePreflight(rw, req)
+		return
+	}
+
+	contentType := req.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, contentTypeWeb) {
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	text := strings.HasPrefix(contentType, contentTypeWebText)
+
+	if g.isAllowedOrigin(origin) {
+		rw.Header().Set("Access-Control-Allow-Origin", origin)
+		rw.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message")
+		rw.Header().Add("Vary", "Origin")
+	}
+
+	// The subtype of the content (e.g. +proto) is kept.
+	if text {
+		req.Header.Set("Content-Type", contentTypeGrpc+strings.TrimPrefix(contentType, contentTypeWebText))
+		req.Body = &readCloser{
+			Reader: &textDecoder{src: req.Body},
+			Closer: req.Body,
+		}
+	} else {
+		req.Header.Set("Content-Type", contentTypeGrpc+strings.TrimPrefix(contentType, contentTypeWeb))
+	}
+
+	req.Header.Set("Te", "trailers")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+
+	writer := newResponseWriter(rw, text)
+	g.next.ServeHTTP(writer, req)
+
+	if err := writer.finish(); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), g.name, typeName)).Debugf("Error while writing the gRPC-Web trailers: %v", err)
+	}
+}
+
+func (g *grpcWeb) servePreflight(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Access-Control-Allow-Origin", req.Header.Get("Origin"))
+	rw.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	if headers := req.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		rw.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+	rw.Header().Add("Vary", "Origin")
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (g *grpcWeb) isAllowedOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	for _, allowed := range g.allowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// textDecoder decodes a grpc-web-text body.
+// The clients encode each message separately, so the body is made of base64 chunks each having its own padding,
+// which is why the body is decoded one quantum of 4 characters at a time.
+type textDecoder struct {
+	src     io.Reader
+	buf     [512]byte
+	quantum []byte
+	decoded []byte
+	err     error
+}
+
+func (d *textDecoder) Read(p []byte) (int, error) {
+	for len(d.decoded) == 0 {
+		if d.err != nil {
+			if d.err == io.EOF && len(d.quantum) > 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, d.err
+		}
+
+		n, err := d.src.Read(d.buf[:])
+		d.err = err
+
+		for _, c := range d.buf[:n] {
+			if c == '\r' || c == '\n' {
+				continue
+			}
+
+			d.quantum = append(d.quantum, c)
+			if len(d.quantum) < 4 {
+				continue
+			}
+
+			var out [3]byte
+			m, err := base64.StdEncoding.Decode(out[:], d.quantum)
+			if err != nil {
+				d.err = err
+				break
+			}
+
+			d.decoded = append(d.decoded, out[:m]...)
+			d.quantum = d.quantum[:0]
+		}
+	}
+
+	n := copy(p, d.decoded)
+	d.decoded = d.decoded[n:]
+	return n, nil
+}
+
+// responseWriter converts the native gRPC response to a gRPC-Web response:
+// the trailers are sent at the end of the body, in a dedicated frame.
+type responseWriter struct {
+	rw           http.ResponseWriter
+	text         bool
+	wroteHeader  bool
+	trailerNames []string
+	body         io.Writer
+	encoder      io.WriteCloser
+}
+
+func newResponseWriter(rw http.ResponseWriter, text bool) *responseWriter {
+	w := &responseWriter{rw: rw, text: text, body: rw}
+
+	if text {
+		w.encoder = base64.NewEncoder(base64.StdEncoding, rw)
+		w.body = w.encoder
+	}
+
+	return w
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.rw.Header()
+
+	for _, value := range header["Trailer"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				w.trailerNames = append(w.trailerNames, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	header.Del("Trailer")
+	header.Del("Content-Length")
+
+	if contentType := header.Get("Content-Type"); strings.HasPrefix(contentType, contentTypeGrpc) {
+		webContentType := contentTypeWeb
+		if w.text {
+			webContentType = contentTypeWebText
+		}
+		header.Set("Content-Type", webContentType+strings.TrimPrefix(contentType, contentTypeGrpc))
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.body.Write(p)
+}
+
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// The encoder keeps the bytes of an incomplete base64 quantum:
+	// they are written padded, and the following bytes are encoded as a new chunk, as the clients expect.
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			return
+		}
+		w.encoder = base64.NewEncoder(base64.StdEncoding, w.rw)
+		w.body = w.encoder
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the trailers frame, once the response of the next handler has been written.
+func (w *responseWriter) finish() error {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	header := w.rw.Header()
+
+	trailers := &bytes.Buffer{}
+	writeTrailer := func(name string, values []string) {
+		for _, value := range values {
+			trailers.WriteString(strings.ToLower(name) + ": " + value + "\r\n")
+		}
+	}
+
+	for _, name := range w.trailerNames {
+		writeTrailer(name, header[name])
+		header.Del(name)
+	}
+
+	for key, values := range header {
+		if strings.HasPrefix(key, http.TrailerPrefix) {
+			writeTrailer(strings.TrimPrefix(key, http.TrailerPrefix), values)
+			delete(header, key)
+		}
+	}
+
+	if trailers.Len() > 0 {
+		frameHeader := make([]byte, 5)
+		frameHeader[0] = trailerFrameFlag
+		binary.BigEndian.PutUint32(frameHeader[1:], uint32(trailers.Len()))
+
+		if _, err := w.body.Write(append(frameHeader, trailers.Bytes()...)); err != nil {
+			return err
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grpcMessage is a gRPC data frame holding the "hello" message.
+var grpcMessage = []byte{0, 0, 0, 0, 5, 'h', 'e', 'l', 'l', 'o'}
+
+// grpcBackend behaves as a native gRPC server reached through the reverse proxy:
+// it echoes the request frames, and sends the status in the trailers.
+func grpcBackend(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/grpc+proto", req.Header.Get("Content-Type"))
+		assert.Equal(t, "trailers", req.Header.Get("Te"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		rw.Header().Set("Content-Type", "application/grpc+proto")
+		rw.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		rw.WriteHeader(http.StatusOK)
+
+		_, err = rw.Write(body)
+		require.NoError(t, err)
+
+		rw.Header().Set("Grpc-Status", "0")
+		rw.Header().Set("Grpc-Message", "OK")
+		rw.Header().Set(http.TrailerPrefix+"X-Custom", "foo")
+	})
+}
+
+func TestGrpcWeb(t *testing.T) {
+	// The trailer frame: the order of the announced trailers is kept, then the undeclared ones.
+	trailers := "grpc-status: 0\r\ngrpc-message: OK\r\nx-custom: foo\r\n"
+	trailerFrame := append([]byte{0x80, 0, 0, 0, byte(len(trailers))}, trailers...)
+	expectedBody := append(append([]byte{}, grpcMessage...), trailerFrame...)
+	expectedStreamBody := append(append(append([]byte{}, grpcMessage...), grpcMessage...), trailerFrame...)
+
+	testCases := []struct {
+		desc                string
+		contentType         string
+		body                []byte
+		expectedContentType string
+		expectedBody        []byte
+	}{
+		{
+			desc:                "binary",
+			contentType:         "application/grpc-web+proto",
+			body:                grpcMessage,
+			expectedContentType: "application/grpc-web+proto",
+			expectedBody:        expectedBody,
+		},
+		{
+			desc:                "text",
+			contentType:         "application/grpc-web-text+proto",
+			body:                []byte(base64.StdEncoding.EncodeToString(grpcMessage)),
+			expectedContentType: "application/grpc-web-text+proto",
+			expectedBody:        []byte(base64.StdEncoding.EncodeToString(expectedBody)),
+		},
+		{
+			desc:                "text with separately padded messages",
+			contentType:         "application/grpc-web-text+proto",
+			body:                []byte(base64.StdEncoding.EncodeToString(grpcMessage) + "\r\n" + base64.StdEncoding.EncodeToString(grpcMessage)),
+			expectedContentType: "application/grpc-web-text+proto",
+			expectedBody:        []byte(base64.StdEncoding.EncodeToString(expectedStreamBody)),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := New(context.Background(), grpcBackend(t), dynamic.GrpcWeb{}, "grpcweb")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Bar/Baz", bytes.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, test.expectedContentType, rec.Header().Get("Content-Type"))
+			assert.Empty(t, rec.Header().Get("Trailer"))
+			assert.Empty(t, rec.Header().Get("Grpc-Status"))
+			assert.Equal(t, test.expectedBody, rec.Body.Bytes())
+		})
+	}
+}
+
+func TestGrpcWeb_textFlush(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/grpc+proto")
+
+		_, err := rw.Write(grpcMessage)
+		require.NoError(t, err)
+		rw.(http.Flusher).Flush()
+
+		// The flushed message is fully written, the incomplete quantum being padded.
+		assert.Equal(t, base64.StdEncoding.EncodeToString(grpcMessage), rec.Body.String())
+
+		_, err = rw.Write(grpcMessage)
+		require.NoError(t, err)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.GrpcWeb{}, "grpcweb")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Bar/Baz", bytes.NewReader([]byte(base64.StdEncoding.EncodeToString(grpcMessage))))
+	req.Header.Set("Content-Type", "application/grpc-web-text+proto")
+
+	handler.ServeHTTP(rec, req)
+
+	expected := base64.StdEncoding.EncodeToString(grpcMessage) + base64.StdEncoding.EncodeToString(grpcMessage)
+	assert.Equal(t, expected, rec.Body.String())
+}
+
+func TestGrpcWeb_notGrpcWeb(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/grpc", req.Header.Get("Content-Type"))
+		rw.Header().Set("Content-Type", "application/grpc")
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.GrpcWeb{}, "grpcweb")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Bar/Baz", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "application/grpc", rec.Header().Get("Content-Type"))
+}
+
+func TestGrpcWeb_allowOrigins(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		allowOrigins   []string
+		origin         string
+		expectedOrigin string
+		expectedNext   bool
+	}{
+		{
+			desc:         "no allowed origins",
+			origin:       "http://foo.bar",
+			expectedNext: true,
+		},
+		{
+			desc:           "allowed origin",
+			allowOrigins:   []string{"http://foo.bar"},
+			origin:         "http://foo.bar",
+			expectedOrigin: "http://foo.bar",
+		},
+		{
+			desc:           "all origins allowed",
+			allowOrigins:   []string{"*"},
+			origin:         "http://foo.bar",
+			expectedOrigin: "http://foo.bar",
+		},
+		{
+			desc:         "other origin",
+			allowOrigins: []string{"http://bar.foo"},
+			origin:       "http://foo.bar",
+			expectedNext: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var nextCalled bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				nextCalled = true
+			})
+
+			handler, err := New(context.Background(), next, dynamic.GrpcWeb{AllowOrigins: test.allowOrigins}, "grpcweb")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodOptions, "http://localhost/foo.Bar/Baz", nil)
+			req.Header.Set("Origin", test.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type, x-grpc-web")
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedNext, nextCalled)
+			assert.Equal(t, test.expectedOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+			if !test.expectedNext {
+				assert.Equal(t, "content-type, x-grpc-web", rec.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
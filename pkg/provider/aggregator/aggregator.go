@@ -45,6 +45,10 @@ func NewProviderAggregator(conf static.Providers) ProviderAggregator {
 		p.quietAddProvider(conf.KubernetesCRD)
 	}
 
+	if conf.KubernetesGateway != nil {
+		p.quietAddProvider(conf.KubernetesGateway)
+	}
+
 	if conf.Rancher != nil {
 		p.quietAddProvider(conf.Rancher)
 	}
@@ -78,6 +82,11 @@ func (p *ProviderAggregator) AddProvider(provider provider.Provider) error {
 	return nil
 }
 
+// Empty returns whether no provider is aggregated.
+func (p ProviderAggregator) Empty() bool {
+	return p.fileProvider == nil && len(p.providers) == 0
+}
+
 // Init the provider
 func (p ProviderAggregator) Init() error {
 	return nil
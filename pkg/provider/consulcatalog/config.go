@@ -14,8 +14,9 @@ import (
 	"github.com/hashicorp/consul/api"
 )
 
-func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dynamic.Configuration {
+func (p *Provider) buildConfiguration(ctx context.Context, items []itemData, certInfo *connectCert) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	serversTransports := make(map[string]*dynamic.ServersTransport)
 
 	for _, item := range items {
 		svcName := item.Name + "-" + item.ID
@@ -27,6 +28,11 @@ func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dy
 
 		logger := log.FromContext(ctxSvc)
 
+		if item.ExtraConf.ConsulCatalog.Connect && !certInfo.isReady() {
+			logger.Debug("Filtering Connect-enabled item, the Connect certificates are not available")
+			continue
+		}
+
 		confFromLabel, err := label.DecodeConfiguration(item.Labels)
 		if err != nil {
 			logger.Error(err)
@@ -67,9 +73,18 @@ func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dy
 		provider.BuildRouterConfiguration(ctx, confFromLabel.HTTP, item.Name, p.defaultRuleTpl, model)
 
 		configurations[svcName] = confFromLabel
+
+		if item.ExtraConf.ConsulCatalog.Connect {
+			serversTransports[connectTransportName(item)] = certInfo.serversTransport(item)
+		}
+	}
+
+	configuration := provider.Merge(ctx, configurations)
+	if len(serversTransports) > 0 {
+		configuration.HTTP.ServersTransports = serversTransports
 	}
 
-	return provider.Merge(ctx, configurations)
+	return configuration
 }
 
 func (p *Provider) keepContainer(ctx context.Context, item itemData) bool {
@@ -90,6 +105,11 @@ func (p *Provider) keepContainer(ctx context.Context, item itemData) bool {
 		return false
 	}
 
+	if item.ExtraConf.ConsulCatalog.Connect && !p.ConnectAware {
+		logger.Debug("Filtering Connect-enabled item, Consul Connect support is disabled")
+		return false
+	}
+
 	if item.Status != api.HealthPassing && item.Status != api.HealthWarning {
 		logger.Debug("Filtering unhealthy or starting item")
 		return false
@@ -193,8 +213,20 @@ func (p *Provider) addServer(ctx context.Context, item itemData, loadBalancer *d
 		return errors.New("port is missing")
 	}
 
-	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(item.Address, port))
+	scheme := loadBalancer.Servers[0].Scheme
+	if item.ExtraConf.ConsulCatalog.Connect {
+		// The Connect-enabled services are reached with mutual TLS.
+		scheme = "https"
+		loadBalancer.ServersTransport = connectTransportName(item)
+	}
+
+	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(item.Address, port))
 	loadBalancer.Servers[0].Scheme = ""
 
 	return nil
 }
+
+// connectTransportName returns the name of the servers transport used to reach the given Connect-enabled item.
+func connectTransportName(item itemData) string {
+	return "tls-" + item.Name
+}
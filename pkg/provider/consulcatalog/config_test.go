@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
 	"github.com/hashicorp/consul/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -238,7 +239,7 @@ func TestDefaultRule(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			configuration := p.buildConfiguration(context.Background(), test.items)
+			configuration := p.buildConfiguration(context.Background(), test.items, nil)
 
 			assert.Equal(t, test.expected, configuration)
 		})
@@ -247,10 +248,11 @@ func TestDefaultRule(t *testing.T) {
 
 func Test_buildConfiguration(t *testing.T) {
 	testCases := []struct {
-		desc        string
-		items       []itemData
-		constraints string
-		expected    *dynamic.Configuration
+		desc         string
+		items        []itemData
+		constraints  string
+		connectAware bool
+		expected     *dynamic.Configuration
 	}{
 		{
 			desc: "one container no label",
@@ -1819,6 +1821,101 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "two Connect-enabled containers",
+			items: []itemData{
+				{
+					ID:         "1",
+					Name:       "Test",
+					Datacenter: "dc1",
+					Labels: map[string]string{
+						"traefik.consulcatalog.connect": "true",
+					},
+					Address: "127.0.0.1",
+					Port:    "20000",
+					Status:  api.HealthPassing,
+				},
+				{
+					ID:         "2",
+					Name:       "Test",
+					Datacenter: "dc1",
+					Labels: map[string]string{
+						"traefik.consulcatalog.connect": "true",
+					},
+					Address: "127.0.0.2",
+					Port:    "20000",
+					Status:  api.HealthPassing,
+				},
+			},
+			connectAware: true,
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Test",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Test": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "https://127.0.0.1:20000",
+									},
+									{
+										URL: "https://127.0.0.2:20000",
+									},
+								},
+								PassHostHeader:   Bool(true),
+								ServersTransport: "tls-Test",
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{
+						"tls-Test": {
+							RootCAs: []traefiktls.FileOrContent{"root"},
+							Certificates: traefiktls.Certificates{
+								{CertFile: "cert", KeyFile: "key"},
+							},
+							PeerCertURI: "spiffe://test.consul/ns/default/dc/dc1/svc/Test",
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "one Connect-enabled container with Connect support disabled",
+			items: []itemData{
+				{
+					ID:         "1",
+					Name:       "Test",
+					Datacenter: "dc1",
+					Labels: map[string]string{
+						"traefik.consulcatalog.connect": "true",
+					},
+					Address: "127.0.0.1",
+					Port:    "20000",
+					Status:  api.HealthPassing,
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1832,6 +1929,7 @@ func Test_buildConfiguration(t *testing.T) {
 				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
 			}
 			p.Constraints = test.constraints
+			p.ConnectAware = test.connectAware
 
 			err := p.Init()
 			require.NoError(t, err)
@@ -1842,9 +1940,87 @@ func Test_buildConfiguration(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			configuration := p.buildConfiguration(context.Background(), test.items)
+			certInfo := &connectCert{
+				trustDomain: "test.consul",
+				root:        []string{"root"},
+				leaf:        keyPair{cert: "cert", key: "key"},
+			}
+
+			configuration := p.buildConfiguration(context.Background(), test.items, certInfo)
 
 			assert.Equal(t, test.expected, configuration)
 		})
 	}
 }
+
+func Test_buildConfiguration_connectNotReady(t *testing.T) {
+	p := Provider{
+		ExposedByDefault: true,
+		DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+		ConnectAware:     true,
+	}
+
+	err := p.Init()
+	require.NoError(t, err)
+
+	items := []itemData{
+		{
+			ID:         "1",
+			Name:       "Connect",
+			Datacenter: "dc1",
+			Labels: map[string]string{
+				"traefik.consulcatalog.connect": "true",
+			},
+			Address: "127.0.0.1",
+			Port:    "20000",
+			Status:  api.HealthPassing,
+		},
+		{
+			ID:         "2",
+			Name:       "Plain",
+			Datacenter: "dc1",
+			Labels:     map[string]string{},
+			Address:    "127.0.0.2",
+			Port:       "80",
+			Status:     api.HealthPassing,
+		},
+	}
+
+	for i := 0; i < len(items); i++ {
+		items[i].ExtraConf, err = p.getConfiguration(items[i])
+		require.NoError(t, err)
+	}
+
+	// Only the Connect-enabled services wait for the certificates.
+	configuration := p.buildConfiguration(context.Background(), items, &connectCert{})
+
+	expected := &dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  map[string]*dynamic.TCPRouter{},
+			Services: map[string]*dynamic.TCPService{},
+		},
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"Plain": {
+					Service: "Plain",
+					Rule:    "Host(`Plain.traefik.wtf`)",
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{},
+			Services: map[string]*dynamic.Service{
+				"Plain": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{
+							{
+								URL: "http://127.0.0.2:80",
+							},
+						},
+						PassHostHeader: Bool(true),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, configuration)
+}
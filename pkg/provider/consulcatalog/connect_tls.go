@@ -0,0 +1,118 @@
+package consulcatalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/hashicorp/consul/api"
+)
+
+// connectCert holds the certificates fetched from the Consul agent to reach the Connect-enabled services.
+type connectCert struct {
+	trustDomain string
+	root        []string
+	leaf        keyPair
+}
+
+type keyPair struct {
+	cert string
+	key  string
+}
+
+// connectRoots holds the CA roots of the Connect trust domain.
+type connectRoots struct {
+	trustDomain string
+	certs       []string
+}
+
+func (c *connectCert) isReady() bool {
+	return c != nil && len(c.root) > 0 && c.leaf.cert != "" && c.leaf.key != ""
+}
+
+func (c *connectCert) getRoot() []traefiktls.FileOrContent {
+	var result []traefiktls.FileOrContent
+	for _, r := range c.root {
+		result = append(result, traefiktls.FileOrContent(r))
+	}
+	return result
+}
+
+func (c *connectCert) getLeaf() traefiktls.Certificate {
+	return traefiktls.Certificate{
+		CertFile: traefiktls.FileOrContent(c.leaf.cert),
+		KeyFile:  traefiktls.FileOrContent(c.leaf.key),
+	}
+}
+
+// peerCertURI returns the SPIFFE ID of the given service, expected in the certificate presented by its instances.
+func (c *connectCert) peerCertURI(datacenter, serviceName string) string {
+	return fmt.Sprintf("spiffe://%s/ns/default/dc/%s/svc/%s", c.trustDomain, datacenter, serviceName)
+}
+
+// serversTransport returns the transport used to reach the instances of the given Connect-enabled service.
+func (c *connectCert) serversTransport(item itemData) *dynamic.ServersTransport {
+	return &dynamic.ServersTransport{
+		RootCAs:      c.getRoot(),
+		Certificates: traefiktls.Certificates{c.getLeaf()},
+		PeerCertURI:  c.peerCertURI(item.Datacenter, item.Name),
+	}
+}
+
+// watchConnectRoots sends the CA roots to the given channel each time they change, until the context is done.
+func (p *Provider) watchConnectRoots(ctx context.Context, rootsChan chan<- connectRoots) error {
+	var index uint64
+	for {
+		opts := &api.QueryOptions{WaitIndex: index}
+		roots, meta, err := p.client.Agent().ConnectCARoots(opts.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch Connect CA roots: %v", err)
+		}
+
+		if meta.LastIndex == index {
+			continue
+		}
+		index = meta.LastIndex
+
+		result := connectRoots{trustDomain: roots.TrustDomain}
+		for _, root := range roots.Roots {
+			result.certs = append(result.certs, root.RootCertPEM)
+		}
+
+		select {
+		case rootsChan <- result:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// watchConnectLeaf sends the leaf certificate of Traefik to the given channel each time it is renewed, until the context is done.
+func (p *Provider) watchConnectLeaf(ctx context.Context, leafChan chan<- keyPair) error {
+	var index uint64
+	for {
+		opts := &api.QueryOptions{WaitIndex: index}
+		leaf, meta, err := p.client.Agent().ConnectCALeaf(p.ServiceName, opts.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch Connect leaf certificate: %v", err)
+		}
+
+		if meta.LastIndex == index {
+			continue
+		}
+		index = meta.LastIndex
+
+		select {
+		case leafChan <- keyPair{cert: leaf.CertPEM, key: leaf.PrivateKeyPEM}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
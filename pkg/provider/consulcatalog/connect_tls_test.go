@@ -0,0 +1,231 @@
+package consulcatalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent is a fake Consul agent HTTP API, serving the catalog and the Connect CA endpoints.
+// The Connect CA endpoints support blocking queries.
+type fakeAgent struct {
+	mu      sync.Mutex
+	index   uint64
+	changed chan struct{}
+	roots   api.CARootList
+	leaf    api.LeafCert
+
+	services map[string][]string
+	catalog  map[string][]*api.CatalogService
+	connect  map[string][]*api.CatalogService
+}
+
+func newFakeAgent() *fakeAgent {
+	return &fakeAgent{
+		index:   1,
+		changed: make(chan struct{}),
+		roots: api.CARootList{
+			TrustDomain: "test.consul",
+			Roots:       []*api.CARoot{{ID: "root1", RootCertPEM: "root1", Active: true}},
+		},
+		leaf: api.LeafCert{CertPEM: "cert1", PrivateKeyPEM: "key1", Service: "traefik"},
+	}
+}
+
+// rotateLeaf replaces the leaf certificate, and unblocks the pending queries.
+func (f *fakeAgent) rotateLeaf(cert, key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.leaf.CertPEM = cert
+	f.leaf.PrivateKeyPEM = key
+	f.index++
+
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// wait blocks until the index is greater than the one of the query.
+func (f *fakeAgent) wait(req *http.Request) {
+	f.mu.Lock()
+	changed := f.changed
+	index := f.index
+	f.mu.Unlock()
+
+	queryIndex, _ := strconv.ParseUint(req.URL.Query().Get("index"), 10, 64)
+	if queryIndex < index {
+		return
+	}
+
+	select {
+	case <-changed:
+	case <-req.Context().Done():
+	}
+}
+
+func (f *fakeAgent) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var result interface{}
+
+	switch path := req.URL.Path; {
+	case path == "/v1/agent/connect/ca/roots":
+		f.wait(req)
+		result = f.roots
+	case path == "/v1/agent/connect/ca/leaf/traefik":
+		f.wait(req)
+		result = f.leaf
+	case path == "/v1/catalog/services":
+		result = f.services
+	case strings.HasPrefix(path, "/v1/catalog/service/"):
+		result = f.catalog[strings.TrimPrefix(path, "/v1/catalog/service/")]
+	case strings.HasPrefix(path, "/v1/catalog/connect/"):
+		result = f.connect[strings.TrimPrefix(path, "/v1/catalog/connect/")]
+	default:
+		http.NotFound(rw, req)
+		return
+	}
+
+	f.mu.Lock()
+	rw.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	err := json.NewEncoder(rw).Encode(result)
+	f.mu.Unlock()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func newFakeAgentProvider(t *testing.T, srv *httptest.Server) *Provider {
+	t.Helper()
+
+	p := &Provider{}
+	p.SetDefaults()
+	p.Endpoint.Address = srv.URL
+	p.ConnectAware = true
+
+	var err error
+	p.client, err = createClient(p.Endpoint)
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestProvider_watchConnectRoots(t *testing.T) {
+	agent := newFakeAgent()
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	p := newFakeAgentProvider(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rootsChan := make(chan connectRoots)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- p.watchConnectRoots(ctx, rootsChan)
+	}()
+
+	select {
+	case roots := <-rootsChan:
+		assert.Equal(t, connectRoots{trustDomain: "test.consul", certs: []string{"root1"}}, roots)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the CA roots")
+	}
+
+	cancel()
+	assert.NoError(t, <-errChan)
+}
+
+func TestProvider_watchConnectLeaf(t *testing.T) {
+	agent := newFakeAgent()
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	p := newFakeAgentProvider(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	leafChan := make(chan keyPair)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- p.watchConnectLeaf(ctx, leafChan)
+	}()
+
+	select {
+	case leaf := <-leafChan:
+		assert.Equal(t, keyPair{cert: "cert1", key: "key1"}, leaf)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the leaf certificate")
+	}
+
+	agent.rotateLeaf("cert2", "key2")
+
+	select {
+	case leaf := <-leafChan:
+		assert.Equal(t, keyPair{cert: "cert2", key: "key2"}, leaf)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the renewed leaf certificate")
+	}
+
+	cancel()
+	assert.NoError(t, <-errChan)
+}
+
+func TestProvider_getConsulServicesData_connect(t *testing.T) {
+	agent := newFakeAgent()
+	agent.services = map[string][]string{
+		"web":   {"traefik.consulcatalog.connect=true"},
+		"other": {},
+	}
+	agent.catalog = map[string][]*api.CatalogService{
+		"other": {{
+			ServiceID:      "other1",
+			ServiceName:    "other",
+			Datacenter:     "dc1",
+			ServiceAddress: "10.0.0.1",
+			ServicePort:    80,
+		}},
+	}
+	agent.connect = map[string][]*api.CatalogService{
+		"web": {{
+			ServiceID:      "web-sidecar-proxy1",
+			ServiceName:    "web-sidecar-proxy",
+			Datacenter:     "dc1",
+			ServiceAddress: "10.0.0.2",
+			ServicePort:    20000,
+			ServiceTags:    []string{"traefik.consulcatalog.connect=true"},
+		}},
+	}
+
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	p := newFakeAgentProvider(t, srv)
+
+	data, err := p.getConsulServicesData(context.Background())
+	require.NoError(t, err)
+
+	items := make(map[string]itemData)
+	for _, item := range data {
+		items[item.Name] = item
+	}
+
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "10.0.0.1", items["other"].Address)
+	assert.False(t, items["other"].ExtraConf.ConsulCatalog.Connect)
+
+	assert.Equal(t, "web-sidecar-proxy1", items["web"].ID)
+	assert.Equal(t, "dc1", items["web"].Datacenter)
+	assert.Equal(t, "10.0.0.2", items["web"].Address)
+	assert.Equal(t, "20000", items["web"].Port)
+	assert.True(t, items["web"].ExtraConf.ConsulCatalog.Connect)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/cenkalti/backoff/v3"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/label"
 	"github.com/containous/traefik/v2/pkg/job"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/provider"
@@ -23,13 +24,14 @@ const DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
 var _ provider.Provider = (*Provider)(nil)
 
 type itemData struct {
-	ID        string
-	Name      string
-	Address   string
-	Port      string
-	Status    string
-	Labels    map[string]string
-	ExtraConf configuration
+	ID         string
+	Name       string
+	Datacenter string
+	Address    string
+	Port       string
+	Status     string
+	Labels     map[string]string
+	ExtraConf  configuration
 }
 
 // Provider holds configurations of the provider.
@@ -43,6 +45,8 @@ type Provider struct {
 	Cache             bool            `description:"Use local agent caching for catalog reads." json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" export:"true"`
 	ExposedByDefault  bool            `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	DefaultRule       string          `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	ConnectAware      bool            `description:"Enable Consul Connect support." json:"connectAware,omitempty" toml:"connectAware,omitempty" yaml:"connectAware,omitempty" export:"true"`
+	ServiceName       string          `description:"Name of the Traefik service in Consul Catalog, used as its identity in Consul Connect." json:"serviceName,omitempty" toml:"serviceName,omitempty" yaml:"serviceName,omitempty" export:"true"`
 
 	client         *api.Client
 	defaultRuleTpl *template.Template
@@ -79,6 +83,7 @@ func (p *Provider) SetDefaults() {
 	p.Prefix = "traefik"
 	p.ExposedByDefault = true
 	p.DefaultRule = DefaultTemplateRule
+	p.ServiceName = "traefik"
 }
 
 // Init the provider.
@@ -106,26 +111,56 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 				return fmt.Errorf("error create consul client, %v", err)
 			}
 
+			ctxWatch, cancel := context.WithCancel(routineCtx)
+			defer cancel()
+
+			rootsChan := make(chan connectRoots)
+			leafChan := make(chan keyPair)
+			errChan := make(chan error, 2)
+
+			if p.ConnectAware {
+				safe.Go(func() {
+					errChan <- p.watchConnectRoots(ctxWatch, rootsChan)
+				})
+				safe.Go(func() {
+					errChan <- p.watchConnectLeaf(ctxWatch, leafChan)
+				})
+			}
+
+			certInfo := &connectCert{}
+
 			ticker := time.NewTicker(time.Duration(p.RefreshInterval))
+			defer ticker.Stop()
 
 			for {
 				select {
 				case <-ticker.C:
-					data, err := p.getConsulServicesData(routineCtx)
+				case roots := <-rootsChan:
+					certInfo.trustDomain = roots.trustDomain
+					certInfo.root = roots.certs
+				case leaf := <-leafChan:
+					certInfo.leaf = leaf
+				case err := <-errChan:
 					if err != nil {
-						logger.Errorf("error get consul catalog data, %v", err)
-						return err
-					}
-
-					configuration := p.buildConfiguration(routineCtx, data)
-					configurationChan <- dynamic.Message{
-						ProviderName:  "consulcatalog",
-						Configuration: configuration,
+						logger.Errorf("error watching Connect certificates, %v", err)
 					}
+					return err
 				case <-routineCtx.Done():
-					ticker.Stop()
 					return nil
 				}
+
+				// The Connect-enabled services are left out of the configuration until the certificates are available.
+				data, err := p.getConsulServicesData(routineCtx)
+				if err != nil {
+					logger.Errorf("error get consul catalog data, %v", err)
+					return err
+				}
+
+				configuration := p.buildConfiguration(routineCtx, data, certInfo)
+				configurationChan <- dynamic.Message{
+					ProviderName:  "consulcatalog",
+					Configuration: configuration,
+				}
 			}
 		}
 
@@ -149,8 +184,10 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 	}
 
 	var data []itemData
-	for name := range consulServiceNames {
-		consulServices, err := p.fetchService(ctx, name)
+	for name, tags := range consulServiceNames {
+		connect := p.ConnectAware && p.isConnectEnabled(tags)
+
+		consulServices, err := p.fetchService(ctx, name, connect)
 		if err != nil {
 			return nil, err
 		}
@@ -158,12 +195,13 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 		for _, consulService := range consulServices {
 			labels := tagsToNeutralLabels(consulService.ServiceTags, p.Prefix)
 			item := itemData{
-				ID:      consulService.ServiceID,
-				Name:    consulService.ServiceName,
-				Address: consulService.ServiceAddress,
-				Port:    strconv.Itoa(consulService.ServicePort),
-				Labels:  labels,
-				Status:  consulService.Checks.AggregatedStatus(),
+				ID:         consulService.ServiceID,
+				Name:       name,
+				Datacenter: consulService.Datacenter,
+				Address:    consulService.ServiceAddress,
+				Port:       strconv.Itoa(consulService.ServicePort),
+				Labels:     labels,
+				Status:     consulService.Checks.AggregatedStatus(),
 			}
 
 			extraConf, err := p.getConfiguration(item)
@@ -179,13 +217,33 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 	return data, nil
 }
 
-func (p *Provider) fetchService(ctx context.Context, name string) ([]*api.CatalogService, error) {
+// isConnectEnabled tells whether the service with the given tags is Connect-enabled.
+func (p *Provider) isConnectEnabled(tags []string) bool {
+	conf := configuration{}
+
+	err := label.Decode(tagsToNeutralLabels(tags, p.Prefix), &conf, "traefik.consulcatalog.connect")
+	if err != nil {
+		return false
+	}
+
+	return conf.ConsulCatalog.Connect
+}
+
+// fetchService returns the instances of the given service,
+// or the instances of its Connect-native services and Connect proxies if the service is Connect-enabled.
+func (p *Provider) fetchService(ctx context.Context, name string, connect bool) ([]*api.CatalogService, error) {
 	var tagFilter string
 	if !p.ExposedByDefault {
 		tagFilter = p.Prefix + ".enable=true"
 	}
 
 	opts := &api.QueryOptions{AllowStale: p.Stale, RequireConsistent: p.RequireConsistent, UseCache: p.Cache}
+
+	if connect {
+		consulServices, _, err := p.client.Catalog().Connect(name, tagFilter, opts)
+		return consulServices, err
+	}
+
 	consulServices, _, err := p.client.Catalog().Service(name, tagFilter, opts)
 	return consulServices, err
 }
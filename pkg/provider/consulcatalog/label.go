@@ -6,7 +6,12 @@ import (
 
 // configuration Contains information from the labels that are globals (not related to the dynamic configuration) or specific to the provider.
 type configuration struct {
-	Enable bool
+	Enable        bool
+	ConsulCatalog specificConfiguration
+}
+
+type specificConfiguration struct {
+	Connect bool
 }
 
 func (p *Provider) getConfiguration(item itemData) (configuration, error) {
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/label"
+	"github.com/containous/traefik/v2/pkg/config/parser"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/provider"
 	"github.com/containous/traefik/v2/pkg/provider/constraints"
@@ -18,7 +21,18 @@ import (
 func (p *Provider) buildConfiguration(ctx context.Context, containersInspected []dockerData) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
 
-	for _, container := range containersInspected {
+	// Traefik labels of the replica kept first for each Docker Compose service:
+	// the other replicas must share them to join the same load balancer.
+	// The containers are sorted by ID for this replica not to depend on the order they are listed in.
+	composeLabels := make(map[string]composeReplica)
+
+	containers := make([]dockerData, len(containersInspected))
+	copy(containers, containersInspected)
+	sort.SliceStable(containers, func(i, j int) bool {
+		return containers[i].ID < containers[j].ID
+	})
+
+	for _, container := range containers {
 		containerName := getServiceName(container) + "-" + container.ID
 		ctxContainer := log.With(ctx, log.Str("container", containerName))
 
@@ -28,6 +42,16 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 
 		logger := log.FromContext(ctxContainer)
 
+		if group, ok := getComposeGroup(container); ok {
+			labels := getTraefikLabels(container.Labels)
+			if reference, exists := composeLabels[group]; !exists {
+				composeLabels[group] = composeReplica{containerID: container.ID, labels: labels}
+			} else if !reflect.DeepEqual(reference.labels, labels) {
+				logger.Warnf("Skipping replica: its labels differ from the ones of the replica %s kept for the Compose service %s", reference.containerID, group)
+				continue
+			}
+		}
+
 		confFromLabel, err := label.DecodeConfiguration(container.Labels)
 		if err != nil {
 			logger.Error(err)
@@ -138,7 +162,7 @@ func (p *Provider) keepContainer(ctx context.Context, container dockerData) bool
 	}
 
 	if container.Health != "" && container.Health != "healthy" {
-		logger.Debug("Filtering unhealthy or starting container")
+		logger.Debugf("Filtering container with health status %q", container.Health)
 		return false
 	}
 
@@ -241,7 +265,7 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 	if container.ExtraConf.Docker.Network != "" {
 		settings := container.NetworkSettings
 		if settings.Networks != nil {
-			network := settings.Networks[container.ExtraConf.Docker.Network]
+			network := getNetwork(container, container.ExtraConf.Docker.Network)
 			if network != nil {
 				return network.Addr
 			}
@@ -273,14 +297,46 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 		return p.getIPAddress(ctx, parseContainer(containerInspected))
 	}
 
-	for _, network := range container.NetworkSettings.Networks {
-		return network.Addr
+	var names []string
+	for name := range container.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Prefer a network shared with Traefik, the other ones might not be reachable.
+	for _, name := range names {
+		network := container.NetworkSettings.Networks[name]
+		if _, ok := p.traefikNetworks[network.ID]; ok {
+			return network.Addr
+		}
+	}
+
+	if len(names) > 0 {
+		if len(names) > 1 {
+			logger.Debugf("No network shared with Traefik for container %s, using the network %s.", container.Name, names[0])
+		}
+		return container.NetworkSettings.Networks[names[0]].Addr
 	}
 
 	logger.Warn("Unable to find the IP address.")
 	return ""
 }
 
+// getNetwork returns the named network of the container.
+// Docker Compose prefixes the network names with the project name,
+// so the prefixed name is also looked up for containers started by Docker Compose.
+func getNetwork(container dockerData, name string) *networkData {
+	if network := container.NetworkSettings.Networks[name]; network != nil {
+		return network
+	}
+
+	if project := getStringValue(container.Labels, labelDockerComposeProject, ""); project != "" {
+		return container.NetworkSettings.Networks[project+"_"+name]
+	}
+
+	return nil
+}
+
 func (p *Provider) getPortBinding(container dockerData, serverPort string) (*nat.PortBinding, error) {
 	port := getPort(container, serverPort)
 	for netPort, portBindings := range container.NetworkSettings.Ports {
@@ -324,6 +380,34 @@ func getPort(container dockerData, serverPort string) string {
 	return ""
 }
 
+// composeReplica is the replica whose Traefik labels are used for its Docker Compose service.
+type composeReplica struct {
+	containerID string
+	labels      map[string]string
+}
+
+// getComposeGroup returns the Docker Compose project and service the container is a replica of.
+func getComposeGroup(container dockerData) (string, bool) {
+	values, err := getStringMultipleStrict(container.Labels, labelDockerComposeProject, labelDockerComposeService)
+	if err != nil {
+		return "", false
+	}
+
+	return values[labelDockerComposeProject] + "/" + values[labelDockerComposeService], true
+}
+
+// getTraefikLabels returns the labels used to build the container configuration.
+func getTraefikLabels(labels map[string]string) map[string]string {
+	traefikLabels := make(map[string]string)
+	for key, value := range labels {
+		if strings.HasPrefix(key, parser.DefaultRootName+".") {
+			traefikLabels[key] = value
+		}
+	}
+
+	return traefikLabels
+}
+
 func getServiceName(container dockerData) string {
 	serviceName := container.ServiceName
 
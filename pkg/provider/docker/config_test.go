@@ -2401,6 +2401,307 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "docker compose replicas grouped in one service, unhealthy replica filtered",
+			containers: []dockerData{
+				{
+					ID:          "1",
+					ServiceName: "app_web_1",
+					Name:        "app_web_1",
+					Labels: map[string]string{
+						labelDockerComposeProject: "app",
+						labelDockerComposeService: "web",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+					Health: "healthy",
+				},
+				{
+					ID:          "2",
+					ServiceName: "app_web_2",
+					Name:        "app_web_2",
+					Labels: map[string]string{
+						labelDockerComposeProject: "app",
+						labelDockerComposeService: "web",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.2",
+							},
+						},
+					},
+				},
+				{
+					ID:          "3",
+					ServiceName: "app_web_3",
+					Name:        "app_web_3",
+					Labels: map[string]string{
+						labelDockerComposeProject: "app",
+						labelDockerComposeService: "web",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.3",
+							},
+						},
+					},
+					Health: "starting",
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"web-app": {
+							Service: "web-app",
+							Rule:    "Host(`web-app.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"web-app": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:80",
+									},
+									{
+										URL: "http://127.0.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "docker compose replica with diverging labels skipped",
+			containers: []dockerData{
+				{
+					ID:          "1",
+					ServiceName: "app_web_1",
+					Name:        "app_web_1",
+					Labels: map[string]string{
+						labelDockerComposeProject:                            "app",
+						labelDockerComposeService:                            "web",
+						"com.docker.compose.container-number":                "1",
+						"traefik.http.services.web.loadbalancer.server.port": "8080",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+				{
+					ID:          "2",
+					ServiceName: "app_web_2",
+					Name:        "app_web_2",
+					Labels: map[string]string{
+						labelDockerComposeProject:                            "app",
+						labelDockerComposeService:                            "web",
+						"com.docker.compose.container-number":                "2",
+						"traefik.http.services.web.loadbalancer.server.port": "8080",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.2",
+							},
+						},
+					},
+				},
+				{
+					ID:          "3",
+					ServiceName: "app_web_3",
+					Name:        "app_web_3",
+					Labels: map[string]string{
+						labelDockerComposeProject:                               "app",
+						labelDockerComposeService:                               "web",
+						"com.docker.compose.container-number":                   "3",
+						"traefik.http.services.web.loadbalancer.server.port":    "8080",
+						"traefik.http.services.web.loadbalancer.passhostheader": "false",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.3",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"web-app": {
+							Service: "web",
+							Rule:    "Host(`web-app.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"web": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:8080",
+									},
+									{
+										URL: "http://127.0.0.2:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "docker compose replica with diverging labels listed first skipped",
+			containers: []dockerData{
+				{
+					ID:          "3",
+					ServiceName: "app_web_3",
+					Name:        "app_web_3",
+					Labels: map[string]string{
+						labelDockerComposeProject:                               "app",
+						labelDockerComposeService:                               "web",
+						"com.docker.compose.container-number":                   "3",
+						"traefik.http.services.web.loadbalancer.server.port":    "8080",
+						"traefik.http.services.web.loadbalancer.passhostheader": "false",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.3",
+							},
+						},
+					},
+				},
+				{
+					ID:          "1",
+					ServiceName: "app_web_1",
+					Name:        "app_web_1",
+					Labels: map[string]string{
+						labelDockerComposeProject:                            "app",
+						labelDockerComposeService:                            "web",
+						"com.docker.compose.container-number":                "1",
+						"traefik.http.services.web.loadbalancer.server.port": "8080",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+				{
+					ID:          "2",
+					ServiceName: "app_web_2",
+					Name:        "app_web_2",
+					Labels: map[string]string{
+						labelDockerComposeProject:                            "app",
+						labelDockerComposeService:                            "web",
+						"com.docker.compose.container-number":                "2",
+						"traefik.http.services.web.loadbalancer.server.port": "8080",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"app_default": {
+								Name: "app_default",
+								Addr: "127.0.0.2",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"web-app": {
+							Service: "web",
+							Rule:    "Host(`web-app.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"web": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:8080",
+									},
+									{
+										URL: "http://127.0.0.2:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "tcp with label for tcp service, with termination delay",
 			containers: []dockerData{
@@ -2723,10 +3024,11 @@ func TestDockerGetPort(t *testing.T) {
 
 func TestDockerGetIPAddress(t *testing.T) {
 	testCases := []struct {
-		desc      string
-		container docker.ContainerJSON
-		network   string
-		expected  string
+		desc            string
+		container       docker.ContainerJSON
+		network         string
+		traefikNetworks []string
+		expected        string
 	}{
 		{
 			desc:      "one network, no network label",
@@ -2777,6 +3079,36 @@ func TestDockerGetIPAddress(t *testing.T) {
 			network:  "testnet",
 			expected: "10.11.12.13",
 		},
+		{
+			desc: "two networks, no network label, network shared with traefik",
+			container: containerJSON(
+				withNetwork("backend", ipv4("10.11.12.13"), networkID("backend-id")),
+				withNetwork("frontend", ipv4("10.11.12.14"), networkID("frontend-id")),
+			),
+			network:         "unknown",
+			traefikNetworks: []string{"frontend-id"},
+			expected:        "10.11.12.14",
+		},
+		{
+			desc: "two networks, no network label, no network shared with traefik",
+			container: containerJSON(
+				withNetwork("frontend", ipv4("10.11.12.14"), networkID("frontend-id")),
+				withNetwork("backend", ipv4("10.11.12.13"), networkID("backend-id")),
+			),
+			network:         "unknown",
+			traefikNetworks: []string{"other-id"},
+			expected:        "10.11.12.13",
+		},
+		{
+			desc: "two networks, network label without the docker compose project prefix",
+			container: containerJSON(
+				labels(map[string]string{labelDockerComposeProject: "app"}),
+				withNetwork("app_backend", ipv4("10.11.12.13")),
+				withNetwork("app_frontend", ipv4("10.11.12.14")),
+			),
+			network:  "frontend",
+			expected: "10.11.12.14",
+		},
 		{
 			desc: "no network, no network label, mode host",
 			container: containerJSON(
@@ -2800,7 +3132,12 @@ func TestDockerGetIPAddress(t *testing.T) {
 			t.Parallel()
 
 			provider := &Provider{
-				Network: "webnet",
+				Network:         "webnet",
+				traefikNetworks: map[string]struct{}{},
+			}
+
+			for _, id := range test.traefikNetworks {
+				provider.traefikNetworks[id] = struct{}{}
 			}
 
 			dData := parseContainer(test.container)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"text/template"
@@ -56,6 +57,7 @@ type Provider struct {
 	Network                 string           `description:"Default Docker network used." json:"network,omitempty" toml:"network,omitempty" yaml:"network,omitempty" export:"true"`
 	SwarmModeRefreshSeconds types.Duration   `description:"Polling interval for swarm mode." json:"swarmModeRefreshSeconds,omitempty" toml:"swarmModeRefreshSeconds,omitempty" yaml:"swarmModeRefreshSeconds,omitempty" export:"true"`
 	defaultRuleTpl          *template.Template
+	traefikNetworks         map[string]struct{} // IDs of the networks of the Traefik container, if any.
 }
 
 // SetDefaults sets the default values.
@@ -305,6 +307,8 @@ func (p *Provider) listContainers(ctx context.Context, dockerClient client.Conta
 		return nil, err
 	}
 
+	p.traefikNetworks = lookupTraefikNetworks(ctx, dockerClient)
+
 	var inspectedContainers []dockerData
 	// get inspect containers
 	for _, container := range containerList {
@@ -325,6 +329,32 @@ func (p *Provider) listContainers(ctx context.Context, dockerClient client.Conta
 	return inspectedContainers, nil
 }
 
+// lookupTraefikNetworks returns the IDs of the networks Traefik is attached to, when Traefik runs in a container.
+// Docker sets the hostname of a container to its ID, unless overridden.
+func lookupTraefikNetworks(ctx context.Context, dockerClient client.ContainerAPIClient) map[string]struct{} {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil
+	}
+
+	containerInspected, err := dockerClient.ContainerInspect(ctx, hostname)
+	if err != nil {
+		log.FromContext(ctx).Debugf("Traefik does not seem to run in a container, no network shared with the containers: %v", err)
+		return nil
+	}
+
+	if containerInspected.NetworkSettings == nil {
+		return nil
+	}
+
+	networks := make(map[string]struct{})
+	for _, network := range containerInspected.NetworkSettings.Networks {
+		networks[network.NetworkID] = struct{}{}
+	}
+
+	return networks
+}
+
 func inspectContainers(ctx context.Context, dockerClient client.ContainerAPIClient, containerID string) dockerData {
 	containerInspected, err := dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
@@ -50,12 +51,16 @@ type Client interface {
 	GetIngressRouteTCPs() []*v1alpha1.IngressRouteTCP
 	GetMiddlewares() []*v1alpha1.Middleware
 	GetTLSOptions() []*v1alpha1.TLSOption
+	GetTLSStores() []*v1alpha1.TLSStore
 
 	GetIngresses() []*extensionsv1beta1.Ingress
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
 	UpdateIngressStatus(namespace, name, ip, hostname string) error
+	UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute, status v1alpha1.ResourceStatus) error
+	UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP, status v1alpha1.ResourceStatus) error
+	UpdateMiddlewareStatus(middleware *v1alpha1.Middleware, status v1alpha1.ResourceStatus) error
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -160,6 +165,7 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		factoryCrd.Traefik().V1alpha1().Middlewares().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().IngressRouteTCPs().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().TLSOptions().Informer().AddEventHandler(eventHandler)
+		factoryCrd.Traefik().V1alpha1().TLSStores().Informer().AddEventHandler(eventHandler)
 
 		factoryKube := informers.NewFilteredSharedInformerFactory(c.csKube, resyncPeriod, ns, nil)
 		factoryKube.Extensions().V1beta1().Ingresses().Informer().AddEventHandler(eventHandler)
@@ -258,6 +264,21 @@ func (c *clientWrapper) GetTLSOptions() []*v1alpha1.TLSOption {
 	return result
 }
 
+// GetTLSStores returns all TLS stores.
+func (c *clientWrapper) GetTLSStores() []*v1alpha1.TLSStore {
+	var result []*v1alpha1.TLSStore
+
+	for ns, factory := range c.factoriesCrd {
+		stores, err := factory.Traefik().V1alpha1().TLSStores().Lister().List(c.labelSelector)
+		if err != nil {
+			log.Errorf("Failed to list tls stores in namespace %s: %s", ns, err)
+		}
+		result = append(result, stores...)
+	}
+
+	return result
+}
+
 // GetIngresses returns all Ingresses for observed namespaces in the cluster.
 func (c *clientWrapper) GetIngresses() []*extensionsv1beta1.Ingress {
 	var result []*extensionsv1beta1.Ingress
@@ -300,6 +321,60 @@ func (c *clientWrapper) UpdateIngressStatus(namespace, name, ip, hostname string
 	return nil
 }
 
+// UpdateIngressRouteStatus updates an IngressRoute with a provided status.
+func (c *clientWrapper) UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute, status v1alpha1.ResourceStatus) error {
+	if reflect.DeepEqual(ingressRoute.Status, status) {
+		log.Debugf("Skipping status update on ingressroute %s/%s", ingressRoute.Namespace, ingressRoute.Name)
+		return nil
+	}
+
+	ingCopy := ingressRoute.DeepCopy()
+	ingCopy.Status = status
+
+	_, err := c.csCrd.TraefikV1alpha1().IngressRoutes(ingCopy.Namespace).UpdateStatus(ingCopy)
+	if err != nil {
+		return fmt.Errorf("failed to update ingressroute status %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+	}
+	log.Debugf("Updated status on ingressroute %s/%s", ingressRoute.Namespace, ingressRoute.Name)
+	return nil
+}
+
+// UpdateIngressRouteTCPStatus updates an IngressRouteTCP with a provided status.
+func (c *clientWrapper) UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP, status v1alpha1.ResourceStatus) error {
+	if reflect.DeepEqual(ingressRouteTCP.Status, status) {
+		log.Debugf("Skipping status update on ingressroutetcp %s/%s", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
+		return nil
+	}
+
+	ingCopy := ingressRouteTCP.DeepCopy()
+	ingCopy.Status = status
+
+	_, err := c.csCrd.TraefikV1alpha1().IngressRouteTCPs(ingCopy.Namespace).UpdateStatus(ingCopy)
+	if err != nil {
+		return fmt.Errorf("failed to update ingressroutetcp status %s/%s: %v", ingressRouteTCP.Namespace, ingressRouteTCP.Name, err)
+	}
+	log.Debugf("Updated status on ingressroutetcp %s/%s", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
+	return nil
+}
+
+// UpdateMiddlewareStatus updates a Middleware with a provided status.
+func (c *clientWrapper) UpdateMiddlewareStatus(middleware *v1alpha1.Middleware, status v1alpha1.ResourceStatus) error {
+	if reflect.DeepEqual(middleware.Status, status) {
+		log.Debugf("Skipping status update on middleware %s/%s", middleware.Namespace, middleware.Name)
+		return nil
+	}
+
+	middlewareCopy := middleware.DeepCopy()
+	middlewareCopy.Status = status
+
+	_, err := c.csCrd.TraefikV1alpha1().Middlewares(middlewareCopy.Namespace).UpdateStatus(middlewareCopy)
+	if err != nil {
+		return fmt.Errorf("failed to update middleware status %s/%s: %v", middleware.Namespace, middleware.Name, err)
+	}
+	log.Debugf("Updated status on middleware %s/%s", middleware.Namespace, middleware.Name)
+	return nil
+}
+
 // GetService returns the named service from the given namespace.
 func (c *clientWrapper) GetService(namespace, name string) (*corev1.Service, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
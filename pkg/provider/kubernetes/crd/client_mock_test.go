@@ -38,6 +38,7 @@ type clientMock struct {
 	ingressRouteTCPs []*v1alpha1.IngressRouteTCP
 	middlewares      []*v1alpha1.Middleware
 	tlsOptions       []*v1alpha1.TLSOption
+	tlsStores        []*v1alpha1.TLSStore
 
 	watchChan chan interface{}
 }
@@ -66,6 +67,8 @@ func newClientMock(paths ...string) clientMock {
 				c.middlewares = append(c.middlewares, o)
 			case *v1alpha1.TLSOption:
 				c.tlsOptions = append(c.tlsOptions, o)
+			case *v1alpha1.TLSStore:
+				c.tlsStores = append(c.tlsStores, o)
 			case *v1beta12.Ingress:
 				c.ingresses = append(c.ingresses, o)
 			case *corev1.Secret:
@@ -95,6 +98,10 @@ func (c clientMock) GetTLSOptions() []*v1alpha1.TLSOption {
 	return c.tlsOptions
 }
 
+func (c clientMock) GetTLSStores() []*v1alpha1.TLSStore {
+	return c.tlsStores
+}
+
 func (c clientMock) GetTLSOption(namespace, name string) (*v1alpha1.TLSOption, bool, error) {
 	for _, option := range c.tlsOptions {
 		if option.Namespace == namespace && option.Name == name {
@@ -156,3 +163,15 @@ func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-cha
 func (c clientMock) UpdateIngressStatus(namespace, name, ip, hostname string) error {
 	return c.apiIngressStatusError
 }
+
+func (c clientMock) UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute, status v1alpha1.ResourceStatus) error {
+	return nil
+}
+
+func (c clientMock) UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP, status v1alpha1.ResourceStatus) error {
+	return nil
+}
+
+func (c clientMock) UpdateMiddlewareStatus(middleware *v1alpha1.Middleware, status v1alpha1.ResourceStatus) error {
+	return nil
+}
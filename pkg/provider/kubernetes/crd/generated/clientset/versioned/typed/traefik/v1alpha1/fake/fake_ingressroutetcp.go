@@ -108,6 +108,18 @@ func (c *FakeIngressRouteTCPs) Update(ingressRouteTCP *v1alpha1.IngressRouteTCP)
 	return obj.(*v1alpha1.IngressRouteTCP), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeIngressRouteTCPs) UpdateStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP) (*v1alpha1.IngressRouteTCP, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ingressroutetcpsResource, "status", c.ns, ingressRouteTCP), &v1alpha1.IngressRouteTCP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IngressRouteTCP), err
+}
+
 // Delete takes name of the ingressRouteTCP and deletes it. Returns an error if one occurs.
 func (c *FakeIngressRouteTCPs) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
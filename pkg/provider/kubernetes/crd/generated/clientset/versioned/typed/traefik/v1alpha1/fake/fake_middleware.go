@@ -108,6 +108,18 @@ func (c *FakeMiddlewares) Update(middleware *v1alpha1.Middleware) (result *v1alp
 	return obj.(*v1alpha1.Middleware), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeMiddlewares) UpdateStatus(middleware *v1alpha1.Middleware) (*v1alpha1.Middleware, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(middlewaresResource, "status", c.ns, middleware), &v1alpha1.Middleware{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Middleware), err
+}
+
 // Delete takes name of the middleware and deletes it. Returns an error if one occurs.
 func (c *FakeMiddlewares) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -33,3 +33,5 @@ type IngressRouteTCPExpansion interface{}
 type MiddlewareExpansion interface{}
 
 type TLSOptionExpansion interface{}
+
+type TLSStoreExpansion interface{}
@@ -47,6 +47,7 @@ type IngressRouteTCPsGetter interface {
 type IngressRouteTCPInterface interface {
 	Create(*v1alpha1.IngressRouteTCP) (*v1alpha1.IngressRouteTCP, error)
 	Update(*v1alpha1.IngressRouteTCP) (*v1alpha1.IngressRouteTCP, error)
+	UpdateStatus(*v1alpha1.IngressRouteTCP) (*v1alpha1.IngressRouteTCP, error)
 	Delete(name string, options *v1.DeleteOptions) error
 	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
 	Get(name string, options v1.GetOptions) (*v1alpha1.IngressRouteTCP, error)
@@ -140,6 +141,22 @@ func (c *ingressRouteTCPs) Update(ingressRouteTCP *v1alpha1.IngressRouteTCP) (re
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *ingressRouteTCPs) UpdateStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP) (result *v1alpha1.IngressRouteTCP, err error) {
+	result = &v1alpha1.IngressRouteTCP{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ingressroutetcps").
+		Name(ingressRouteTCP.Name).
+		SubResource("status").
+		Body(ingressRouteTCP).
+		Do().
+		Into(result)
+	return
+}
+
 // Delete takes name of the ingressRouteTCP and deletes it. Returns an error if one occurs.
 func (c *ingressRouteTCPs) Delete(name string, options *v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -47,6 +47,7 @@ type MiddlewaresGetter interface {
 type MiddlewareInterface interface {
 	Create(*v1alpha1.Middleware) (*v1alpha1.Middleware, error)
 	Update(*v1alpha1.Middleware) (*v1alpha1.Middleware, error)
+	UpdateStatus(*v1alpha1.Middleware) (*v1alpha1.Middleware, error)
 	Delete(name string, options *v1.DeleteOptions) error
 	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
 	Get(name string, options v1.GetOptions) (*v1alpha1.Middleware, error)
@@ -140,6 +141,22 @@ func (c *middlewares) Update(middleware *v1alpha1.Middleware) (result *v1alpha1.
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *middlewares) UpdateStatus(middleware *v1alpha1.Middleware) (result *v1alpha1.Middleware, err error) {
+	result = &v1alpha1.Middleware{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("middlewares").
+		Name(middleware.Name).
+		SubResource("status").
+		Body(middleware).
+		Do().
+		Into(result)
+	return
+}
+
 // Delete takes name of the middleware and deletes it. Returns an error if one occurs.
 func (c *middlewares) Delete(name string, options *v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -38,6 +38,7 @@ type TraefikV1alpha1Interface interface {
 	IngressRouteTCPsGetter
 	MiddlewaresGetter
 	TLSOptionsGetter
+	TLSStoresGetter
 }
 
 // TraefikV1alpha1Client is used to interact with features provided by the traefik.containo.us group.
@@ -61,6 +62,10 @@ func (c *TraefikV1alpha1Client) TLSOptions(namespace string) TLSOptionInterface
 	return newTLSOptions(c, namespace)
 }
 
+func (c *TraefikV1alpha1Client) TLSStores(namespace string) TLSStoreInterface {
+	return newTLSStores(c, namespace)
+}
+
 // NewForConfig creates a new TraefikV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*TraefikV1alpha1Client, error) {
 	config := *c
@@ -40,6 +40,8 @@ type Interface interface {
 	Middlewares() MiddlewareInformer
 	// TLSOptions returns a TLSOptionInformer.
 	TLSOptions() TLSOptionInformer
+	// TLSStores returns a TLSStoreInformer.
+	TLSStores() TLSStoreInformer
 }
 
 type version struct {
@@ -72,3 +74,8 @@ func (v *version) Middlewares() MiddlewareInformer {
 func (v *version) TLSOptions() TLSOptionInformer {
 	return &tLSOptionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TLSStores returns a TLSStoreInformer.
+func (v *version) TLSStores() TLSStoreInformer {
+	return &tLSStoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
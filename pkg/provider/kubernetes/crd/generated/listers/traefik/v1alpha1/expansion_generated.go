@@ -57,3 +57,11 @@ type TLSOptionListerExpansion interface{}
 // TLSOptionNamespaceListerExpansion allows custom methods to be added to
 // TLSOptionNamespaceLister.
 type TLSOptionNamespaceListerExpansion interface{}
+
+// TLSStoreListerExpansion allows custom methods to be added to
+// TLSStoreLister.
+type TLSStoreListerExpansion interface{}
+
+// TLSStoreNamespaceListerExpansion allows custom methods to be added to
+// TLSStoreNamespaceLister.
+type TLSStoreNamespaceListerExpansion interface{}
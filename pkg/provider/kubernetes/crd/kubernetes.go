@@ -14,6 +14,7 @@ import (
 
 	"github.com/cenkalti/backoff/v3"
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/job"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
@@ -28,6 +29,8 @@ import (
 const (
 	annotationKubernetesIngressClass = "kubernetes.io/ingress.class"
 	traefikDefaultIngressClass       = "traefik"
+	defaultTLSStoreName              = "default"
+	providerName                     = "kubernetescrd"
 )
 
 // Provider holds configurations of the provider.
@@ -41,6 +44,8 @@ type Provider struct {
 	IngressClass           string         `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
 	ThrottleDuration       types.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty"`
 	lastConfiguration      safe.Safe
+
+	runtimeConfigurationChan chan *runtime.Configuration
 }
 
 func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*clientWrapper, error) {
@@ -77,13 +82,14 @@ func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*cli
 
 // Init the provider.
 func (p *Provider) Init() error {
+	p.runtimeConfigurationChan = make(chan *runtime.Configuration, 1)
 	return nil
 }
 
 // Provide allows the k8s provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
-	ctxLog := log.With(context.Background(), log.Str(log.ProviderName, "kubernetescrd"))
+	ctxLog := log.With(context.Background(), log.Str(log.ProviderName, providerName))
 	logger := log.FromContext(ctxLog)
 
 	logger.Debugf("Using label selector: %q", p.LabelSelector)
@@ -136,7 +142,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					default:
 						p.lastConfiguration.Set(confHash)
 						configurationChan <- dynamic.Message{
-							ProviderName:  "kubernetescrd",
+							ProviderName:  providerName,
 							Configuration: conf,
 						}
 					}
@@ -145,6 +151,8 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					// enforce that we don't refresh faster than our throttle. time.Sleep
 					// returns immediately if p.ThrottleDuration is 0 (no throttle).
 					time.Sleep(throttleDuration)
+				case rtConf := <-p.runtimeConfigurationChan:
+					p.updateResourcesStatus(ctxLog, k8sClient, rtConf)
 				}
 			}
 		}
@@ -169,6 +177,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		TLS: &dynamic.TLSConfiguration{
 			Certificates: getTLSConfig(tlsConfigs),
 			Options:      buildTLSOptions(ctx, client),
+			Stores:       buildTLSStores(ctx, client),
 		},
 	}
 
@@ -228,6 +237,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Buffering:         middleware.Spec.Buffering,
 			CircuitBreaker:    middleware.Spec.CircuitBreaker,
 			Compress:          middleware.Spec.Compress,
+			GrpcWeb:           middleware.Spec.GrpcWeb,
+			BodyRewrite:       middleware.Spec.BodyRewrite,
+			Cache:             middleware.Spec.Cache,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             middleware.Spec.Retry,
 		}
@@ -497,6 +509,63 @@ func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options
 	return tlsOptions
 }
 
+func buildTLSStores(ctx context.Context, client Client) map[string]tls.Store {
+	tlsStoresCRD := client.GetTLSStores()
+	var tlsStores map[string]tls.Store
+
+	if len(tlsStoresCRD) == 0 {
+		return tlsStores
+	}
+	tlsStores = make(map[string]tls.Store)
+
+	for _, tlsStore := range tlsStoresCRD {
+		namespace := tlsStore.Namespace
+		secretName := tlsStore.Spec.DefaultCertificate.SecretName
+		logger := log.FromContext(log.With(ctx, log.Str(log.TLSStoreName, tlsStore.Name), log.Str("namespace", namespace)))
+
+		if secretName == "" {
+			logger.Errorf("TLS store %s/%s has no default certificate secret", namespace, tlsStore.Name)
+			continue
+		}
+
+		secret, exists, err := client.GetSecret(namespace, secretName)
+		if err != nil {
+			logger.Errorf("Failed to fetch secret %s/%s: %v", namespace, secretName, err)
+			continue
+		}
+
+		if !exists {
+			logger.Errorf("Secret %s/%s does not exist", namespace, secretName)
+			continue
+		}
+
+		cert, key, err := getCertificateBlocks(secret, namespace, secretName)
+		if err != nil {
+			logger.Errorf("Failed to extract certificate from secret %s/%s: %v", namespace, secretName, err)
+			continue
+		}
+
+		// The default store is the one used by the entry points,
+		// so a TLSStore named "default" is not prefixed by its namespace.
+		id := makeID(namespace, tlsStore.Name)
+		if tlsStore.Name == defaultTLSStoreName {
+			if _, ok := tlsStores[defaultTLSStoreName]; ok {
+				logger.Errorf("Default TLS store defined in multiple namespaces, skipping %s/%s", namespace, tlsStore.Name)
+				continue
+			}
+			id = defaultTLSStoreName
+		}
+
+		tlsStores[id] = tls.Store{
+			DefaultCertificate: &tls.Certificate{
+				CertFile: tls.FileOrContent(cert),
+				KeyFile:  tls.FileOrContent(key),
+			},
+		}
+	}
+	return tlsStores
+}
+
 func checkStringQuoteValidity(value string) error {
 	_, err := strconv.Unquote(`"` + value + `"`)
 	return err
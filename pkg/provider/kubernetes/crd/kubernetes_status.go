@@ -0,0 +1,158 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+)
+
+// ListenRuntimeConfiguration is called with the runtime configuration built after each configuration reload.
+// The latest configuration is kept until the provider writes the resources status back to Kubernetes.
+func (p *Provider) ListenRuntimeConfiguration(conf *runtime.Configuration) {
+	if p.runtimeConfigurationChan == nil {
+		return
+	}
+
+	for {
+		select {
+		case p.runtimeConfigurationChan <- conf:
+			return
+		default:
+			// Drop the pending configuration, only the latest one matters.
+			select {
+			case <-p.runtimeConfigurationChan:
+			default:
+			}
+		}
+	}
+}
+
+func (p *Provider) updateResourcesStatus(ctx context.Context, client Client, conf *runtime.Configuration) {
+	for _, ingressRoute := range client.GetIngressRoutes() {
+		if !shouldProcessIngress(p.IngressClass, ingressRoute.Annotations[annotationKubernetesIngressClass]) {
+			continue
+		}
+
+		if err := client.UpdateIngressRouteStatus(ingressRoute, buildIngressRouteStatus(ingressRoute, conf)); err != nil {
+			log.FromContext(ctx).Error(err)
+		}
+	}
+
+	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
+		if !shouldProcessIngress(p.IngressClass, ingressRouteTCP.Annotations[annotationKubernetesIngressClass]) {
+			continue
+		}
+
+		if err := client.UpdateIngressRouteTCPStatus(ingressRouteTCP, buildIngressRouteTCPStatus(ingressRouteTCP, conf)); err != nil {
+			log.FromContext(ctx).Error(err)
+		}
+	}
+
+	for _, middleware := range client.GetMiddlewares() {
+		if err := client.UpdateMiddlewareStatus(middleware, buildMiddlewareStatus(middleware, conf)); err != nil {
+			log.FromContext(ctx).Error(err)
+		}
+	}
+}
+
+func buildIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute, conf *runtime.Configuration) v1alpha1.ResourceStatus {
+	ingressName := ingressRoute.Name
+	if len(ingressName) == 0 {
+		ingressName = ingressRoute.GenerateName
+	}
+
+	var errs []string
+	for _, route := range ingressRoute.Spec.Routes {
+		key, err := makeServiceKey(route.Match, ingressName)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		routerName := qualifyName(makeID(ingressRoute.Namespace, key))
+		router, ok := conf.Routers[routerName]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("route %q is not loaded, see Traefik logs for details", route.Match))
+			continue
+		}
+		errs = appendErrors(errs, "router", routerName, router.Err)
+
+		serviceName := qualifyName(router.Service)
+		if service, ok := conf.Services[serviceName]; ok {
+			errs = appendErrors(errs, "service", serviceName, service.Err)
+		}
+	}
+
+	return newResourceStatus(ingressRoute.Generation, errs)
+}
+
+func buildIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP, conf *runtime.Configuration) v1alpha1.ResourceStatus {
+	ingressName := ingressRouteTCP.Name
+	if len(ingressName) == 0 {
+		ingressName = ingressRouteTCP.GenerateName
+	}
+
+	var errs []string
+	for _, route := range ingressRouteTCP.Spec.Routes {
+		key, err := makeServiceKey(route.Match, ingressName)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		routerName := qualifyName(makeID(ingressRouteTCP.Namespace, key))
+		router, ok := conf.TCPRouters[routerName]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("route %q is not loaded, see Traefik logs for details", route.Match))
+			continue
+		}
+		errs = appendErrors(errs, "router", routerName, router.Err)
+
+		serviceName := qualifyName(router.Service)
+		if service, ok := conf.TCPServices[serviceName]; ok {
+			errs = appendErrors(errs, "service", serviceName, service.Err)
+		}
+	}
+
+	return newResourceStatus(ingressRouteTCP.Generation, errs)
+}
+
+func buildMiddlewareStatus(middleware *v1alpha1.Middleware, conf *runtime.Configuration) v1alpha1.ResourceStatus {
+	var errs []string
+
+	middlewareName := qualifyName(makeID(middleware.Namespace, middleware.Name))
+	if mi, ok := conf.Middlewares[middlewareName]; ok {
+		errs = appendErrors(errs, "middleware", middlewareName, mi.Err)
+	} else {
+		errs = append(errs, "middleware is not loaded, see Traefik logs for details")
+	}
+
+	return newResourceStatus(middleware.Generation, errs)
+}
+
+func newResourceStatus(generation int64, errs []string) v1alpha1.ResourceStatus {
+	return v1alpha1.ResourceStatus{
+		Accepted:           len(errs) == 0,
+		Errors:             errs,
+		ObservedGeneration: generation,
+	}
+}
+
+func appendErrors(errs []string, kind, name string, values []string) []string {
+	for _, value := range values {
+		errs = append(errs, fmt.Sprintf("%s %s: %s", kind, name, value))
+	}
+	return errs
+}
+
+// qualifyName returns the name of an element as seen in the runtime configuration.
+func qualifyName(name string) string {
+	if strings.Contains(name, "@") {
+		return name
+	}
+	return name + "@" + providerName
+}
@@ -0,0 +1,103 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIngressRouteStatus(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		conf     *runtime.Configuration
+		expected v1alpha1.ResourceStatus
+	}{
+		{
+			desc: "accepted",
+			conf: &runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"default-test2.route-23c7f4c450289ee29016@kubernetescrd": {
+						Router: &dynamic.Router{Service: "default-test2.route-23c7f4c450289ee29016"},
+						Status: runtime.StatusEnabled,
+					},
+				},
+				Services: map[string]*runtime.ServiceInfo{
+					"default-test2.route-23c7f4c450289ee29016@kubernetescrd": {
+						Status: runtime.StatusEnabled,
+					},
+				},
+			},
+			expected: v1alpha1.ResourceStatus{Accepted: true},
+		},
+		{
+			desc: "router and service errors",
+			conf: &runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"default-test2.route-23c7f4c450289ee29016@kubernetescrd": {
+						Router: &dynamic.Router{Service: "default-test2.route-23c7f4c450289ee29016"},
+						Err:    []string{"middleware \"default-stripprefix@kubernetescrd\" does not exist"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+				Services: map[string]*runtime.ServiceInfo{
+					"default-test2.route-23c7f4c450289ee29016@kubernetescrd": {
+						Err:    []string{"no servers"},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: v1alpha1.ResourceStatus{
+				Errors: []string{
+					"router default-test2.route-23c7f4c450289ee29016@kubernetescrd: middleware \"default-stripprefix@kubernetescrd\" does not exist",
+					"service default-test2.route-23c7f4c450289ee29016@kubernetescrd: no servers",
+				},
+			},
+		},
+		{
+			desc: "route not loaded",
+			conf: &runtime.Configuration{},
+			expected: v1alpha1.ResourceStatus{
+				Errors: []string{"route \"Host(`foo.com`) && PathPrefix(`/tobestripped`)\" is not loaded, see Traefik logs for details"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ingressRoutes := newClientMock("with_middleware.yml").GetIngressRoutes()
+			require.Len(t, ingressRoutes, 1)
+
+			status := buildIngressRouteStatus(ingressRoutes[0], test.conf)
+			assert.Equal(t, test.expected, status)
+		})
+	}
+}
+
+func TestBuildMiddlewareStatus(t *testing.T) {
+	middlewares := newClientMock("with_middleware.yml").GetMiddlewares()
+	require.Len(t, middlewares, 2)
+
+	conf := &runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"default-stripprefix@kubernetescrd": {
+				Status: runtime.StatusEnabled,
+			},
+			"foo-addprefix@kubernetescrd": {
+				Err:    []string{"invalid prefix"},
+				Status: runtime.StatusDisabled,
+			},
+		},
+	}
+
+	assert.Equal(t, v1alpha1.ResourceStatus{Accepted: true}, buildMiddlewareStatus(middlewares[0], conf))
+	assert.Equal(t, v1alpha1.ResourceStatus{
+		Errors: []string{"middleware foo-addprefix@kubernetescrd: invalid prefix"},
+	}, buildMiddlewareStatus(middlewares[1], conf))
+}
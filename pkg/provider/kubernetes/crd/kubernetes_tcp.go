@@ -92,6 +92,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 			conf.Routers[serviceName] = &dynamic.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
 				Rule:        route.Match,
+				Priority:    route.Priority,
 				Service:     serviceName,
 			}
 
@@ -144,6 +145,10 @@ func createLoadBalancerServerTCP(client Client, namespace string, service v1alph
 		tcpService.LoadBalancer.TerminationDelay = service.TerminationDelay
 	}
 
+	if service.ProxyProtocol != nil {
+		tcpService.LoadBalancer.ProxyProtocol = service.ProxyProtocol
+	}
+
 	return tcpService, nil
 }
 
@@ -81,7 +81,8 @@ type IngressRoute struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec IngressRouteSpec `json:"spec"`
+	Spec   IngressRouteSpec `json:"spec"`
+	Status ResourceStatus   `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
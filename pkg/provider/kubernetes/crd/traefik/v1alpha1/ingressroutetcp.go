@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -15,6 +16,7 @@ type IngressRouteTCPSpec struct {
 // RouteTCP contains the set of routes.
 type RouteTCP struct {
 	Match    string       `json:"match"`
+	Priority int          `json:"priority,omitempty"`
 	Services []ServiceTCP `json:"services,omitempty"`
 }
 
@@ -44,10 +46,11 @@ type TLSOptionTCPRef struct {
 
 // ServiceTCP defines an upstream to proxy traffic.
 type ServiceTCP struct {
-	Name             string `json:"name"`
-	Port             int32  `json:"port"`
-	Weight           *int   `json:"weight,omitempty"`
-	TerminationDelay *int   `json:"terminationDelay,omitempty"`
+	Name             string                 `json:"name"`
+	Port             int32                  `json:"port"`
+	Weight           *int                   `json:"weight,omitempty"`
+	TerminationDelay *int                   `json:"terminationDelay,omitempty"`
+	ProxyProtocol    *dynamic.ProxyProtocol `json:"proxyProtocol,omitempty"`
 }
 
 // +genclient
@@ -58,7 +61,8 @@ type IngressRouteTCP struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec IngressRouteTCPSpec `json:"spec"`
+	Spec   IngressRouteTCPSpec `json:"spec"`
+	Status ResourceStatus      `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
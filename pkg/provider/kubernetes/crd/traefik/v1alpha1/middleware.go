@@ -13,7 +13,8 @@ type Middleware struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec MiddlewareSpec `json:"spec"`
+	Spec   MiddlewareSpec `json:"spec"`
+	Status ResourceStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -41,6 +42,9 @@ type MiddlewareSpec struct {
 	Buffering         *dynamic.Buffering         `json:"buffering,omitempty"`
 	CircuitBreaker    *dynamic.CircuitBreaker    `json:"circuitBreaker,omitempty"`
 	Compress          *dynamic.Compress          `json:"compress,omitempty"`
+	GrpcWeb           *dynamic.GrpcWeb           `json:"grpcWeb,omitempty"`
+	BodyRewrite       *dynamic.BodyRewrite       `json:"bodyRewrite,omitempty"`
+	Cache             *dynamic.Cache             `json:"cache,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert `json:"passTLSClientCert,omitempty"`
 	Retry             *dynamic.Retry             `json:"retry,omitempty"`
 }
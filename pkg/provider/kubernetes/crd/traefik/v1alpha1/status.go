@@ -0,0 +1,13 @@
+package v1alpha1
+
+// +k8s:deepcopy-gen=true
+
+// ResourceStatus reports how Traefik handled a resource.
+type ResourceStatus struct {
+	// Accepted is true when the resource is in use without any error.
+	Accepted bool `json:"accepted"`
+	// Errors lists the errors reported by Traefik for the resource.
+	Errors []string `json:"errors,omitempty"`
+	// ObservedGeneration is the resource generation the status was computed from.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
@@ -0,0 +1,576 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway/networking/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	kubeerror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const resyncPeriod = 10 * time.Minute
+
+var (
+	gatewayClassesResource = v1alpha1.SchemeGroupVersion.WithResource("gatewayclasses")
+	gatewaysResource       = v1alpha1.SchemeGroupVersion.WithResource("gateways")
+	httpRoutesResource     = v1alpha1.SchemeGroupVersion.WithResource("httproutes")
+	tlsRoutesResource      = v1alpha1.SchemeGroupVersion.WithResource("tlsroutes")
+)
+
+type resourceEventHandler struct {
+	ev chan<- interface{}
+}
+
+func (reh *resourceEventHandler) OnAdd(obj interface{}) {
+	eventHandlerFunc(reh.ev, obj)
+}
+
+func (reh *resourceEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	eventHandlerFunc(reh.ev, newObj)
+}
+
+func (reh *resourceEventHandler) OnDelete(obj interface{}) {
+	eventHandlerFunc(reh.ev, obj)
+}
+
+// Client is a client for the Provider master.
+// WatchAll starts the watch of the Provider resources and updates the stores.
+// The stores can then be accessed via the Get* functions.
+type Client interface {
+	WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error)
+
+	GetGatewayClasses() []*v1alpha1.GatewayClass
+	GetGateways() []*v1alpha1.Gateway
+	GetHTTPRoutes(namespace string, selector labels.Selector) []*v1alpha1.HTTPRoute
+	GetTLSRoutes(namespace string, selector labels.Selector) []*v1alpha1.TLSRoute
+	GetNamespaces(selector labels.Selector) []string
+
+	UpdateGatewayClassStatus(gatewayClass *v1alpha1.GatewayClass, status v1alpha1.GatewayClassStatus) error
+	UpdateGatewayStatus(gateway *v1alpha1.Gateway, status v1alpha1.GatewayStatus) error
+	UpdateHTTPRouteStatus(route *v1alpha1.HTTPRoute, status v1alpha1.RouteStatus) error
+	UpdateTLSRouteStatus(route *v1alpha1.TLSRoute, status v1alpha1.RouteStatus) error
+
+	GetService(namespace, name string) (*corev1.Service, bool, error)
+	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
+	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+}
+
+// clientWrapper watches the Gateway API resources through the dynamic client,
+// since their typed clientset is not generated in Traefik.
+type clientWrapper struct {
+	csDynamic dynamic.Interface
+	csKube    *kubernetes.Clientset
+
+	factoryClusterDynamic dynamicinformer.DynamicSharedInformerFactory
+	factoryClusterKube    informers.SharedInformerFactory
+	factoriesDynamic      map[string]dynamicinformer.DynamicSharedInformerFactory
+	factoriesKube         map[string]informers.SharedInformerFactory
+
+	labelSelector labels.Selector
+
+	isNamespaceAll    bool
+	watchedNamespaces []string
+}
+
+func createClientFromConfig(c *rest.Config) (*clientWrapper, error) {
+	csDynamic, err := dynamic.NewForConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	csKube, err := kubernetes.NewForConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClientImpl(csKube, csDynamic), nil
+}
+
+func newClientImpl(csKube *kubernetes.Clientset, csDynamic dynamic.Interface) *clientWrapper {
+	return &clientWrapper{
+		csDynamic:        csDynamic,
+		csKube:           csKube,
+		factoriesDynamic: make(map[string]dynamicinformer.DynamicSharedInformerFactory),
+		factoriesKube:    make(map[string]informers.SharedInformerFactory),
+	}
+}
+
+// newInClusterClient returns a new Provider client that is expected to run
+// inside the cluster.
+func newInClusterClient(endpoint string) (*clientWrapper, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster configuration: %s", err)
+	}
+
+	if endpoint != "" {
+		config.Host = endpoint
+	}
+
+	return createClientFromConfig(config)
+}
+
+func newExternalClusterClientFromFile(file string) (*clientWrapper, error) {
+	configFromFlags, err := clientcmd.BuildConfigFromFlags("", file)
+	if err != nil {
+		return nil, err
+	}
+	return createClientFromConfig(configFromFlags)
+}
+
+// newExternalClusterClient returns a new Provider client that may run outside
+// of the cluster.
+// The endpoint parameter must not be empty.
+func newExternalClusterClient(endpoint, token, caFilePath string) (*clientWrapper, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint missing for external cluster client")
+	}
+
+	config := &rest.Config{
+		Host:        endpoint,
+		BearerToken: token,
+	}
+
+	if caFilePath != "" {
+		caData, err := ioutil.ReadFile(caFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %s", caFilePath, err)
+		}
+
+		config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+	}
+
+	return createClientFromConfig(config)
+}
+
+// WatchAll starts namespace-specific controllers for all relevant kinds.
+func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+	eventCh := make(chan interface{}, 1)
+	eventHandler := &resourceEventHandler{ev: eventCh}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+		c.isNamespaceAll = true
+	}
+	c.watchedNamespaces = namespaces
+
+	// GatewayClasses and Namespaces are cluster scoped resources.
+	c.factoryClusterDynamic = dynamicinformer.NewDynamicSharedInformerFactory(c.csDynamic, resyncPeriod)
+	c.factoryClusterDynamic.ForResource(gatewayClassesResource).Informer().AddEventHandler(eventHandler)
+
+	c.factoryClusterKube = informers.NewSharedInformerFactory(c.csKube, resyncPeriod)
+	c.factoryClusterKube.Core().V1().Namespaces().Informer().AddEventHandler(eventHandler)
+
+	for _, ns := range namespaces {
+		factoryDynamic := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.csDynamic, resyncPeriod, ns, nil)
+		factoryDynamic.ForResource(gatewaysResource).Informer().AddEventHandler(eventHandler)
+		factoryDynamic.ForResource(httpRoutesResource).Informer().AddEventHandler(eventHandler)
+		factoryDynamic.ForResource(tlsRoutesResource).Informer().AddEventHandler(eventHandler)
+
+		factoryKube := informers.NewFilteredSharedInformerFactory(c.csKube, resyncPeriod, ns, nil)
+		factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
+		factoryKube.Core().V1().Endpoints().Informer().AddEventHandler(eventHandler)
+
+		c.factoriesDynamic[ns] = factoryDynamic
+		c.factoriesKube[ns] = factoryKube
+	}
+
+	c.factoryClusterDynamic.Start(stopCh)
+	c.factoryClusterKube.Start(stopCh)
+
+	for _, ns := range namespaces {
+		c.factoriesDynamic[ns].Start(stopCh)
+		c.factoriesKube[ns].Start(stopCh)
+	}
+
+	for t, ok := range c.factoryClusterDynamic.WaitForCacheSync(stopCh) {
+		if !ok {
+			return nil, fmt.Errorf("timed out waiting for controller caches to sync %s", t.String())
+		}
+	}
+
+	for t, ok := range c.factoryClusterKube.WaitForCacheSync(stopCh) {
+		if !ok {
+			return nil, fmt.Errorf("timed out waiting for controller caches to sync %s", t.String())
+		}
+	}
+
+	for _, ns := range namespaces {
+		for t, ok := range c.factoriesDynamic[ns].WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s in namespace %q", t.String(), ns)
+			}
+		}
+
+		for t, ok := range c.factoriesKube[ns].WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s in namespace %q", t.String(), ns)
+			}
+		}
+	}
+
+	// Do not wait for the Secrets store to get synced since we cannot rely on
+	// users having granted RBAC permissions for this object.
+	// https://github.com/containous/traefik/issues/1784 should improve the
+	// situation here in the future.
+	for _, ns := range namespaces {
+		c.factoriesKube[ns].Core().V1().Secrets().Informer().AddEventHandler(eventHandler)
+		c.factoriesKube[ns].Start(stopCh)
+	}
+
+	return eventCh, nil
+}
+
+// GetGatewayClasses returns all the GatewayClasses of the cluster.
+func (c *clientWrapper) GetGatewayClasses() []*v1alpha1.GatewayClass {
+	var result []*v1alpha1.GatewayClass
+
+	objects, err := c.factoryClusterDynamic.ForResource(gatewayClassesResource).Lister().List(labels.Everything())
+	if err != nil {
+		log.Errorf("Failed to list gateway classes: %s", err)
+		return nil
+	}
+
+	for _, object := range objects {
+		gatewayClass := &v1alpha1.GatewayClass{}
+		if err := fromUnstructured(object, gatewayClass); err != nil {
+			log.Errorf("Failed to read gateway class: %s", err)
+			continue
+		}
+		result = append(result, gatewayClass)
+	}
+
+	return result
+}
+
+// GetGateways returns all the Gateways of the watched namespaces.
+func (c *clientWrapper) GetGateways() []*v1alpha1.Gateway {
+	var result []*v1alpha1.Gateway
+
+	for ns, factory := range c.factoriesDynamic {
+		objects, err := factory.ForResource(gatewaysResource).Lister().List(c.labelSelector)
+		if err != nil {
+			log.Errorf("Failed to list gateways in namespace %s: %s", ns, err)
+			continue
+		}
+
+		for _, object := range objects {
+			gateway := &v1alpha1.Gateway{}
+			if err := fromUnstructured(object, gateway); err != nil {
+				log.Errorf("Failed to read gateway in namespace %s: %s", ns, err)
+				continue
+			}
+			result = append(result, gateway)
+		}
+	}
+
+	return result
+}
+
+// GetHTTPRoutes returns the HTTPRoutes of the given namespace matching the selector.
+func (c *clientWrapper) GetHTTPRoutes(namespace string, selector labels.Selector) []*v1alpha1.HTTPRoute {
+	if !c.isWatchedNamespace(namespace) {
+		log.Warnf("Failed to get HTTPRoutes: %q is not within watched namespaces", namespace)
+		return nil
+	}
+
+	objects, err := c.factoriesDynamic[c.lookupNamespace(namespace)].ForResource(httpRoutesResource).Lister().ByNamespace(namespace).List(selector)
+	if err != nil {
+		log.Errorf("Failed to list HTTPRoutes in namespace %s: %s", namespace, err)
+		return nil
+	}
+
+	var result []*v1alpha1.HTTPRoute
+	for _, object := range objects {
+		route := &v1alpha1.HTTPRoute{}
+		if err := fromUnstructured(object, route); err != nil {
+			log.Errorf("Failed to read HTTPRoute in namespace %s: %s", namespace, err)
+			continue
+		}
+		result = append(result, route)
+	}
+
+	return result
+}
+
+// GetTLSRoutes returns the TLSRoutes of the given namespace matching the selector.
+func (c *clientWrapper) GetTLSRoutes(namespace string, selector labels.Selector) []*v1alpha1.TLSRoute {
+	if !c.isWatchedNamespace(namespace) {
+		log.Warnf("Failed to get TLSRoutes: %q is not within watched namespaces", namespace)
+		return nil
+	}
+
+	objects, err := c.factoriesDynamic[c.lookupNamespace(namespace)].ForResource(tlsRoutesResource).Lister().ByNamespace(namespace).List(selector)
+	if err != nil {
+		log.Errorf("Failed to list TLSRoutes in namespace %s: %s", namespace, err)
+		return nil
+	}
+
+	var result []*v1alpha1.TLSRoute
+	for _, object := range objects {
+		route := &v1alpha1.TLSRoute{}
+		if err := fromUnstructured(object, route); err != nil {
+			log.Errorf("Failed to read TLSRoute in namespace %s: %s", namespace, err)
+			continue
+		}
+		result = append(result, route)
+	}
+
+	return result
+}
+
+// GetNamespaces returns the watched namespaces matching the given selector.
+func (c *clientWrapper) GetNamespaces(selector labels.Selector) []string {
+	var namespaces []string
+	for _, namespace := range c.watchedNamespaces {
+		if namespace != metav1.NamespaceAll {
+			ns, err := c.factoryClusterKube.Core().V1().Namespaces().Lister().Get(namespace)
+			if err != nil {
+				log.Errorf("Failed to get namespace %s: %s", namespace, err)
+				continue
+			}
+
+			if selector.Matches(labels.Set(ns.Labels)) {
+				namespaces = append(namespaces, namespace)
+			}
+			continue
+		}
+
+		nss, err := c.factoryClusterKube.Core().V1().Namespaces().Lister().List(selector)
+		if err != nil {
+			log.Errorf("Failed to list namespaces: %s", err)
+			return nil
+		}
+
+		for _, ns := range nss {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	return namespaces
+}
+
+// UpdateGatewayClassStatus updates a GatewayClass with a provided status.
+func (c *clientWrapper) UpdateGatewayClassStatus(gatewayClass *v1alpha1.GatewayClass, status v1alpha1.GatewayClassStatus) error {
+	if conditionsEqual(gatewayClass.Status.Conditions, status.Conditions) {
+		return nil
+	}
+
+	gcCopy := gatewayClass.DeepCopy()
+	gcCopy.Status = status
+
+	return c.updateStatus(gatewayClassesResource, gcCopy)
+}
+
+// UpdateGatewayStatus updates a Gateway with a provided status.
+func (c *clientWrapper) UpdateGatewayStatus(gateway *v1alpha1.Gateway, status v1alpha1.GatewayStatus) error {
+	if gatewayStatusEqual(gateway.Status, status) {
+		return nil
+	}
+
+	gatewayCopy := gateway.DeepCopy()
+	gatewayCopy.Status = status
+
+	return c.updateStatus(gatewaysResource, gatewayCopy)
+}
+
+// UpdateHTTPRouteStatus updates an HTTPRoute with a provided status.
+func (c *clientWrapper) UpdateHTTPRouteStatus(route *v1alpha1.HTTPRoute, status v1alpha1.RouteStatus) error {
+	if routeStatusEqual(route.Status, status) {
+		return nil
+	}
+
+	routeCopy := route.DeepCopy()
+	routeCopy.Status = status
+
+	return c.updateStatus(httpRoutesResource, routeCopy)
+}
+
+// UpdateTLSRouteStatus updates a TLSRoute with a provided status.
+func (c *clientWrapper) UpdateTLSRouteStatus(route *v1alpha1.TLSRoute, status v1alpha1.RouteStatus) error {
+	if routeStatusEqual(route.Status, status) {
+		return nil
+	}
+
+	routeCopy := route.DeepCopy()
+	routeCopy.Status = status
+
+	return c.updateStatus(tlsRoutesResource, routeCopy)
+}
+
+func (c *clientWrapper) updateStatus(resource schema.GroupVersionResource, object metav1.Object) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s/%s: %v", resource.Resource, object.GetNamespace(), object.GetName(), err)
+	}
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+
+	var client dynamic.ResourceInterface = c.csDynamic.Resource(resource)
+	if object.GetNamespace() != "" {
+		client = c.csDynamic.Resource(resource).Namespace(object.GetNamespace())
+	}
+
+	_, err = client.UpdateStatus(obj, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update %s status %s/%s: %v", resource.Resource, object.GetNamespace(), object.GetName(), err)
+	}
+
+	log.Debugf("Updated status on %s %s/%s", resource.Resource, object.GetNamespace(), object.GetName())
+	return nil
+}
+
+// GetService returns the named service from the given namespace.
+func (c *clientWrapper) GetService(namespace, name string) (*corev1.Service, bool, error) {
+	if !c.isWatchedNamespace(namespace) {
+		return nil, false, fmt.Errorf("failed to get service %s/%s: namespace is not within watched namespaces", namespace, name)
+	}
+
+	service, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Services().Lister().Services(namespace).Get(name)
+	exist, err := translateNotFoundError(err)
+	return service, exist, err
+}
+
+// GetEndpoints returns the named endpoints from the given namespace.
+func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	if !c.isWatchedNamespace(namespace) {
+		return nil, false, fmt.Errorf("failed to get endpoints %s/%s: namespace is not within watched namespaces", namespace, name)
+	}
+
+	endpoint, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Endpoints().Lister().Endpoints(namespace).Get(name)
+	exist, err := translateNotFoundError(err)
+	return endpoint, exist, err
+}
+
+// GetSecret returns the named secret from the given namespace.
+func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
+	if !c.isWatchedNamespace(namespace) {
+		return nil, false, fmt.Errorf("failed to get secret %s/%s: namespace is not within watched namespaces", namespace, name)
+	}
+
+	secret, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Secrets().Lister().Secrets(namespace).Get(name)
+	exist, err := translateNotFoundError(err)
+	return secret, exist, err
+}
+
+// lookupNamespace returns the lookup namespace key for the given namespace.
+// When listening on all namespaces, it returns the client-go identifier ("")
+// for all-namespaces. Otherwise, it returns the given namespace.
+func (c *clientWrapper) lookupNamespace(ns string) string {
+	if c.isNamespaceAll {
+		return metav1.NamespaceAll
+	}
+	return ns
+}
+
+// isWatchedNamespace checks to ensure that the namespace is being watched before we request
+// it to ensure we don't panic by requesting an out-of-watch object.
+func (c *clientWrapper) isWatchedNamespace(ns string) bool {
+	if c.isNamespaceAll {
+		return true
+	}
+	for _, watchedNamespace := range c.watchedNamespaces {
+		if watchedNamespace == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// eventHandlerFunc will pass the obj on to the events channel or drop it.
+// This is so passing the events along won't block in the case of high volume.
+// The events are only used for signaling anyway so dropping a few is ok.
+func eventHandlerFunc(events chan<- interface{}, obj interface{}) {
+	select {
+	case events <- obj:
+	default:
+	}
+}
+
+// translateNotFoundError will translate a "not found" error to a boolean return
+// value which indicates if the resource exists and a nil error.
+func translateNotFoundError(err error) (bool, error) {
+	if kubeerror.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func fromUnstructured(object runtime.Object, into interface{}) error {
+	u, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", object)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), into)
+}
+
+// conditionsEqual compares the conditions regardless of their transition time.
+func conditionsEqual(a, b []v1alpha1.Condition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Type != b[i].Type ||
+			a[i].Status != b[i].Status ||
+			a[i].Reason != b[i].Reason ||
+			a[i].Message != b[i].Message ||
+			a[i].ObservedGeneration != b[i].ObservedGeneration {
+			return false
+		}
+	}
+
+	return true
+}
+
+func gatewayStatusEqual(a, b v1alpha1.GatewayStatus) bool {
+	if !conditionsEqual(a.Conditions, b.Conditions) || len(a.Listeners) != len(b.Listeners) {
+		return false
+	}
+
+	for i := range a.Listeners {
+		if a.Listeners[i].Port != b.Listeners[i].Port ||
+			a.Listeners[i].Protocol != b.Listeners[i].Protocol ||
+			!reflect.DeepEqual(a.Listeners[i].Hostname, b.Listeners[i].Hostname) ||
+			!conditionsEqual(a.Listeners[i].Conditions, b.Listeners[i].Conditions) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func routeStatusEqual(a, b v1alpha1.RouteStatus) bool {
+	if len(a.Gateways) != len(b.Gateways) {
+		return false
+	}
+
+	for i := range a.Gateways {
+		if a.Gateways[i].GatewayRef != b.Gateways[i].GatewayRef ||
+			!conditionsEqual(a.Gateways[i].Conditions, b.Gateways[i].Conditions) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var _ cache.ResourceEventHandler = (*resourceEventHandler)(nil)
@@ -0,0 +1,188 @@
+package gateway
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway/networking/v1alpha1"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ Client = (*clientMock)(nil)
+
+func init() {
+	// required by k8s.MustParseYaml
+	err := v1alpha1.AddToScheme(scheme.Scheme)
+	if err != nil {
+		panic(err)
+	}
+}
+
+type clientMock struct {
+	services   []*corev1.Service
+	secrets    []*corev1.Secret
+	endpoints  []*corev1.Endpoints
+	namespaces []*corev1.Namespace
+
+	apiServiceError   error
+	apiSecretError    error
+	apiEndpointsError error
+
+	gatewayClasses []*v1alpha1.GatewayClass
+	gateways       []*v1alpha1.Gateway
+	httpRoutes     []*v1alpha1.HTTPRoute
+	tlsRoutes      []*v1alpha1.TLSRoute
+
+	gatewayClassStatuses map[string]v1alpha1.GatewayClassStatus
+	gatewayStatuses      map[string]v1alpha1.GatewayStatus
+	routeStatuses        map[string]v1alpha1.RouteStatus
+
+	watchChan chan interface{}
+}
+
+func newClientMock(paths ...string) clientMock {
+	c := clientMock{
+		gatewayClassStatuses: map[string]v1alpha1.GatewayClassStatus{},
+		gatewayStatuses:      map[string]v1alpha1.GatewayStatus{},
+		routeStatuses:        map[string]v1alpha1.RouteStatus{},
+	}
+
+	for _, path := range paths {
+		yamlContent, err := ioutil.ReadFile(filepath.FromSlash("./fixtures/" + path))
+		if err != nil {
+			panic(err)
+		}
+
+		k8sObjects := k8s.MustParseYaml(yamlContent)
+		for _, obj := range k8sObjects {
+			switch o := obj.(type) {
+			case *corev1.Service:
+				c.services = append(c.services, o)
+			case *corev1.Endpoints:
+				c.endpoints = append(c.endpoints, o)
+			case *corev1.Secret:
+				c.secrets = append(c.secrets, o)
+			case *corev1.Namespace:
+				c.namespaces = append(c.namespaces, o)
+			case *v1alpha1.GatewayClass:
+				c.gatewayClasses = append(c.gatewayClasses, o)
+			case *v1alpha1.Gateway:
+				c.gateways = append(c.gateways, o)
+			case *v1alpha1.HTTPRoute:
+				c.httpRoutes = append(c.httpRoutes, o)
+			case *v1alpha1.TLSRoute:
+				c.tlsRoutes = append(c.tlsRoutes, o)
+			default:
+				panic(fmt.Sprintf("Unknown runtime object %+v %T", o, o))
+			}
+		}
+	}
+
+	return c
+}
+
+func (c clientMock) GetGatewayClasses() []*v1alpha1.GatewayClass {
+	return c.gatewayClasses
+}
+
+func (c clientMock) GetGateways() []*v1alpha1.Gateway {
+	return c.gateways
+}
+
+func (c clientMock) GetHTTPRoutes(namespace string, selector labels.Selector) []*v1alpha1.HTTPRoute {
+	var routes []*v1alpha1.HTTPRoute
+	for _, route := range c.httpRoutes {
+		if route.Namespace == namespace && selector.Matches(labels.Set(route.Labels)) {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func (c clientMock) GetTLSRoutes(namespace string, selector labels.Selector) []*v1alpha1.TLSRoute {
+	var routes []*v1alpha1.TLSRoute
+	for _, route := range c.tlsRoutes {
+		if route.Namespace == namespace && selector.Matches(labels.Set(route.Labels)) {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+func (c clientMock) GetNamespaces(selector labels.Selector) []string {
+	var namespaces []string
+	for _, namespace := range c.namespaces {
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+	return namespaces
+}
+
+func (c clientMock) UpdateGatewayClassStatus(gatewayClass *v1alpha1.GatewayClass, status v1alpha1.GatewayClassStatus) error {
+	c.gatewayClassStatuses[gatewayClass.Name] = status
+	return nil
+}
+
+func (c clientMock) UpdateGatewayStatus(gateway *v1alpha1.Gateway, status v1alpha1.GatewayStatus) error {
+	c.gatewayStatuses[gateway.Namespace+"/"+gateway.Name] = status
+	return nil
+}
+
+func (c clientMock) UpdateHTTPRouteStatus(route *v1alpha1.HTTPRoute, status v1alpha1.RouteStatus) error {
+	c.routeStatuses["HTTPRoute:"+route.Namespace+"/"+route.Name] = status
+	return nil
+}
+
+func (c clientMock) UpdateTLSRouteStatus(route *v1alpha1.TLSRoute, status v1alpha1.RouteStatus) error {
+	c.routeStatuses["TLSRoute:"+route.Namespace+"/"+route.Name] = status
+	return nil
+}
+
+func (c clientMock) GetService(namespace, name string) (*corev1.Service, bool, error) {
+	if c.apiServiceError != nil {
+		return nil, false, c.apiServiceError
+	}
+
+	for _, service := range c.services {
+		if service.Namespace == namespace && service.Name == name {
+			return service, true, nil
+		}
+	}
+	return nil, false, c.apiServiceError
+}
+
+func (c clientMock) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	if c.apiEndpointsError != nil {
+		return nil, false, c.apiEndpointsError
+	}
+
+	for _, endpoints := range c.endpoints {
+		if endpoints.Namespace == namespace && endpoints.Name == name {
+			return endpoints, true, nil
+		}
+	}
+
+	return &corev1.Endpoints{}, false, nil
+}
+
+func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
+	if c.apiSecretError != nil {
+		return nil, false, c.apiSecretError
+	}
+
+	for _, secret := range c.secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+	return c.watchChan, nil
+}
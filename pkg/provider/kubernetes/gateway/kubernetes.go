@@ -0,0 +1,627 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v3"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/job"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway/networking/v1alpha1"
+	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/tls"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/mitchellh/hashstructure"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	providerName = "kubernetesgateway"
+
+	// controllerName is the value of the GatewayClass controller field handled by Traefik.
+	controllerName = "traefik.io/gateway-controller"
+
+	kindHTTPRoute = "HTTPRoute"
+	kindTLSRoute  = "TLSRoute"
+	kindSecret    = "Secret"
+)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	Endpoint         string                `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token            string                `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath string                `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces       []string              `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	LabelSelector    string                `description:"Kubernetes label selector to select specific Gateways." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	ThrottleDuration types.Duration        `description:"Kubernetes refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	EntryPoints      map[string]Entrypoint `json:"-" toml:"-" yaml:"-" label:"-"`
+
+	lastConfiguration safe.Safe
+}
+
+// Entrypoint defines the available entry points, Gateway listeners are bound to them by port.
+type Entrypoint struct {
+	Address string
+}
+
+func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
+	labelSel, err := labels.Parse(p.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %q", p.LabelSelector)
+	}
+	log.FromContext(ctx).Infof("label selector is: %q", labelSel)
+
+	withEndpoint := ""
+	if p.Endpoint != "" {
+		withEndpoint = fmt.Sprintf(" with endpoint %s", p.Endpoint)
+	}
+
+	var client *clientWrapper
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "":
+		log.FromContext(ctx).Infof("Creating in-cluster Provider client%s", withEndpoint)
+		client, err = newInClusterClient(p.Endpoint)
+	case os.Getenv("KUBECONFIG") != "":
+		log.FromContext(ctx).Infof("Creating cluster-external Provider client from KUBECONFIG %s", os.Getenv("KUBECONFIG"))
+		client, err = newExternalClusterClientFromFile(os.Getenv("KUBECONFIG"))
+	default:
+		log.FromContext(ctx).Infof("Creating cluster-external Provider client%s", withEndpoint)
+		client, err = newExternalClusterClient(p.Endpoint, p.Token, p.CertAuthFilePath)
+	}
+
+	if err == nil {
+		client.labelSelector = labelSel
+	}
+
+	return client, err
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	return nil
+}
+
+// Provide allows the k8s provider to provide configurations to traefik
+// using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	ctxLog := log.With(context.Background(), log.Str(log.ProviderName, providerName))
+	logger := log.FromContext(ctxLog)
+
+	k8sClient, err := p.newK8sClient(ctxLog)
+	if err != nil {
+		return err
+	}
+
+	pool.Go(func(stop chan bool) {
+		operation := func() error {
+			stopWatch := make(chan struct{}, 1)
+			defer close(stopWatch)
+			eventsChan, err := k8sClient.WatchAll(p.Namespaces, stopWatch)
+
+			if err != nil {
+				logger.Errorf("Error watching kubernetes events: %v", err)
+				timer := time.NewTimer(1 * time.Second)
+				select {
+				case <-timer.C:
+					return err
+				case <-stop:
+					return nil
+				}
+			}
+
+			throttleDuration := time.Duration(p.ThrottleDuration)
+			throttledChan := throttleEvents(ctxLog, throttleDuration, stop, eventsChan)
+			if throttledChan != nil {
+				eventsChan = throttledChan
+			}
+
+			for {
+				select {
+				case <-stop:
+					return nil
+				case event := <-eventsChan:
+					// Note that event is the *first* event that came in during this
+					// throttling interval -- if we're hitting our throttle, we may have
+					// dropped events. This is fine, because we don't treat different
+					// event types differently.
+					conf := p.loadConfigurationFromGateway(ctxLog, k8sClient)
+
+					confHash, err := hashstructure.Hash(conf, nil)
+					switch {
+					case err != nil:
+						logger.Error("Unable to hash the configuration")
+					case p.lastConfiguration.Get() == confHash:
+						logger.Debugf("Skipping Kubernetes event kind %T", event)
+					default:
+						p.lastConfiguration.Set(confHash)
+						configurationChan <- dynamic.Message{
+							ProviderName:  providerName,
+							Configuration: conf,
+						}
+					}
+
+					// If we're throttling, we sleep here for the throttle duration to
+					// enforce that we don't refresh faster than our throttle. time.Sleep
+					// returns immediately if p.ThrottleDuration is 0 (no throttle).
+					time.Sleep(throttleDuration)
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error: %s; retrying in %s", err, time)
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to Provider: %s", err)
+		}
+	})
+
+	return nil
+}
+
+// routeStatuses accumulates the status of the routes across all the Gateways they are bound to.
+type routeStatuses struct {
+	http map[string]*v1alpha1.RouteStatus
+	tls  map[string]*v1alpha1.RouteStatus
+}
+
+func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Client) *dynamic.Configuration {
+	logger := log.FromContext(ctx)
+
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     map[string]*dynamic.Router{},
+			Middlewares: map[string]*dynamic.Middleware{},
+			Services:    map[string]*dynamic.Service{},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  map[string]*dynamic.TCPRouter{},
+			Services: map[string]*dynamic.TCPService{},
+		},
+		TLS: &dynamic.TLSConfiguration{},
+	}
+
+	gatewayClassNames := map[string]struct{}{}
+	for _, gatewayClass := range client.GetGatewayClasses() {
+		if gatewayClass.Spec.Controller != controllerName {
+			continue
+		}
+
+		gatewayClassNames[gatewayClass.Name] = struct{}{}
+
+		status := v1alpha1.GatewayClassStatus{
+			Conditions: []v1alpha1.Condition{
+				newCondition(v1alpha1.GatewayClassConditionAdmitted, corev1.ConditionTrue, gatewayClass.Generation, "Handled", "Handled by Traefik controller"),
+			},
+		}
+		if err := client.UpdateGatewayClassStatus(gatewayClass, status); err != nil {
+			logger.Errorf("Failed to update GatewayClass %s status: %v", gatewayClass.Name, err)
+		}
+	}
+
+	tlsConfigs := make(map[string]*tls.CertAndStores)
+	statuses := routeStatuses{
+		http: map[string]*v1alpha1.RouteStatus{},
+		tls:  map[string]*v1alpha1.RouteStatus{},
+	}
+
+	for _, gateway := range client.GetGateways() {
+		if _, ok := gatewayClassNames[gateway.Spec.GatewayClassName]; !ok {
+			continue
+		}
+
+		ctxGateway := log.With(ctx, log.Str("gateway", gateway.Namespace+"/"+gateway.Name))
+
+		status := p.loadGatewayConfiguration(ctxGateway, client, gateway, conf, tlsConfigs, statuses)
+		if err := client.UpdateGatewayStatus(gateway, status); err != nil {
+			log.FromContext(ctxGateway).Errorf("Failed to update Gateway status: %v", err)
+		}
+	}
+
+	p.updateRoutesStatus(ctx, client, statuses)
+
+	if len(tlsConfigs) > 0 {
+		conf.TLS.Certificates = getTLSConfig(tlsConfigs)
+	}
+
+	return conf
+}
+
+func (p *Provider) loadGatewayConfiguration(ctx context.Context, client Client, gateway *v1alpha1.Gateway, conf *dynamic.Configuration, tlsConfigs map[string]*tls.CertAndStores, statuses routeStatuses) v1alpha1.GatewayStatus {
+	logger := log.FromContext(ctx)
+
+	gatewayStatus := v1alpha1.GatewayStatus{}
+	allListenersReady := true
+
+	for i, listener := range gateway.Spec.Listeners {
+		listenerStatus := v1alpha1.ListenerStatus{
+			Port:     listener.Port,
+			Protocol: listener.Protocol,
+			Hostname: listener.Hostname,
+		}
+
+		conditions, err := p.loadListenerConfiguration(ctx, client, gateway, listener, conf, tlsConfigs, statuses)
+		if err != nil {
+			logger.Errorf("Cannot bind listener %d: %v", i, err)
+			allListenersReady = false
+		}
+		listenerStatus.Conditions = conditions
+
+		gatewayStatus.Listeners = append(gatewayStatus.Listeners, listenerStatus)
+	}
+
+	if allListenersReady {
+		gatewayStatus.Conditions = []v1alpha1.Condition{
+			newCondition(v1alpha1.GatewayConditionScheduled, corev1.ConditionTrue, gateway.Generation, "Scheduled", "All the listeners are bound to entry points"),
+			newCondition(v1alpha1.GatewayConditionReady, corev1.ConditionTrue, gateway.Generation, "ListenersReady", "All the listeners are ready"),
+		}
+	} else {
+		gatewayStatus.Conditions = []v1alpha1.Condition{
+			newCondition(v1alpha1.GatewayConditionScheduled, corev1.ConditionTrue, gateway.Generation, "Scheduled", "Handled by Traefik controller"),
+			newCondition(v1alpha1.GatewayConditionReady, corev1.ConditionFalse, gateway.Generation, "ListenersNotReady", "Some listeners are not ready, see their status"),
+		}
+	}
+
+	return gatewayStatus
+}
+
+// loadListenerConfiguration adds the routes bound to the listener to the configuration.
+// It returns the listener conditions, and an error if the listener is not ready.
+func (p *Provider) loadListenerConfiguration(ctx context.Context, client Client, gateway *v1alpha1.Gateway, listener v1alpha1.Listener, conf *dynamic.Configuration, tlsConfigs map[string]*tls.CertAndStores, statuses routeStatuses) ([]v1alpha1.Condition, error) {
+	notReady := func(conditionType, reason string, err error) ([]v1alpha1.Condition, error) {
+		return []v1alpha1.Condition{
+			newCondition(conditionType, corev1.ConditionTrue, gateway.Generation, reason, err.Error()),
+			newCondition(v1alpha1.ListenerConditionReady, corev1.ConditionFalse, gateway.Generation, reason, err.Error()),
+		}, err
+	}
+
+	ep, err := p.entryPointName(listener.Port)
+	if err != nil {
+		return notReady(v1alpha1.ListenerConditionDetached, "PortUnavailable", err)
+	}
+
+	var expectedKind string
+	switch listener.Protocol {
+	case v1alpha1.HTTPProtocolType, v1alpha1.HTTPSProtocolType:
+		expectedKind = kindHTTPRoute
+	case v1alpha1.TLSProtocolType:
+		expectedKind = kindTLSRoute
+	default:
+		return notReady(v1alpha1.ListenerConditionDetached, "UnsupportedProtocol", fmt.Errorf("unsupported listener protocol %q", listener.Protocol))
+	}
+
+	if listener.Routes.Kind != expectedKind {
+		return notReady(v1alpha1.ListenerConditionResolvedRefs, "InvalidRoutesRef", fmt.Errorf("unsupported route kind %q for protocol %s, expected %s", listener.Routes.Kind, listener.Protocol, expectedKind))
+	}
+
+	passthrough := false
+	if listener.Protocol != v1alpha1.HTTPProtocolType {
+		if listener.TLS == nil {
+			return notReady(v1alpha1.ListenerConditionResolvedRefs, "InvalidCertificateRef", fmt.Errorf("missing TLS configuration for protocol %s", listener.Protocol))
+		}
+
+		passthrough = listener.TLS.Mode != nil && *listener.TLS.Mode == v1alpha1.TLSModePassthrough
+		if passthrough && listener.Protocol == v1alpha1.HTTPSProtocolType {
+			return notReady(v1alpha1.ListenerConditionDetached, "UnsupportedProtocol", errors.New("the Passthrough TLS mode is not supported with the HTTPS protocol"))
+		}
+
+		if !passthrough {
+			if err := loadListenerCertificate(client, gateway.Namespace, listener.TLS.CertificateRef, tlsConfigs); err != nil {
+				return notReady(v1alpha1.ListenerConditionResolvedRefs, "InvalidCertificateRef", err)
+			}
+		}
+	}
+
+	namespaces, err := routeNamespaces(client, gateway.Namespace, listener.Routes.Namespaces)
+	if err != nil {
+		return notReady(v1alpha1.ListenerConditionResolvedRefs, "InvalidRoutesRef", err)
+	}
+
+	routeSelector := labels.Everything()
+	if listener.Routes.Selector != nil {
+		routeSelector, err = metav1.LabelSelectorAsSelector(listener.Routes.Selector)
+		if err != nil {
+			return notReady(v1alpha1.ListenerConditionResolvedRefs, "InvalidRoutesRef", fmt.Errorf("invalid route selector: %v", err))
+		}
+	}
+
+	gatewayRef := v1alpha1.GatewayReference{Name: gateway.Name, Namespace: gateway.Namespace}
+
+	for _, namespace := range namespaces {
+		switch expectedKind {
+		case kindHTTPRoute:
+			for _, route := range client.GetHTTPRoutes(namespace, routeSelector) {
+				err := loadHTTPRoute(client, gateway, ep, listener, route, conf)
+				addRouteCondition(statuses.http, route.Namespace+"/"+route.Name, route.Generation, gatewayRef, err)
+				if err != nil {
+					log.FromContext(ctx).Errorf("Cannot load HTTPRoute %s/%s: %v", route.Namespace, route.Name, err)
+				}
+			}
+
+		case kindTLSRoute:
+			for _, route := range client.GetTLSRoutes(namespace, routeSelector) {
+				err := loadTLSRoute(client, gateway, ep, listener, passthrough, route, conf)
+				addRouteCondition(statuses.tls, route.Namespace+"/"+route.Name, route.Generation, gatewayRef, err)
+				if err != nil {
+					log.FromContext(ctx).Errorf("Cannot load TLSRoute %s/%s: %v", route.Namespace, route.Name, err)
+				}
+			}
+		}
+	}
+
+	return []v1alpha1.Condition{
+		newCondition(v1alpha1.ListenerConditionReady, corev1.ConditionTrue, gateway.Generation, "Ready", "Bound to entry point "+ep),
+	}, nil
+}
+
+func (p *Provider) updateRoutesStatus(ctx context.Context, client Client, statuses routeStatuses) {
+	logger := log.FromContext(ctx)
+
+	for _, namespace := range client.GetNamespaces(labels.Everything()) {
+		for _, route := range client.GetHTTPRoutes(namespace, labels.Everything()) {
+			status, ok := statuses.http[route.Namespace+"/"+route.Name]
+			if !ok {
+				continue
+			}
+
+			if err := client.UpdateHTTPRouteStatus(route, *status); err != nil {
+				logger.Errorf("Failed to update HTTPRoute %s/%s status: %v", route.Namespace, route.Name, err)
+			}
+		}
+
+		for _, route := range client.GetTLSRoutes(namespace, labels.Everything()) {
+			status, ok := statuses.tls[route.Namespace+"/"+route.Name]
+			if !ok {
+				continue
+			}
+
+			if err := client.UpdateTLSRouteStatus(route, *status); err != nil {
+				logger.Errorf("Failed to update TLSRoute %s/%s status: %v", route.Namespace, route.Name, err)
+			}
+		}
+	}
+}
+
+// entryPointName returns the name of the entry point listening on the given port.
+func (p *Provider) entryPointName(port v1alpha1.PortNumber) (string, error) {
+	var names []string
+	for name := range p.EntryPoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, epPort, err := net.SplitHostPort(p.EntryPoints[name].Address)
+		if err != nil {
+			continue
+		}
+
+		if epPort == strconv.Itoa(int(port)) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no entry point found for port %d", port)
+}
+
+func routeNamespaces(client Client, gatewayNamespace string, routeNamespaces v1alpha1.RouteNamespaces) ([]string, error) {
+	switch routeNamespaces.From {
+	case "", v1alpha1.RouteSelectSame:
+		return []string{gatewayNamespace}, nil
+	case v1alpha1.RouteSelectAll:
+		return client.GetNamespaces(labels.Everything()), nil
+	case v1alpha1.RouteSelectSelector:
+		if routeNamespaces.Selector == nil {
+			return nil, errors.New("missing namespace selector")
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(routeNamespaces.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector: %v", err)
+		}
+		return client.GetNamespaces(selector), nil
+	default:
+		return nil, fmt.Errorf("unsupported namespace selection %q", routeNamespaces.From)
+	}
+}
+
+func loadListenerCertificate(client Client, namespace string, certificateRef *v1alpha1.LocalObjectReference, tlsConfigs map[string]*tls.CertAndStores) error {
+	if certificateRef == nil {
+		return errors.New("missing certificate reference")
+	}
+
+	if certificateRef.Kind != kindSecret || (certificateRef.Group != "" && certificateRef.Group != "core") {
+		return fmt.Errorf("unsupported certificate reference %s/%s", certificateRef.Group, certificateRef.Kind)
+	}
+
+	configKey := namespace + "/" + certificateRef.Name
+	if _, exists := tlsConfigs[configKey]; exists {
+		return nil
+	}
+
+	tlsConf, err := getTLS(client, certificateRef.Name, namespace)
+	if err != nil {
+		return err
+	}
+
+	tlsConfigs[configKey] = tlsConf
+	return nil
+}
+
+func addRouteCondition(statuses map[string]*v1alpha1.RouteStatus, key string, generation int64, gatewayRef v1alpha1.GatewayReference, err error) {
+	condition := newCondition(v1alpha1.RouteConditionAdmitted, corev1.ConditionTrue, generation, "Admitted", "Admitted by Traefik controller")
+	if err != nil {
+		condition = newCondition(v1alpha1.RouteConditionAdmitted, corev1.ConditionFalse, generation, "Invalid", err.Error())
+	}
+
+	status, ok := statuses[key]
+	if !ok {
+		status = &v1alpha1.RouteStatus{}
+		statuses[key] = status
+	}
+
+	for i, gw := range status.Gateways {
+		if gw.GatewayRef != gatewayRef {
+			continue
+		}
+
+		// The route is bound to several listeners of the same Gateway, an error on any of them prevails.
+		if err != nil {
+			status.Gateways[i].Conditions = []v1alpha1.Condition{condition}
+		}
+		return
+	}
+
+	status.Gateways = append(status.Gateways, v1alpha1.RouteGatewayStatus{
+		GatewayRef: gatewayRef,
+		Conditions: []v1alpha1.Condition{condition},
+	})
+}
+
+func newCondition(conditionType string, status corev1.ConditionStatus, generation int64, reason, message string) v1alpha1.Condition {
+	return v1alpha1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+func makeRouterKey(rule, name string) (string, error) {
+	h := sha256.New()
+	if _, err := h.Write([]byte(rule)); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%s-%.10x", name, h.Sum(nil))
+
+	return key, nil
+}
+
+func makeID(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "-" + name
+}
+
+func getTLS(k8sClient Client, secretName, namespace string) (*tls.CertAndStores, error) {
+	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret %s/%s: %v", namespace, secretName, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("secret %s/%s does not exist", namespace, secretName)
+	}
+
+	cert, key, err := getCertificateBlocks(secret, namespace, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.CertAndStores{
+		Certificate: tls.Certificate{
+			CertFile: tls.FileOrContent(cert),
+			KeyFile:  tls.FileOrContent(key),
+		},
+	}, nil
+}
+
+func getTLSConfig(tlsConfigs map[string]*tls.CertAndStores) []*tls.CertAndStores {
+	var secretNames []string
+	for secretName := range tlsConfigs {
+		secretNames = append(secretNames, secretName)
+	}
+	sort.Strings(secretNames)
+
+	var configs []*tls.CertAndStores
+	for _, secretName := range secretNames {
+		configs = append(configs, tlsConfigs[secretName])
+	}
+
+	return configs
+}
+
+func getCertificateBlocks(secret *corev1.Secret, namespace, secretName string) (string, string, error) {
+	var missingEntries []string
+
+	tlsCrtData, tlsCrtExists := secret.Data["tls.crt"]
+	if !tlsCrtExists {
+		missingEntries = append(missingEntries, "tls.crt")
+	}
+
+	tlsKeyData, tlsKeyExists := secret.Data["tls.key"]
+	if !tlsKeyExists {
+		missingEntries = append(missingEntries, "tls.key")
+	}
+
+	if len(missingEntries) > 0 {
+		return "", "", fmt.Errorf("secret %s/%s is missing the following TLS data entries: %s",
+			namespace, secretName, strings.Join(missingEntries, ", "))
+	}
+
+	cert := string(tlsCrtData)
+	if cert == "" {
+		missingEntries = append(missingEntries, "tls.crt")
+	}
+
+	key := string(tlsKeyData)
+	if key == "" {
+		missingEntries = append(missingEntries, "tls.key")
+	}
+
+	if len(missingEntries) > 0 {
+		return "", "", fmt.Errorf("secret %s/%s contains the following empty TLS data entries: %s",
+			namespace, secretName, strings.Join(missingEntries, ", "))
+	}
+
+	return cert, key, nil
+}
+
+func throttleEvents(ctx context.Context, throttleDuration time.Duration, stop chan bool, eventsChan <-chan interface{}) chan interface{} {
+	if throttleDuration == 0 {
+		return nil
+	}
+	// Create a buffered channel to hold the pending event (if we're delaying processing the event due to throttling)
+	eventsChanBuffered := make(chan interface{}, 1)
+
+	// Run a goroutine that reads events from eventChan and does a
+	// non-blocking write to pendingEvent. This guarantees that writing to
+	// eventChan will never block, and that pendingEvent will have
+	// something in it if there's been an event since we read from that channel.
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case nextEvent := <-eventsChan:
+				select {
+				case eventsChanBuffered <- nextEvent:
+				default:
+					// We already have an event in eventsChanBuffered, so we'll
+					// do a refresh as soon as our throttle allows us to. It's fine
+					// to drop the event and keep whatever's in the buffer -- we
+					// don't do different things for different events
+					log.FromContext(ctx).Debugf("Dropping event kind %T due to throttling", nextEvent)
+				}
+			}
+		}
+	}()
+
+	return eventsChanBuffered
+}
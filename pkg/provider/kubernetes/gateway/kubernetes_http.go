@@ -0,0 +1,451 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway/networking/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// loadHTTPRoute adds the routers, middlewares and services of an HTTPRoute bound to a Gateway listener.
+// Nothing is added when one of the rules of the route is invalid.
+func loadHTTPRoute(client Client, gateway *v1alpha1.Gateway, ep string, listener v1alpha1.Listener, route *v1alpha1.HTTPRoute, conf *dynamic.Configuration) error {
+	hostRule, err := hostRule(listener.Hostname, route.Spec.Hostnames)
+	if err != nil {
+		return err
+	}
+
+	routers := make(map[string]*dynamic.Router)
+	middlewares := make(map[string]*dynamic.Middleware)
+	services := make(map[string]*dynamic.Service)
+
+	for i, rule := range route.Spec.Rules {
+		if len(rule.ForwardTo) == 0 {
+			return fmt.Errorf("rule %d: missing forwardTo", i)
+		}
+
+		rule, err := extractRule(hostRule, rule.Matches)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		routerKey, err := makeRouterKey(rule, makeID(gateway.Namespace, gateway.Name)+"-"+ep)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+		routerName := makeID(route.Namespace, route.Name) + "-" + routerKey
+
+		router := &dynamic.Router{
+			Rule:        rule,
+			EntryPoints: []string{ep},
+		}
+		if listener.Protocol == v1alpha1.HTTPSProtocolType {
+			router.TLS = &dynamic.RouterTLSConfig{}
+		}
+
+		middlewareNames, ruleMiddlewares, err := loadMiddlewares(routerName, route.Spec.Rules[i].Filters)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		serviceName, ruleServices, err := loadHTTPServices(client, route.Namespace, routerName, route.Spec.Rules[i].ForwardTo)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		for name, middleware := range ruleMiddlewares {
+			middlewares[name] = middleware
+		}
+		router.Middlewares = middlewareNames
+
+		for name, service := range ruleServices {
+			services[name] = service
+		}
+
+		router.Service = serviceName
+		routers[routerName] = router
+	}
+
+	for name, router := range routers {
+		conf.HTTP.Routers[name] = router
+	}
+	for name, middleware := range middlewares {
+		conf.HTTP.Middlewares[name] = middleware
+	}
+	for name, service := range services {
+		conf.HTTP.Services[name] = service
+	}
+
+	return nil
+}
+
+// hostRule returns the host matcher of the route, restricted by the listener hostname.
+func hostRule(listenerHostname *v1alpha1.Hostname, hostnames []v1alpha1.Hostname) (string, error) {
+	var hosts []v1alpha1.Hostname
+	for _, hostname := range hostnames {
+		if listenerHostname == nil || hostnameMatches(*listenerHostname, hostname) {
+			hosts = append(hosts, hostname)
+		}
+	}
+
+	switch {
+	case len(hostnames) > 0 && len(hosts) == 0:
+		return "", fmt.Errorf("no hostname matches the listener hostname %q", *listenerHostname)
+	case len(hosts) == 0 && listenerHostname != nil && *listenerHostname != "*":
+		hosts = []v1alpha1.Hostname{*listenerHostname}
+	}
+
+	var plain []string
+	var rules []string
+	for _, host := range hosts {
+		if host == "*" {
+			return "", nil
+		}
+
+		if strings.HasPrefix(string(host), "*.") {
+			rules = append(rules, fmt.Sprintf("HostRegexp(`{subdomain:[a-zA-Z0-9-]+}.%s`)", strings.TrimPrefix(string(host), "*.")))
+			continue
+		}
+		plain = append(plain, "`"+string(host)+"`")
+	}
+
+	if len(plain) > 0 {
+		rules = append([]string{"Host(" + strings.Join(plain, ", ") + ")"}, rules...)
+	}
+
+	if len(rules) > 1 {
+		return "(" + strings.Join(rules, " || ") + ")", nil
+	}
+
+	return strings.Join(rules, ""), nil
+}
+
+// hostnameMatches reports whether the route hostname is accepted by the listener hostname.
+func hostnameMatches(listenerHostname, hostname v1alpha1.Hostname) bool {
+	if listenerHostname == "*" || listenerHostname == hostname {
+		return true
+	}
+
+	if strings.HasPrefix(string(listenerHostname), "*.") {
+		return strings.HasSuffix(string(hostname), strings.TrimPrefix(string(listenerHostname), "*"))
+	}
+
+	return false
+}
+
+// extractRule builds the router rule, the route matches are ORed and ANDed with the host rule.
+func extractRule(hostRule string, matches []v1alpha1.HTTPRouteMatch) (string, error) {
+	var matchRules []string
+	for _, match := range matches {
+		var parts []string
+
+		if match.Path != nil {
+			pathRule, err := pathRule(match.Path)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, pathRule)
+		}
+
+		if match.Headers != nil {
+			headerRules, err := headerRules(match.Headers)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, headerRules...)
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		if len(parts) > 1 && len(matches) > 1 {
+			matchRules = append(matchRules, "("+strings.Join(parts, " && ")+")")
+			continue
+		}
+		matchRules = append(matchRules, strings.Join(parts, " && "))
+	}
+
+	matchRule := strings.Join(matchRules, " || ")
+	if len(matchRules) > 1 && hostRule != "" {
+		matchRule = "(" + matchRule + ")"
+	}
+
+	switch {
+	case hostRule != "" && matchRule != "":
+		return hostRule + " && " + matchRule, nil
+	case hostRule != "":
+		return hostRule, nil
+	case matchRule != "":
+		return matchRule, nil
+	default:
+		return "PathPrefix(`/`)", nil
+	}
+}
+
+func pathRule(path *v1alpha1.HTTPPathMatch) (string, error) {
+	switch path.Type {
+	case v1alpha1.PathMatchExact:
+		return "Path(`" + path.Value + "`)", nil
+	case "", v1alpha1.PathMatchPrefix:
+		return "PathPrefix(`" + path.Value + "`)", nil
+	case v1alpha1.PathMatchRegularExpression:
+		// The matcher is anchored on the whole path.
+		expr := strings.TrimSuffix(strings.TrimPrefix(path.Value, "^"), "$")
+		if _, err := regexp.Compile(expr); err != nil {
+			return "", fmt.Errorf("invalid path regular expression %q: %v", path.Value, err)
+		}
+
+		// The path template must start with a slash, the variable matches the rest of the path.
+		if !strings.HasPrefix(expr, "/") {
+			return "", fmt.Errorf("path regular expression %q must start with a slash", path.Value)
+		}
+		if expr == "/" {
+			return "Path(`/`)", nil
+		}
+		return "Path(`/{path:" + expr[1:] + "}`)", nil
+	default:
+		return "", fmt.Errorf("unsupported path match type %q", path.Type)
+	}
+}
+
+func headerRules(headers *v1alpha1.HTTPHeaderMatch) ([]string, error) {
+	var matcher string
+	switch headers.Type {
+	case "", v1alpha1.HeaderMatchExact:
+		matcher = "Headers"
+	case v1alpha1.HeaderMatchRegularExpression:
+		matcher = "HeadersRegexp"
+	default:
+		return nil, fmt.Errorf("unsupported header match type %q", headers.Type)
+	}
+
+	var names []string
+	for name := range headers.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rules []string
+	for _, name := range names {
+		rules = append(rules, fmt.Sprintf("%s(`%s`, `%s`)", matcher, name, headers.Values[name]))
+	}
+
+	return rules, nil
+}
+
+// loadMiddlewares returns the names of the middlewares created from the filters, in the order of the filters, and the middlewares.
+func loadMiddlewares(routerName string, filters []v1alpha1.HTTPRouteFilter) ([]string, map[string]*dynamic.Middleware, error) {
+	var names []string
+	middlewares := make(map[string]*dynamic.Middleware)
+
+	for i, filter := range filters {
+		name := fmt.Sprintf("%s-%s-%d", routerName, strings.ToLower(string(filter.Type)), i)
+
+		switch filter.Type {
+		case v1alpha1.HTTPRouteFilterRequestHeaderModifier:
+			if filter.RequestHeaderModifier == nil {
+				return nil, nil, fmt.Errorf("filter %d: missing %s configuration", i, filter.Type)
+			}
+			middleware, err := createRequestHeaderModifier(filter.RequestHeaderModifier)
+			if err != nil {
+				return nil, nil, fmt.Errorf("filter %d: %v", i, err)
+			}
+			middlewares[name] = middleware
+
+		case v1alpha1.HTTPRouteFilterRequestRedirect:
+			if filter.RequestRedirect == nil {
+				return nil, nil, fmt.Errorf("filter %d: missing %s configuration", i, filter.Type)
+			}
+			middlewares[name] = createRequestRedirect(filter.RequestRedirect)
+
+		default:
+			return nil, nil, fmt.Errorf("filter %d: unsupported filter type %q", i, filter.Type)
+		}
+
+		names = append(names, name)
+	}
+
+	return names, middlewares, nil
+}
+
+// createRequestHeaderModifier returns the headers middleware setting and removing the request headers.
+// Adding a value to a header, without replacing its current values, is not supported by the headers middleware.
+func createRequestHeaderModifier(filter *v1alpha1.HTTPRequestHeaderFilter) (*dynamic.Middleware, error) {
+	if len(filter.Add) > 0 {
+		return nil, errors.New("the add action of the RequestHeaderModifier filter is not supported, use the set action instead")
+	}
+
+	headers := make(map[string]string)
+	for name, value := range filter.Set {
+		headers[name] = value
+	}
+	// An empty value removes the header.
+	for _, name := range filter.Remove {
+		headers[name] = ""
+	}
+
+	return &dynamic.Middleware{
+		Headers: &dynamic.Headers{CustomRequestHeaders: headers},
+	}, nil
+}
+
+func createRequestRedirect(filter *v1alpha1.HTTPRequestRedirectFilter) *dynamic.Middleware {
+	scheme := "${1}"
+	if filter.Protocol != nil {
+		scheme = strings.ToLower(*filter.Protocol)
+	}
+
+	host := "${2}"
+	if filter.Hostname != nil {
+		host = *filter.Hostname
+	}
+
+	// The port of the request is kept only when the scheme is, as it would not be the one of the new scheme otherwise.
+	port := "${3}"
+	switch {
+	case filter.Port != nil:
+		port = ":" + strconv.Itoa(int(*filter.Port))
+	case filter.Protocol != nil:
+		port = ""
+	}
+
+	return &dynamic.Middleware{
+		RedirectRegex: &dynamic.RedirectRegex{
+			Regex:       `^([a-z]+)://([^/:]+)(:[0-9]+)?(.*)$`,
+			Replacement: scheme + "://" + host + port + "${4}",
+			Permanent:   filter.StatusCode != nil && *filter.StatusCode == 301,
+		},
+	}
+}
+
+// loadHTTPServices returns the name of the service handling the requests, and the services to add to the configuration.
+// A weighted service is created in front of the load-balancers when the requests are forwarded to several Kubernetes services.
+func loadHTTPServices(client Client, namespace, routerName string, forwardTo []v1alpha1.RouteForwardTo) (string, map[string]*dynamic.Service, error) {
+	services := make(map[string]*dynamic.Service)
+	wrr := &dynamic.WeightedRoundRobin{}
+
+	for _, fwd := range forwardTo {
+		servers, err := loadHTTPServers(client, namespace, fwd)
+		if err != nil {
+			return "", nil, err
+		}
+
+		lb := &dynamic.ServersLoadBalancer{}
+		lb.SetDefaults()
+		lb.Servers = servers
+
+		name := makeID(namespace, fmt.Sprintf("%s-%d", fwd.ServiceName, fwd.Port))
+		services[name] = &dynamic.Service{LoadBalancer: lb}
+
+		wrr.Services = append(wrr.Services, dynamic.WRRService{Name: name, Weight: weight(fwd.Weight)})
+	}
+
+	if len(wrr.Services) == 1 {
+		return wrr.Services[0].Name, services, nil
+	}
+
+	name := routerName + "-wrr"
+	services[name] = &dynamic.Service{Weighted: wrr}
+
+	return name, services, nil
+}
+
+func loadHTTPServers(client Client, namespace string, fwd v1alpha1.RouteForwardTo) ([]dynamic.Server, error) {
+	service, portSpec, err := getServicePort(client, namespace, fwd)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := "http"
+	if portSpec.Port == 443 || strings.HasPrefix(portSpec.Name, "https") {
+		protocol = "https"
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return []dynamic.Server{{URL: fmt.Sprintf("%s://%s:%d", protocol, service.Spec.ExternalName, portSpec.Port)}}, nil
+	}
+
+	addresses, err := getEndpointAddresses(client, namespace, fwd.ServiceName, portSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []dynamic.Server
+	for _, address := range addresses {
+		servers = append(servers, dynamic.Server{URL: protocol + "://" + address})
+	}
+
+	return servers, nil
+}
+
+func getServicePort(client Client, namespace string, fwd v1alpha1.RouteForwardTo) (*corev1.Service, *corev1.ServicePort, error) {
+	service, exists, err := client.GetService(namespace, fwd.ServiceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("service not found %s/%s", namespace, fwd.ServiceName)
+	}
+
+	for _, p := range service.Spec.Ports {
+		if p.Port == int32(fwd.Port) {
+			port := p
+			return service, &port, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("service port %d not found for %s/%s", fwd.Port, namespace, fwd.ServiceName)
+}
+
+// getEndpointAddresses returns the host:port addresses of the endpoints backing the service port.
+func getEndpointAddresses(client Client, namespace, serviceName string, portSpec *corev1.ServicePort) ([]string, error) {
+	endpoints, exists, err := client.GetEndpoints(namespace, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, errors.New("endpoints not found")
+	}
+
+	if len(endpoints.Subsets) == 0 {
+		return nil, errors.New("subset not found")
+	}
+
+	var addresses []string
+	for _, subset := range endpoints.Subsets {
+		var port int32
+		for _, p := range subset.Ports {
+			if portSpec.Name == p.Name {
+				port = p.Port
+				break
+			}
+		}
+
+		if port == 0 {
+			return nil, errors.New("cannot define a port")
+		}
+
+		for _, addr := range subset.Addresses {
+			addresses = append(addresses, fmt.Sprintf("%s:%d", addr.IP, port))
+		}
+	}
+
+	return addresses, nil
+}
+
+func weight(value *int32) *int {
+	w := 1
+	if value != nil {
+		w = int(*value)
+	}
+	return &w
+}
@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/provider/kubernetes/gateway/networking/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// loadTLSRoute adds the TCP routers and services of a TLSRoute bound to a Gateway listener.
+// Nothing is added when one of the rules of the route is invalid.
+func loadTLSRoute(client Client, gateway *v1alpha1.Gateway, ep string, listener v1alpha1.Listener, passthrough bool, route *v1alpha1.TLSRoute, conf *dynamic.Configuration) error {
+	routers := make(map[string]*dynamic.TCPRouter)
+	services := make(map[string]*dynamic.TCPService)
+
+	for i, rule := range route.Spec.Rules {
+		if len(rule.ForwardTo) == 0 {
+			return fmt.Errorf("rule %d: missing forwardTo", i)
+		}
+
+		sniRule, err := hostSNIRule(listener.Hostname, rule.Matches)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		routerKey, err := makeRouterKey(sniRule, makeID(gateway.Namespace, gateway.Name)+"-"+ep)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+		routerName := makeID(route.Namespace, route.Name) + "-" + routerKey
+
+		serviceName, ruleServices, err := loadTCPServices(client, route.Namespace, routerName, rule.ForwardTo)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		for name, service := range ruleServices {
+			services[name] = service
+		}
+
+		routers[routerName] = &dynamic.TCPRouter{
+			Rule:        sniRule,
+			EntryPoints: []string{ep},
+			Service:     serviceName,
+			TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: passthrough},
+		}
+	}
+
+	for name, router := range routers {
+		conf.TCP.Routers[name] = router
+	}
+	for name, service := range services {
+		conf.TCP.Services[name] = service
+	}
+
+	return nil
+}
+
+// hostSNIRule returns the HostSNI matcher of the rule, restricted by the listener hostname.
+func hostSNIRule(listenerHostname *v1alpha1.Hostname, matches []v1alpha1.TLSRouteMatch) (string, error) {
+	var snis []v1alpha1.Hostname
+	for _, match := range matches {
+		snis = append(snis, match.SNIs...)
+	}
+
+	var hosts []string
+	for _, sni := range snis {
+		if strings.HasPrefix(string(sni), "*") {
+			return "", fmt.Errorf("wildcard SNI %q is not supported", sni)
+		}
+
+		if listenerHostname == nil || hostnameMatches(*listenerHostname, sni) {
+			hosts = append(hosts, "`"+string(sni)+"`")
+		}
+	}
+
+	switch {
+	case len(snis) > 0 && len(hosts) == 0:
+		return "", fmt.Errorf("no SNI matches the listener hostname %q", *listenerHostname)
+	case len(hosts) == 0 && listenerHostname != nil && !strings.HasPrefix(string(*listenerHostname), "*"):
+		hosts = []string{"`" + string(*listenerHostname) + "`"}
+	case len(hosts) == 0:
+		hosts = []string{"`*`"}
+	}
+
+	return "HostSNI(" + strings.Join(hosts, ", ") + ")", nil
+}
+
+// loadTCPServices returns the name of the service handling the connections, and the services to add to the configuration.
+func loadTCPServices(client Client, namespace, routerName string, forwardTo []v1alpha1.RouteForwardTo) (string, map[string]*dynamic.TCPService, error) {
+	services := make(map[string]*dynamic.TCPService)
+	wrr := &dynamic.TCPWeightedRoundRobin{}
+
+	for _, fwd := range forwardTo {
+		servers, err := loadTCPServers(client, namespace, fwd)
+		if err != nil {
+			return "", nil, err
+		}
+
+		lb := &dynamic.TCPServersLoadBalancer{}
+		lb.SetDefaults()
+		lb.Servers = servers
+
+		name := makeID(namespace, fmt.Sprintf("%s-%d", fwd.ServiceName, fwd.Port))
+		services[name] = &dynamic.TCPService{LoadBalancer: lb}
+
+		wrr.Services = append(wrr.Services, dynamic.TCPWRRService{Name: name, Weight: weight(fwd.Weight)})
+	}
+
+	if len(wrr.Services) == 1 {
+		return wrr.Services[0].Name, services, nil
+	}
+
+	name := routerName + "-wrr"
+	services[name] = &dynamic.TCPService{Weighted: wrr}
+
+	return name, services, nil
+}
+
+func loadTCPServers(client Client, namespace string, fwd v1alpha1.RouteForwardTo) ([]dynamic.TCPServer, error) {
+	service, portSpec, err := getServicePort(client, namespace, fwd)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return []dynamic.TCPServer{{Address: fmt.Sprintf("%s:%d", service.Spec.ExternalName, portSpec.Port)}}, nil
+	}
+
+	addresses, err := getEndpointAddresses(client, namespace, fwd.ServiceName, portSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []dynamic.TCPServer
+	for _, address := range addresses {
+		servers = append(servers, dynamic.TCPServer{Address: address})
+	}
+
+	return servers, nil
+}
@@ -0,0 +1,6 @@
+// +k8s:deepcopy-gen=package
+
+// Package v1alpha1 is the v1alpha1 version of the Kubernetes Gateway API.
+// It only holds the subset of the API understood by Traefik.
+// +groupName=networking.x-k8s.io
+package v1alpha1
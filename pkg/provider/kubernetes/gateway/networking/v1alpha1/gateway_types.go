@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProtocolType defines the application protocol accepted by a Listener.
+type ProtocolType string
+
+// Supported protocols.
+const (
+	HTTPProtocolType  ProtocolType = "HTTP"
+	HTTPSProtocolType ProtocolType = "HTTPS"
+	TCPProtocolType   ProtocolType = "TCP"
+	TLSProtocolType   ProtocolType = "TLS"
+)
+
+// TLSModeType defines how a Listener handles the TLS connections.
+type TLSModeType string
+
+// Supported TLS modes.
+const (
+	TLSModeTerminate   TLSModeType = "Terminate"
+	TLSModePassthrough TLSModeType = "Passthrough"
+)
+
+// RouteSelectType defines the namespaces from which routes may be bound to a Listener.
+type RouteSelectType string
+
+// Supported namespace selections.
+const (
+	RouteSelectAll      RouteSelectType = "All"
+	RouteSelectSame     RouteSelectType = "Same"
+	RouteSelectSelector RouteSelectType = "Selector"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway is a request for the binding of Listeners to the network.
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   GatewaySpec   `json:"spec"`
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewaySpec is the specification of a Gateway.
+type GatewaySpec struct {
+	GatewayClassName string     `json:"gatewayClassName"`
+	Listeners        []Listener `json:"listeners"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Listener describes a port, a protocol and the routes bound to them.
+type Listener struct {
+	Hostname *Hostname            `json:"hostname,omitempty"`
+	Port     PortNumber           `json:"port"`
+	Protocol ProtocolType         `json:"protocol"`
+	TLS      *GatewayTLSConfig    `json:"tls,omitempty"`
+	Routes   RouteBindingSelector `json:"routes"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayTLSConfig describes the TLS configuration of a Listener.
+type GatewayTLSConfig struct {
+	Mode *TLSModeType `json:"mode,omitempty"`
+	// CertificateRef is a reference to the Kubernetes Secret holding the certificate.
+	CertificateRef *LocalObjectReference `json:"certificateRef,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouteBindingSelector selects the routes bound to a Listener.
+type RouteBindingSelector struct {
+	Namespaces RouteNamespaces       `json:"namespaces,omitempty"`
+	Selector   *metav1.LabelSelector `json:"selector,omitempty"`
+	Kind       string                `json:"kind"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouteNamespaces selects the namespaces of the routes bound to a Listener.
+type RouteNamespaces struct {
+	// From defaults to "Same".
+	From     RouteSelectType       `json:"from,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayStatus is the status of a Gateway.
+type GatewayStatus struct {
+	Conditions []Condition      `json:"conditions,omitempty"`
+	Listeners  []ListenerStatus `json:"listeners,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ListenerStatus is the status of a Listener.
+type ListenerStatus struct {
+	Port       PortNumber   `json:"port"`
+	Protocol   ProtocolType `json:"protocol"`
+	Hostname   *Hostname    `json:"hostname,omitempty"`
+	Conditions []Condition  `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList is a list of Gateways.
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Gateway `json:"items"`
+}
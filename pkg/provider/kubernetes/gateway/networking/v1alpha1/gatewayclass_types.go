@@ -0,0 +1,43 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClass describes a class of Gateways, handled by a given controller.
+type GatewayClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   GatewayClassSpec   `json:"spec"`
+	Status GatewayClassStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayClassSpec is the specification of a GatewayClass.
+type GatewayClassSpec struct {
+	// Controller is the name of the controller managing the Gateways of this class.
+	Controller string `json:"controller"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayClassStatus is the status of a GatewayClass.
+type GatewayClassStatus struct {
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClassList is a list of GatewayClasses.
+type GatewayClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayClass `json:"items"`
+}
@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PathMatchType defines how a path is matched.
+type PathMatchType string
+
+// Supported path match types.
+const (
+	PathMatchExact             PathMatchType = "Exact"
+	PathMatchPrefix            PathMatchType = "Prefix"
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// HeaderMatchType defines how headers are matched.
+type HeaderMatchType string
+
+// Supported header match types.
+const (
+	HeaderMatchExact             HeaderMatchType = "Exact"
+	HeaderMatchRegularExpression HeaderMatchType = "RegularExpression"
+)
+
+// HTTPRouteFilterType identifies a filter.
+type HTTPRouteFilterType string
+
+// Supported filter types.
+const (
+	HTTPRouteFilterRequestHeaderModifier HTTPRouteFilterType = "RequestHeaderModifier"
+	HTTPRouteFilterRequestRedirect       HTTPRouteFilterType = "RequestRedirect"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute describes how HTTP requests are routed from a Gateway Listener to Kubernetes Services.
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   HTTPRouteSpec `json:"spec"`
+	Status RouteStatus   `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRouteSpec is the specification of an HTTPRoute.
+type HTTPRouteSpec struct {
+	Hostnames []Hostname      `json:"hostnames,omitempty"`
+	Rules     []HTTPRouteRule `json:"rules"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRouteRule defines the conditions and the actions applied to the matching requests.
+type HTTPRouteRule struct {
+	// Matches are ORed, the rule matches all the requests when empty.
+	Matches   []HTTPRouteMatch  `json:"matches,omitempty"`
+	Filters   []HTTPRouteFilter `json:"filters,omitempty"`
+	ForwardTo []RouteForwardTo  `json:"forwardTo,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRouteMatch defines the predicates a request must match, they are ANDed.
+type HTTPRouteMatch struct {
+	Path    *HTTPPathMatch   `json:"path,omitempty"`
+	Headers *HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPPathMatch describes how to match the request path.
+type HTTPPathMatch struct {
+	// Type defaults to "Prefix".
+	Type  PathMatchType `json:"type,omitempty"`
+	Value string        `json:"value"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPHeaderMatch describes how to match the request headers.
+type HTTPHeaderMatch struct {
+	// Type defaults to "Exact".
+	Type   HeaderMatchType   `json:"type,omitempty"`
+	Values map[string]string `json:"values"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRouteFilter defines a processing step applied to the matching requests.
+type HTTPRouteFilter struct {
+	Type                  HTTPRouteFilterType        `json:"type"`
+	RequestHeaderModifier *HTTPRequestHeaderFilter   `json:"requestHeaderModifier,omitempty"`
+	RequestRedirect       *HTTPRequestRedirectFilter `json:"requestRedirect,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRequestHeaderFilter modifies the request headers.
+type HTTPRequestHeaderFilter struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Add    map[string]string `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPRequestRedirectFilter redirects the request.
+type HTTPRequestRedirectFilter struct {
+	Protocol   *string     `json:"protocol,omitempty"`
+	Hostname   *string     `json:"hostname,omitempty"`
+	Port       *PortNumber `json:"port,omitempty"`
+	StatusCode *int        `json:"statusCode,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is a list of HTTPRoutes.
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []HTTPRoute `json:"items"`
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Hostname is a fully qualified domain name, optionally prefixed by a wildcard label.
+type Hostname string
+
+// PortNumber defines a network port.
+type PortNumber int32
+
+// +k8s:deepcopy-gen=true
+
+// Condition describes the state of a Gateway API resource at a given time.
+type Condition struct {
+	Type               string                 `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// LocalObjectReference identifies an object in the same namespace as the referrer.
+type LocalObjectReference struct {
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+	Name  string `json:"name"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouteForwardTo defines the Kubernetes Service a route forwards the matching requests to.
+type RouteForwardTo struct {
+	ServiceName string     `json:"serviceName"`
+	Port        PortNumber `json:"port"`
+	// Weight is the proportion of the requests forwarded to the service, defaults to 1.
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayReference identifies a Gateway.
+type GatewayReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouteGatewayStatus describes the status of a route with respect to one Gateway.
+type RouteGatewayStatus struct {
+	GatewayRef GatewayReference `json:"gatewayRef"`
+	Conditions []Condition      `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouteStatus defines the observed state of a route.
+type RouteStatus struct {
+	Gateways []RouteGatewayStatus `json:"gateways,omitempty"`
+}
+
+// Condition types and reasons reported by Traefik.
+const (
+	// GatewayClassConditionAdmitted indicates whether the GatewayClass is handled by the controller.
+	GatewayClassConditionAdmitted = "Admitted"
+
+	// GatewayConditionScheduled indicates whether the Gateway listeners are bound to entry points.
+	GatewayConditionScheduled = "Scheduled"
+	// GatewayConditionReady indicates whether the Gateway is ready to serve traffic.
+	GatewayConditionReady = "Ready"
+
+	// ListenerConditionDetached indicates that the Listener could not be bound to an entry point.
+	ListenerConditionDetached = "Detached"
+	// ListenerConditionResolvedRefs indicates whether the references of the Listener are resolved.
+	ListenerConditionResolvedRefs = "ResolvedRefs"
+	// ListenerConditionReady indicates whether the Listener is ready to serve traffic.
+	ListenerConditionReady = "Ready"
+
+	// RouteConditionAdmitted indicates whether the route has been admitted by a Gateway.
+	RouteConditionAdmitted = "Admitted"
+)
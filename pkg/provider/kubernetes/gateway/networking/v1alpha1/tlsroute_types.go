@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSRoute describes how TLS connections are routed, based on their SNI, from a Gateway Listener to Kubernetes Services.
+type TLSRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   TLSRouteSpec `json:"spec"`
+	Status RouteStatus  `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TLSRouteSpec is the specification of a TLSRoute.
+type TLSRouteSpec struct {
+	Rules []TLSRouteRule `json:"rules"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TLSRouteRule defines the conditions and the actions applied to the matching connections.
+type TLSRouteRule struct {
+	// Matches are ORed, the rule matches all the connections when empty.
+	Matches   []TLSRouteMatch  `json:"matches,omitempty"`
+	ForwardTo []RouteForwardTo `json:"forwardTo,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TLSRouteMatch defines the predicates a connection must match.
+type TLSRouteMatch struct {
+	SNIs []Hostname `json:"snis,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSRouteList is a list of TLSRoutes.
+type TLSRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TLSRoute `json:"items"`
+}
@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteMatch is the result of the evaluation of a route against a request or a connection.
+type RouteMatch struct {
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Priority int    `json:"priority"`
+	Matched  bool   `json:"matched"`
+	// Reasons are the matchers of the rule which rejected the request or the connection.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// routeDefinition holds what a route of a Router was built from.
+type routeDefinition struct {
+	name      string
+	rule      string
+	priority  int
+	buildTree treeBuilder
+}
+
+// Explain evaluates the routes of the router against the request, in the order they are matched,
+// and returns the result for each of them, with the matchers which rejected the request.
+// The request must have gone through the request decorator, as for the Host matcher.
+func (r *Router) Explain(req *http.Request) []RouteMatch {
+	var matches []RouteMatch
+
+	_ = r.Walk(func(route *mux.Route, _ *mux.Router, ancestors []*mux.Route) error {
+		if len(ancestors) > 0 {
+			return mux.SkipRouter
+		}
+
+		definition, ok := r.routes[route]
+		if !ok {
+			return mux.SkipRouter
+		}
+
+		match := RouteMatch{
+			Name:     definition.name,
+			Rule:     definition.rule,
+			Priority: definition.priority,
+			Matched:  route.Match(req, &mux.RouteMatch{}),
+		}
+		if !match.Matched {
+			match.Reasons = explainTree(definition.buildTree(), func(rule *tree) bool {
+				return r.matchTree(rule, req)
+			})
+		}
+
+		matches = append(matches, match)
+		return mux.SkipRouter
+	})
+
+	return matches
+}
+
+// ExplainTCP evaluates the TCP router rule against the connection,
+// and returns the matchers which rejected it, or nil if the connection matches the rule.
+func ExplainTCP(rule string, data ConnData) ([]string, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %v", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing rule %s: not a matcher expression", rule)
+	}
+
+	return explainTree(buildTree(), func(rule *tree) bool {
+		matcher, err := buildTCPMatcher(rule)
+		return err == nil && matcher(data)
+	}), nil
+}
+
+// explainTree returns the matchers of the tree rejecting the evaluated request or connection.
+// Both sides of a rejected "or" are reported, but only the rejecting ones of an "and".
+func explainTree(rule *tree, matches func(*tree) bool) []string {
+	if matches(rule) {
+		return nil
+	}
+
+	switch rule.matcher {
+	case "and", "or":
+		return append(explainTree(rule.ruleLeft, matches), explainTree(rule.ruleRight, matches)...)
+	default:
+		return []string{describeMatcher(rule) + " does not match"}
+	}
+}
+
+// matchTree tells whether the request matches the tree.
+func (r *Router) matchTree(rule *tree, req *http.Request) bool {
+	route := mux.NewRouter().SkipClean(true).NewRoute()
+	if err := r.addRuleOnRoute(route, rule); err != nil {
+		return false
+	}
+	return route.Match(req, &mux.RouteMatch{})
+}
+
+// describeMatcher returns the matcher as written in a rule, e.g. !Host(`foo.bar`).
+func describeMatcher(rule *tree) string {
+	values := make([]string, len(rule.value))
+	for i, value := range rule.value {
+		values[i] = "`" + value + "`"
+	}
+
+	description := fmt.Sprintf("%s(%s)", rule.matcher, strings.Join(values, ", "))
+	if rule.not {
+		return "!" + description
+	}
+	return description
+}
@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Explain(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	require.NoError(t, router.AddRoute("foo", "Host(`foo.bar`) && PathPrefix(`/api`)", 0, handler))
+	require.NoError(t, router.AddRoute("bar", "Host(`foo.bar`) && (Method(`POST`) || !Headers(`X-Foo`, `bar`))", 100, handler))
+	require.NoError(t, router.AddRoute("baz", "Host(`foo.bar`)", 0, handler))
+	router.SortRoutes()
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/admin", nil)
+	req.Header.Set("X-Foo", "bar")
+	requestdecorator.New(nil).ServeHTTP(nil, req, func(_ http.ResponseWriter, decorated *http.Request) {
+		req = decorated
+	})
+
+	expected := []RouteMatch{
+		{
+			Name:     "bar",
+			Rule:     "Host(`foo.bar`) && (Method(`POST`) || !Headers(`X-Foo`, `bar`))",
+			Priority: 100,
+			Reasons:  []string{"Method(`POST`) does not match", "!Headers(`X-Foo`, `bar`) does not match"},
+		},
+		{
+			Name:     "foo",
+			Rule:     "Host(`foo.bar`) && PathPrefix(`/api`)",
+			Priority: 37,
+			Reasons:  []string{"PathPrefix(`/api`) does not match"},
+		},
+		{
+			Name:     "baz",
+			Rule:     "Host(`foo.bar`)",
+			Priority: 15,
+			Matched:  true,
+		},
+	}
+
+	assert.Equal(t, expected, router.Explain(req))
+}
+
+func TestExplainTCP(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		data     ConnData
+		expected []string
+	}{
+		{
+			desc: "matching rule",
+			rule: "HostSNI(`foo.bar`)",
+			data: ConnData{ServerName: "foo.bar"},
+		},
+		{
+			desc:     "rejected and",
+			rule:     "HostSNI(`foo.bar`) && ClientIP(`10.0.0.0/8`)",
+			data:     ConnData{ServerName: "foo.bar", RemoteIP: "192.168.1.1"},
+			expected: []string{"ClientIP(`10.0.0.0/8`) does not match"},
+		},
+		{
+			desc:     "rejected or",
+			rule:     "HostSNI(`foo.bar`) || ALPN(`h2`)",
+			data:     ConnData{ServerName: "bar.foo"},
+			expected: []string{"HostSNI(`foo.bar`) does not match", "ALPN(`h2`) does not match"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			reasons, err := ExplainTCP(test.rule, test.data)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, reasons)
+		})
+	}
+}
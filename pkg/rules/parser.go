@@ -29,8 +29,7 @@ func ParseDomains(rule string) ([]string, error) {
 	return lower(parseDomain(buildTree())), nil
 }
 
-// ParseHostSNI extracts the HostSNIs declared in a rule
-// This is a first naive implementation used in TCP routing
+// ParseHostSNI extracts the HostSNIs declared in a TCP rule.
 func ParseHostSNI(rule string) ([]string, error) {
 	parser, err := newTCPParser()
 	if err != nil {
@@ -50,6 +49,26 @@ func ParseHostSNI(rule string) ([]string, error) {
 	return lower(parseDomain(buildTree())), nil
 }
 
+// ParseALPN extracts the ALPN protocols declared in a TCP rule.
+func ParseALPN(rule string) ([]string, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, errors.New("cannot parse")
+	}
+
+	return parseALPN(buildTree()), nil
+}
+
 func lower(slice []string) []string {
 	var lowerStrings []string
 	for _, value := range slice {
@@ -63,6 +82,23 @@ func parseDomain(tree *tree) []string {
 	case "and", "or":
 		return append(parseDomain(tree.ruleLeft), parseDomain(tree.ruleRight)...)
 	case "Host", "HostSNI":
+		if tree.not {
+			return nil
+		}
+		return tree.value
+	default:
+		return nil
+	}
+}
+
+func parseALPN(tree *tree) []string {
+	switch tree.matcher {
+	case "and", "or":
+		return append(parseALPN(tree.ruleLeft), parseALPN(tree.ruleRight)...)
+	case "ALPN":
+		if tree.not {
+			return nil
+		}
 		return tree.value
 	default:
 		return nil
@@ -89,29 +125,24 @@ func orFunc(left, right treeBuilder) treeBuilder {
 	}
 }
 
+func notFunc(elem treeBuilder) treeBuilder {
+	return func() *tree {
+		return elem().notTree()
+	}
+}
+
 func newParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
 	for matcherName := range funcs {
-		matcherName := matcherName
-		fn := func(value ...string) treeBuilder {
-			return func() *tree {
-				return &tree{
-					matcher: matcherName,
-					value:   value,
-				}
-			}
-		}
-		parserFuncs[matcherName] = fn
-		parserFuncs[strings.ToLower(matcherName)] = fn
-		parserFuncs[strings.ToUpper(matcherName)] = fn
-		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
+		addParserFunc(parserFuncs, matcherName)
 	}
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
 			AND: andFunc,
 			OR:  orFunc,
+			NOT: notFunc,
 		},
 		Functions: parserFuncs,
 	})
@@ -120,8 +151,22 @@ func newParser() (predicate.Parser, error) {
 func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
-	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
+	for matcherName := range tcpFuncs {
+		addParserFunc(parserFuncs, matcherName)
+	}
+
+	return predicate.NewParser(predicate.Def{
+		Operators: predicate.Operators{
+			AND: andFunc,
+			OR:  orFunc,
+			NOT: notFunc,
+		},
+		Functions: parserFuncs,
+	})
+}
+
+// addParserFunc registers the matcher, and its case variants, in the parser functions.
+func addParserFunc(parserFuncs map[string]interface{}, matcherName string) {
 	fn := func(value ...string) treeBuilder {
 		return func() *tree {
 			return &tree{
@@ -134,11 +179,4 @@ func newTCPParser() (predicate.Parser, error) {
 	parserFuncs[strings.ToLower(matcherName)] = fn
 	parserFuncs[strings.ToUpper(matcherName)] = fn
 	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
-
-	return predicate.NewParser(predicate.Def{
-		Operators: predicate.Operators{
-			OR: orFunc,
-		},
-		Functions: parserFuncs,
-	})
 }
@@ -2,9 +2,11 @@ package rules
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
+	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/gorilla/mux"
@@ -14,6 +16,7 @@ import (
 var funcs = map[string]func(*mux.Route, ...string) error{
 	"Host":          host,
 	"HostRegexp":    hostRegexp,
+	"ClientIP":      remoteClientIP,
 	"Path":          path,
 	"PathPrefix":    pathPrefix,
 	"Method":        methods,
@@ -26,6 +29,10 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 type Router struct {
 	*mux.Router
 	parser predicate.Parser
+	// routes holds the definitions of the routes added to the router, used to explain the routing.
+	routes map[*mux.Route]routeDefinition
+	// clientIPStrategy selects the IP matched by the ClientIP matcher.
+	clientIPStrategy ip.Strategy
 }
 
 // NewRouter returns a new router instance.
@@ -36,13 +43,33 @@ func NewRouter() (*Router, error) {
 	}
 
 	return &Router{
-		Router: mux.NewRouter().SkipClean(true),
-		parser: parser,
+		Router:           mux.NewRouter().SkipClean(true),
+		parser:           parser,
+		routes:           make(map[*mux.Route]routeDefinition),
+		clientIPStrategy: &ip.RemoteAddrStrategy{},
 	}, nil
 }
 
+// TrustForwardedHeaders makes the ClientIP matcher use the X-Forwarded-For header of the requests coming from trusted peers,
+// as the entry point does for the forwarded headers: from any peer when insecure, and from the given IPs otherwise.
+// It applies to the routes added afterwards.
+func (r *Router) TrustForwardedHeaders(insecure bool, trustedIPs []string) error {
+	strategy := &forwardedForStrategy{insecure: insecure}
+	if len(trustedIPs) > 0 {
+		checker, err := ip.NewChecker(trustedIPs)
+		if err != nil {
+			return err
+		}
+		strategy.checker = checker
+	}
+
+	r.clientIPStrategy = strategy
+	return nil
+}
+
 // AddRoute add a new route to the router.
-func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error {
+// The name identifies the route when explaining the routing of a request.
+func (r *Router) AddRoute(name, rule string, priority int, handler http.Handler) error {
 	parse, err := r.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %v", rule, err)
@@ -50,7 +77,7 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 
 	buildTree, ok := parse.(treeBuilder)
 	if !ok {
-		return fmt.Errorf("error while parsing rule %s", rule)
+		return fmt.Errorf("error while parsing rule %s: not a matcher expression", rule)
 	}
 
 	if priority == 0 {
@@ -58,16 +85,41 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 	}
 
 	route := r.NewRoute().Handler(handler).Priority(priority)
-	return addRuleOnRoute(route, buildTree())
+	err = r.addRuleOnRoute(route, buildTree())
+	if err != nil {
+		return err
+	}
+
+	r.routes[route] = routeDefinition{name: name, rule: rule, priority: priority, buildTree: buildTree}
+	return nil
 }
 
 type tree struct {
 	matcher   string
+	not       bool
 	value     []string
 	ruleLeft  *tree
 	ruleRight *tree
 }
 
+// notTree negates the tree, pushing the negation down to the matchers (De Morgan's laws).
+func (t *tree) notTree() *tree {
+	switch t.matcher {
+	case "and":
+		t.matcher = "or"
+		t.ruleLeft = t.ruleLeft.notTree()
+		t.ruleRight = t.ruleRight.notTree()
+	case "or":
+		t.matcher = "and"
+		t.ruleLeft = t.ruleLeft.notTree()
+		t.ruleRight = t.ruleRight.notTree()
+	default:
+		t.not = !t.not
+	}
+
+	return t
+}
+
 func path(route *mux.Route, paths ...string) error {
 	rt := route.Subrouter()
 
@@ -152,6 +204,71 @@ func hostRegexp(route *mux.Route, hosts ...string) error {
 	return nil
 }
 
+func remoteClientIP(route *mux.Route, clientIPs ...string) error {
+	return clientIP(route, &ip.RemoteAddrStrategy{}, clientIPs...)
+}
+
+func clientIP(route *mux.Route, strategy ip.Strategy, clientIPs ...string) error {
+	checker, err := ip.NewChecker(clientIPs)
+	if err != nil {
+		return fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %v", err)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		ok, err := checker.Contains(strategy.GetIP(req))
+		if err != nil {
+			log.FromContext(req.Context()).Warnf("\"ClientIP\" matcher: could not match remote address: %v", err)
+			return false
+		}
+		return ok
+	})
+
+	return nil
+}
+
+// forwardedForStrategy selects the client IP from the X-Forwarded-For header when the request comes from a trusted peer,
+// and the remote address otherwise.
+type forwardedForStrategy struct {
+	insecure bool
+	checker  *ip.Checker
+}
+
+// GetIP returns the first IP of the X-Forwarded-For header when all the peers are trusted,
+// and otherwise the last one which is not trusted, i.e. the IP of the client seen by the first trusted proxy.
+func (s *forwardedForStrategy) GetIP(req *http.Request) string {
+	remoteAddr := &ip.RemoteAddrStrategy{}
+	if !s.insecure && !s.isTrusted(req.RemoteAddr) {
+		return remoteAddr.GetIP(req)
+	}
+
+	var forwardedIPs []string
+	for _, value := range req.Header.Values("X-Forwarded-For") {
+		for _, forwardedIP := range strings.Split(value, ",") {
+			if forwardedIP = strings.TrimSpace(forwardedIP); forwardedIP != "" {
+				forwardedIPs = append(forwardedIPs, forwardedIP)
+			}
+		}
+	}
+
+	if len(forwardedIPs) == 0 {
+		return remoteAddr.GetIP(req)
+	}
+
+	if !s.insecure {
+		for i := len(forwardedIPs) - 1; i > 0; i-- {
+			if !s.isTrusted(forwardedIPs[i]) {
+				return forwardedIPs[i]
+			}
+		}
+	}
+
+	return forwardedIPs[0]
+}
+
+func (s *forwardedForStrategy) isTrusted(addr string) bool {
+	return s.checker != nil && s.checker.IsAuthorized(addr) == nil
+}
+
 func methods(route *mux.Route, methods ...string) error {
 	return route.Methods(methods...).GetError()
 }
@@ -175,58 +292,91 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
-func addRuleOnRouter(router *mux.Router, rule *tree) error {
+func (r *Router) addRuleOnRouter(router *mux.Router, rule *tree) error {
 	switch rule.matcher {
 	case "and":
 		route := router.NewRoute()
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := r.addRuleOnRoute(route, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return r.addRuleOnRoute(route, rule.ruleRight)
 	case "or":
-		err := addRuleOnRouter(router, rule.ruleLeft)
+		err := r.addRuleOnRouter(router, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(router, rule.ruleRight)
+		return r.addRuleOnRouter(router, rule.ruleRight)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](router.NewRoute(), rule.value...)
+		if rule.not {
+			return not(r.matcher(rule.matcher))(router.NewRoute(), rule.value...)
+		}
+		return r.matcher(rule.matcher)(router.NewRoute(), rule.value...)
 	}
 }
 
-func addRuleOnRoute(route *mux.Route, rule *tree) error {
+func (r *Router) addRuleOnRoute(route *mux.Route, rule *tree) error {
 	switch rule.matcher {
 	case "and":
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := r.addRuleOnRoute(route, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return r.addRuleOnRoute(route, rule.ruleRight)
 	case "or":
 		subRouter := route.Subrouter()
 
-		err := addRuleOnRouter(subRouter, rule.ruleLeft)
+		err := r.addRuleOnRouter(subRouter, rule.ruleLeft)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(subRouter, rule.ruleRight)
+		return r.addRuleOnRouter(subRouter, rule.ruleRight)
 	default:
 		err := checkRule(rule)
 		if err != nil {
 			return err
 		}
 
-		return funcs[rule.matcher](route, rule.value...)
+		if rule.not {
+			return not(r.matcher(rule.matcher))(route, rule.value...)
+		}
+		return r.matcher(rule.matcher)(route, rule.value...)
+	}
+}
+
+// matcher returns the function adding the matcher of the given name to a route.
+func (r *Router) matcher(name string) func(*mux.Route, ...string) error {
+	if name == "ClientIP" {
+		return func(route *mux.Route, clientIPs ...string) error {
+			return clientIP(route, r.clientIPStrategy, clientIPs...)
+		}
+	}
+	return funcs[name]
+}
+
+// not returns a matcher function matching the requests that are not matched by the given matcher function.
+func not(m func(*mux.Route, ...string) error) func(*mux.Route, ...string) error {
+	return func(r *mux.Route, v ...string) error {
+		router := mux.NewRouter().SkipClean(true)
+
+		err := m(router.NewRoute(), v...)
+		if err != nil {
+			return err
+		}
+
+		r.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			return !router.Match(req, &mux.RouteMatch{})
+		})
+		return nil
 	}
 }
 
@@ -239,6 +389,12 @@ func checkRule(rule *tree) error {
 		if len(v) == 0 {
 			return fmt.Errorf("empty args for matcher %s, %v", rule.matcher, rule.value)
 		}
+
+		if rule.matcher == "ClientIP" && net.ParseIP(v) == nil {
+			if _, _, err := net.ParseCIDR(v); err != nil {
+				return fmt.Errorf("invalid value %q for matcher %s: not an IP address or a CIDR", v, rule.matcher)
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/ip"
+)
+
+var tcpFuncs = map[string]func(...string) (TCPMatcher, error){
+	"HostSNI":  hostSNI,
+	"ClientIP": clientIPTCP,
+	"ALPN":     alpn,
+}
+
+// ConnData holds the data of a TCP connection, used to match the TCP router rules.
+type ConnData struct {
+	// ServerName is the SNI sent in the TLS ClientHello, if any.
+	ServerName string
+	// RemoteIP is the IP of the client.
+	RemoteIP string
+	// ALPNProtos are the application protocols offered in the TLS ClientHello, if any.
+	ALPNProtos []string
+}
+
+// TCPMatcher tells whether a TCP connection matches a TCP router rule.
+type TCPMatcher func(data ConnData) bool
+
+// NewTCPMatcher parses the given TCP router rule, and returns the matcher of the connections.
+func NewTCPMatcher(rule string) (TCPMatcher, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing rule %s: %v", rule, err)
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, fmt.Errorf("error while parsing rule %s: not a matcher expression", rule)
+	}
+
+	return buildTCPMatcher(buildTree())
+}
+
+func buildTCPMatcher(rule *tree) (TCPMatcher, error) {
+	switch rule.matcher {
+	case "and", "or":
+		left, err := buildTCPMatcher(rule.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := buildTCPMatcher(rule.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.matcher == "and" {
+			return func(data ConnData) bool {
+				return left(data) && right(data)
+			}, nil
+		}
+
+		return func(data ConnData) bool {
+			return left(data) || right(data)
+		}, nil
+	default:
+		err := checkRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		matcher, err := tcpFuncs[rule.matcher](rule.value...)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.not {
+			return func(data ConnData) bool {
+				return !matcher(data)
+			}, nil
+		}
+		return matcher, nil
+	}
+}
+
+func hostSNI(hosts ...string) (TCPMatcher, error) {
+	for _, host := range hosts {
+		if host == "*" {
+			return func(ConnData) bool { return true }, nil
+		}
+	}
+
+	return func(data ConnData) bool {
+		serverName := strings.TrimSuffix(data.ServerName, ".")
+		for _, host := range hosts {
+			if strings.EqualFold(serverName, strings.TrimSuffix(host, ".")) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func clientIPTCP(clientIPs ...string) (TCPMatcher, error) {
+	checker, err := ip.NewChecker(clientIPs)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %v", err)
+	}
+
+	return func(data ConnData) bool {
+		ok, err := checker.Contains(data.RemoteIP)
+		return err == nil && ok
+	}, nil
+}
+
+func alpn(protos ...string) (TCPMatcher, error) {
+	return func(data ConnData) bool {
+		for _, proto := range protos {
+			for _, clientProto := range data.ALPNProtos {
+				if proto == clientProto {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,184 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTCPMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]ConnData
+		notExpected   map[string]ConnData
+		expectedError bool
+	}{
+		{
+			desc:          "Empty rule",
+			expectedError: true,
+		},
+		{
+			desc:          "Unknown matcher",
+			rule:          "Host(`foo.bar`)",
+			expectedError: true,
+		},
+		{
+			desc:          "Empty HostSNI",
+			rule:          "HostSNI(``)",
+			expectedError: true,
+		},
+		{
+			desc:          "Invalid ClientIP",
+			rule:          "ClientIP(`foo`)",
+			expectedError: true,
+		},
+		{
+			desc: "HostSNI catch-all",
+			rule: "HostSNI(`*`)",
+			expected: map[string]ConnData{
+				"with SNI":    {ServerName: "foo.bar"},
+				"without SNI": {},
+			},
+		},
+		{
+			desc: "HostSNI",
+			rule: "HostSNI(`Foo.bar`, `bar.foo`)",
+			expected: map[string]ConnData{
+				"first host":       {ServerName: "foo.bar"},
+				"second host":      {ServerName: "bar.foo"},
+				"trailing dot":     {ServerName: "foo.bar."},
+				"case-insensitive": {ServerName: "FOO.bar"},
+			},
+			notExpected: map[string]ConnData{
+				"other host":  {ServerName: "foo.foo"},
+				"without SNI": {},
+			},
+		},
+		{
+			desc: "ClientIP",
+			rule: "ClientIP(`10.0.0.0/8`, `192.168.1.1`)",
+			expected: map[string]ConnData{
+				"in range": {RemoteIP: "10.1.2.3"},
+				"exact IP": {RemoteIP: "192.168.1.1"},
+			},
+			notExpected: map[string]ConnData{
+				"out of range": {RemoteIP: "192.168.1.2"},
+				"no IP":        {},
+			},
+		},
+		{
+			desc: "ALPN",
+			rule: "ALPN(`h2`)",
+			expected: map[string]ConnData{
+				"only protocol":   {ALPNProtos: []string{"h2"}},
+				"among protocols": {ALPNProtos: []string{"http/1.1", "h2"}},
+			},
+			notExpected: map[string]ConnData{
+				"other protocol": {ALPNProtos: []string{"acme-tls/1"}},
+				"no protocol":    {},
+			},
+		},
+		{
+			desc: "AND",
+			rule: "HostSNI(`foo.bar`) && ALPN(`h2`)",
+			expected: map[string]ConnData{
+				"both": {ServerName: "foo.bar", ALPNProtos: []string{"h2"}},
+			},
+			notExpected: map[string]ConnData{
+				"host only": {ServerName: "foo.bar", ALPNProtos: []string{"http/1.1"}},
+				"ALPN only": {ServerName: "bar.foo", ALPNProtos: []string{"h2"}},
+			},
+		},
+		{
+			desc: "OR",
+			rule: "HostSNI(`foo.bar`) || ClientIP(`10.0.0.1`)",
+			expected: map[string]ConnData{
+				"host":      {ServerName: "foo.bar"},
+				"client IP": {RemoteIP: "10.0.0.1"},
+			},
+			notExpected: map[string]ConnData{
+				"none": {ServerName: "bar.foo", RemoteIP: "10.0.0.2"},
+			},
+		},
+		{
+			desc: "NOT",
+			rule: "HostSNI(`*`) && !ClientIP(`10.0.0.0/8`)",
+			expected: map[string]ConnData{
+				"outside range": {RemoteIP: "192.168.1.1"},
+			},
+			notExpected: map[string]ConnData{
+				"in range": {RemoteIP: "10.0.0.1"},
+			},
+		},
+		{
+			desc: "NOT on a composition",
+			rule: "!(ALPN(`h2`) || ALPN(`http/1.1`))",
+			expected: map[string]ConnData{
+				"custom protocol": {ALPNProtos: []string{"foo"}},
+				"no protocol":     {},
+			},
+			notExpected: map[string]ConnData{
+				"h2":       {ALPNProtos: []string{"h2"}},
+				"http/1.1": {ALPNProtos: []string{"http/1.1"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewTCPMatcher(test.rule)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for name, data := range test.expected {
+				assert.True(t, matcher(data), name)
+			}
+
+			for name, data := range test.notExpected {
+				assert.False(t, matcher(data), name)
+			}
+		})
+	}
+}
+
+func TestParseALPN(t *testing.T) {
+	testCases := []struct {
+		rule     string
+		expected []string
+	}{
+		{
+			rule:     "HostSNI(`foo.bar`)",
+			expected: nil,
+		},
+		{
+			rule:     "HostSNI(`foo.bar`) && ALPN(`h2`, `acme-tls/1`)",
+			expected: []string{"h2", "acme-tls/1"},
+		},
+		{
+			rule:     "ALPN(`h2`) || !ALPN(`http/1.1`)",
+			expected: []string{"h2"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.rule, func(t *testing.T) {
+			t.Parallel()
+
+			protos, err := ParseALPN(test.rule)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, protos)
+		})
+	}
+}
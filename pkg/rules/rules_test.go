@@ -17,6 +17,7 @@ func Test_addRoute(t *testing.T) {
 		desc          string
 		rule          string
 		headers       map[string]string
+		remoteAddr    string
 		expected      map[string]int
 		expectedError bool
 	}{
@@ -407,6 +408,119 @@ func Test_addRoute(t *testing.T) {
 			rule:          `Host("tchouk") && Path("", "/titi")`,
 			expectedError: true,
 		},
+		{
+			desc: "Rule with not",
+			rule: "!Host(`localhost`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://example.com/":  http.StatusOK,
+			},
+		},
+		{
+			desc: "Rule with not on multiple values",
+			rule: "!Host(`localhost`, `example.com`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+				"http://example.com/":  http.StatusNotFound,
+				"http://traefik.io/":   http.StatusOK,
+			},
+		},
+		{
+			desc: "Rule with host AND not path",
+			rule: "Host(`localhost`) && !PathPrefix(`/admin`)",
+			expected: map[string]int{
+				"http://localhost/foo":       http.StatusOK,
+				"http://localhost/admin/foo": http.StatusNotFound,
+				"http://example.com/foo":     http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Rule with not (host OR path)",
+			rule: "!(Host(`localhost`) || PathPrefix(`/admin`))",
+			expected: map[string]int{
+				"http://localhost/foo":     http.StatusNotFound,
+				"http://example.com/admin": http.StatusNotFound,
+				"http://example.com/foo":   http.StatusOK,
+			},
+		},
+		{
+			desc: "Rule with not (host AND path)",
+			rule: "!(Host(`localhost`) && PathPrefix(`/admin`))",
+			expected: map[string]int{
+				"http://localhost/admin":   http.StatusNotFound,
+				"http://localhost/foo":     http.StatusOK,
+				"http://example.com/admin": http.StatusOK,
+			},
+		},
+		{
+			desc: "Rule with double not",
+			rule: "!!Host(`localhost`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+				"http://example.com/":  http.StatusNotFound,
+			},
+		},
+		{
+			desc:          "Rule with not without matcher",
+			rule:          "Host(`localhost`) && !",
+			expectedError: true,
+		},
+		{
+			desc:          "Rule with not on a matcher with error",
+			rule:          "!Path(`/{foo:[}`)",
+			expectedError: true,
+		},
+		{
+			desc:       "ClientIP with the remote address",
+			rule:       "ClientIP(`10.0.0.0/16`)",
+			remoteAddr: "10.0.4.2:1234",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:       "ClientIP with another remote address",
+			rule:       "ClientIP(`10.0.0.0/16`, `192.168.1.1`)",
+			remoteAddr: "10.1.4.2:1234",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP ignores the X-Forwarded-For header",
+			rule:       "ClientIP(`192.168.1.1`)",
+			remoteAddr: "10.0.4.2:1234",
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1, 10.0.4.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc:       "ClientIP with the remote address and an X-Forwarded-For header",
+			rule:       "ClientIP(`10.0.0.0/16`)",
+			remoteAddr: "10.0.4.2:1234",
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1, 10.0.4.1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:       "Rule with not ClientIP",
+			rule:       "Host(`localhost`) && !ClientIP(`10.0.0.0/16`)",
+			remoteAddr: "192.168.1.1:1234",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc:          "ClientIP with an invalid CIDR",
+			rule:          "ClientIP(`10.0.0.0/foo`)",
+			expectedError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -418,7 +532,7 @@ func Test_addRoute(t *testing.T) {
 			router, err := NewRouter()
 			require.NoError(t, err)
 
-			err = router.AddRoute(test.rule, 0, handler)
+			err = router.AddRoute("foo", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 			} else {
@@ -432,6 +546,7 @@ func Test_addRoute(t *testing.T) {
 					w := httptest.NewRecorder()
 
 					req := testhelpers.MustNewRequest(http.MethodGet, calledURL, nil)
+					req.RemoteAddr = test.remoteAddr
 					for key, value := range test.headers {
 						req.Header.Set(key, value)
 					}
@@ -444,6 +559,80 @@ func Test_addRoute(t *testing.T) {
 	}
 }
 
+func Test_addRoute_trustForwardedHeaders(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		insecure      bool
+		trustedIPs    []string
+		remoteAddr    string
+		xForwardedFor string
+		expected      int
+	}{
+		{
+			desc:          "trusted peer",
+			trustedIPs:    []string{"10.0.4.0/24"},
+			remoteAddr:    "10.0.4.2:1234",
+			xForwardedFor: "192.168.1.1",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:          "trusted peers chain",
+			trustedIPs:    []string{"10.0.4.0/24"},
+			remoteAddr:    "10.0.4.2:1234",
+			xForwardedFor: "172.16.0.1, 192.168.1.1, 10.0.4.3",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:          "untrusted peer",
+			trustedIPs:    []string{"10.0.4.0/24"},
+			remoteAddr:    "10.1.4.2:1234",
+			xForwardedFor: "192.168.1.1",
+			expected:      http.StatusNotFound,
+		},
+		{
+			desc:          "insecure",
+			insecure:      true,
+			remoteAddr:    "10.1.4.2:1234",
+			xForwardedFor: "192.168.1.1, 10.0.4.3",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:       "trusted peer without forwarded header",
+			trustedIPs: []string{"10.0.4.0/24"},
+			remoteAddr: "192.168.1.1:1234",
+			expected:   http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.TrustForwardedHeaders(test.insecure, test.trustedIPs)
+			require.NoError(t, err)
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			err = router.AddRoute("foo", "ClientIP(`192.168.1.1`)", 0, handler)
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/foo", nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
+
 func Test_addRoutePriority(t *testing.T) {
 	type Case struct {
 		xFrom    string
@@ -572,7 +761,7 @@ func Test_addRoutePriority(t *testing.T) {
 					w.Header().Set("X-From", route.xFrom)
 				})
 
-				err := router.AddRoute(route.rule, route.priority, handler)
+				err := router.AddRoute(route.xFrom, route.rule, route.priority, handler)
 				require.NoError(t, err, route.rule)
 			}
 
@@ -698,6 +887,12 @@ func TestParseDomains(t *testing.T) {
 			domain:        []string{"foo.bar"},
 			errorExpected: false,
 		},
+		{
+			description:   "Negated host rule",
+			expression:    "!Host(`foo.bar`) && Host(`test.bar`)",
+			domain:        []string{"test.bar"},
+			errorExpected: false,
+		},
 		{
 			description:   "Host rule with no domain",
 			expression:    "Host() && Path(`/test`)",
@@ -10,9 +10,10 @@ import (
 func mergeConfiguration(configurations dynamic.Configurations) dynamic.Configuration {
 	conf := dynamic.Configuration{
 		HTTP: &dynamic.HTTPConfiguration{
-			Routers:     make(map[string]*dynamic.Router),
-			Middlewares: make(map[string]*dynamic.Middleware),
-			Services:    make(map[string]*dynamic.Service),
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
 		},
 		TCP: &dynamic.TCPConfiguration{
 			Routers:  make(map[string]*dynamic.TCPRouter),
@@ -36,6 +37,9 @@ func mergeConfiguration(configurations dynamic.Configurations) dynamic.Configura
 			for serviceName, service := range configuration.HTTP.Services {
 				conf.HTTP.Services[internal.MakeQualifiedName(provider, serviceName)] = service
 			}
+			for transportName, transport := range configuration.HTTP.ServersTransports {
+				conf.HTTP.ServersTransports[internal.MakeQualifiedName(provider, transportName)] = transport
+			}
 		}
 
 		if configuration.TCP != nil {
@@ -18,9 +18,10 @@ func TestAggregator(t *testing.T) {
 			desc:  "Nil returns an empty configuration",
 			given: nil,
 			expected: &dynamic.HTTPConfiguration{
-				Routers:     make(map[string]*dynamic.Router),
-				Middlewares: make(map[string]*dynamic.Middleware),
-				Services:    make(map[string]*dynamic.Service),
+				Routers:           make(map[string]*dynamic.Router),
+				Middlewares:       make(map[string]*dynamic.Middleware),
+				Services:          make(map[string]*dynamic.Service),
+				ServersTransports: make(map[string]*dynamic.ServersTransport),
 			},
 		},
 		{
@@ -37,6 +38,9 @@ func TestAggregator(t *testing.T) {
 						Services: map[string]*dynamic.Service{
 							"service-1": {},
 						},
+						ServersTransports: map[string]*dynamic.ServersTransport{
+							"transport-1": {},
+						},
 					},
 				},
 			},
@@ -50,6 +54,9 @@ func TestAggregator(t *testing.T) {
 				Services: map[string]*dynamic.Service{
 					"service-1@provider-1": {},
 				},
+				ServersTransports: map[string]*dynamic.ServersTransport{
+					"transport-1@provider-1": {},
+				},
 			},
 		},
 		{
@@ -66,6 +73,9 @@ func TestAggregator(t *testing.T) {
 						Services: map[string]*dynamic.Service{
 							"service-1": {},
 						},
+						ServersTransports: map[string]*dynamic.ServersTransport{
+							"transport-1": {},
+						},
 					},
 				},
 				"provider-2": &dynamic.Configuration{
@@ -79,6 +89,9 @@ func TestAggregator(t *testing.T) {
 						Services: map[string]*dynamic.Service{
 							"service-1": {},
 						},
+						ServersTransports: map[string]*dynamic.ServersTransport{
+							"transport-1": {},
+						},
 					},
 				},
 			},
@@ -95,6 +108,10 @@ func TestAggregator(t *testing.T) {
 					"service-1@provider-1": {},
 					"service-1@provider-2": {},
 				},
+				ServersTransports: map[string]*dynamic.ServersTransport{
+					"transport-1@provider-1": {},
+					"transport-1@provider-2": {},
+				},
 			},
 		},
 	}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+)
+
+// Dependencies records the elements of the dynamic configuration a handler is built from,
+// along with the registrations made on the runtime configuration while building it,
+// so that the handler can be reused by the reloads where these elements do not change.
+// A nil Dependencies records nothing.
+type Dependencies struct {
+	lock sync.Mutex
+
+	services          map[string]struct{}
+	middlewares       map[string]struct{}
+	serversTransports map[string]struct{}
+	// internal is set when the handler uses an internal service, built from the runtime configuration itself.
+	internal bool
+
+	registrations []func(conf *runtime.Configuration)
+	balancers     map[string][]healthcheck.BalancerHandler
+}
+
+// NewDependencies creates a new Dependencies.
+func NewDependencies() *Dependencies {
+	return &Dependencies{
+		services:          make(map[string]struct{}),
+		middlewares:       make(map[string]struct{}),
+		serversTransports: make(map[string]struct{}),
+		balancers:         make(map[string][]healthcheck.BalancerHandler),
+	}
+}
+
+// AddDependenciesInContext adds the Dependencies recording what is built with the context.
+func AddDependenciesInContext(ctx context.Context, deps *Dependencies) context.Context {
+	return context.WithValue(ctx, dependenciesKey, deps)
+}
+
+// GetDependencies returns the Dependencies recording what is built with the context, or nil.
+func GetDependencies(ctx context.Context) *Dependencies {
+	deps, _ := ctx.Value(dependenciesKey).(*Dependencies)
+	return deps
+}
+
+// AddService records that the handler uses the given service.
+func (d *Dependencies) AddService(name string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.services[name] = struct{}{}
+}
+
+// AddMiddleware records that the handler uses the given middleware.
+func (d *Dependencies) AddMiddleware(name string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.middlewares[name] = struct{}{}
+}
+
+// AddServersTransport records that the handler uses the given servers transport.
+func (d *Dependencies) AddServersTransport(name string) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.serversTransports[name] = struct{}{}
+}
+
+// SetInternal records that the handler uses an internal service, and can therefore not be reused.
+func (d *Dependencies) SetInternal() {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.internal = true
+}
+
+// AddRegistration records a registration made on the runtime configuration while building the handler.
+func (d *Dependencies) AddRegistration(registration func(conf *runtime.Configuration)) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.registrations = append(d.registrations, registration)
+}
+
+// AddBalancer records a load-balancer built for the handler, to be health checked.
+func (d *Dependencies) AddBalancer(serviceName string, balancer healthcheck.BalancerHandler) {
+	if d == nil {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.balancers[serviceName] = append(d.balancers[serviceName], balancer)
+}
+
+// Services returns the names of the services used by the handler.
+func (d *Dependencies) Services() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return keys(d.services)
+}
+
+// Middlewares returns the names of the middlewares used by the handler.
+func (d *Dependencies) Middlewares() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return keys(d.middlewares)
+}
+
+// ServersTransports returns the names of the servers transports used by the handler.
+func (d *Dependencies) ServersTransports() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return keys(d.serversTransports)
+}
+
+// Internal returns whether the handler uses an internal service.
+func (d *Dependencies) Internal() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.internal
+}
+
+// Balancers returns the load-balancers built for the handler, by service name.
+func (d *Dependencies) Balancers() map[string][]healthcheck.BalancerHandler {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	balancers := make(map[string][]healthcheck.BalancerHandler, len(d.balancers))
+	for name, serviceBalancers := range d.balancers {
+		balancers[name] = append([]healthcheck.BalancerHandler(nil), serviceBalancers...)
+	}
+	return balancers
+}
+
+// Replay replays the registrations made while building the handler on the given runtime configuration.
+func (d *Dependencies) Replay(conf *runtime.Configuration) {
+	d.lock.Lock()
+	registrations := append([]func(conf *runtime.Configuration){}, d.registrations...)
+	d.lock.Unlock()
+
+	for _, registration := range registrations {
+		registration(conf)
+	}
+}
+
+func keys(set map[string]struct{}) []string {
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	return names
+}
@@ -11,6 +11,7 @@ type contextKey int
 
 const (
 	providerKey contextKey = iota
+	dependenciesKey
 )
 
 // AddProviderInContext Adds the provider name in the context
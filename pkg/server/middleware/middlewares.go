@@ -11,12 +11,15 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/containous/traefik/v2/pkg/middlewares/auth"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodyrewrite"
 	"github.com/containous/traefik/v2/pkg/middlewares/buffering"
+	"github.com/containous/traefik/v2/pkg/middlewares/cache"
 	"github.com/containous/traefik/v2/pkg/middlewares/chain"
 	"github.com/containous/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/containous/traefik/v2/pkg/middlewares/compress"
 	"github.com/containous/traefik/v2/pkg/middlewares/customerrors"
 	"github.com/containous/traefik/v2/pkg/middlewares/collaborForward"
+	"github.com/containous/traefik/v2/pkg/middlewares/grpcweb"
 	"github.com/containous/traefik/v2/pkg/middlewares/headers"
 	"github.com/containous/traefik/v2/pkg/middlewares/huaweilogin"
 	"github.com/containous/traefik/v2/pkg/middlewares/inflightreq"
@@ -43,6 +46,7 @@ const (
 type Builder struct {
 	configs        map[string]*runtime.MiddlewareInfo
 	serviceBuilder serviceBuilder
+	cacheManager   *cache.Manager
 }
 
 type serviceBuilder interface {
@@ -50,8 +54,8 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, cacheManager *cache.Manager) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, cacheManager: cacheManager}
 }
 
 // BuildChain creates a middleware chain
@@ -62,6 +66,7 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 
 		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 			constructorContext := internal.AddProviderInContext(ctx, middlewareName)
+			internal.GetDependencies(ctx).AddMiddleware(middlewareName)
 			if midInf, ok := b.configs[middlewareName]; !ok || midInf.Middleware == nil {
 				return nil, fmt.Errorf("middleware %q does not exist", middlewareName)
 			}
@@ -90,6 +95,20 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 	return &chain
 }
 
+// onMiddlewareInfo calls fn with the runtime information of the middleware,
+// and records the call to be replayed by the reloads reusing the handler being built.
+func (b *Builder) onMiddlewareInfo(ctx context.Context, middlewareName string, fn func(info *runtime.MiddlewareInfo)) {
+	if info, ok := b.configs[middlewareName]; ok {
+		fn(info)
+	}
+
+	internal.GetDependencies(ctx).AddRegistration(func(conf *runtime.Configuration) {
+		if info, ok := conf.Middlewares[middlewareName]; ok {
+			fn(info)
+		}
+	})
+}
+
 func checkRecursion(ctx context.Context, middlewareName string) (context.Context, error) {
 	currentStack, ok := ctx.Value(middlewareStackKey).([]string)
 	if !ok {
@@ -139,6 +158,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// BodyRewrite
+	if config.BodyRewrite != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return bodyrewrite.New(ctx, next, *config.BodyRewrite, middlewareName)
+		}
+	}
+
 	// Buffering
 	if config.Buffering != nil {
 		if middleware != nil {
@@ -149,6 +178,23 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			handler, err := cache.New(ctx, next, *config.Cache, middlewareName, b.cacheManager)
+			if err != nil {
+				return nil, err
+			}
+			b.onMiddlewareInfo(ctx, middlewareName, func(info *runtime.MiddlewareInfo) {
+				info.AddCachePurger(handler.Purge)
+			})
+			return handler, nil
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -185,6 +231,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// GrpcWeb
+	if config.GrpcWeb != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return grpcweb.New(ctx, next, *config.GrpcWeb, middlewareName)
+		}
+	}
+
 	// CustomErrors
 	if config.Errors != nil {
 		if middleware != nil {
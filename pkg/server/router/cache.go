@@ -0,0 +1,173 @@
+package router
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/server/internal"
+	"github.com/mitchellh/hashstructure"
+)
+
+// HandlerCache keeps the handlers of the routers across the configuration reloads,
+// so that a reload only rebuilds the routers whose configuration, or the configuration of the elements they use, changed.
+type HandlerCache struct {
+	lock     sync.Mutex
+	conf     *dynamic.HTTPConfiguration
+	handlers map[string]*cachedHandler
+}
+
+type cachedHandler struct {
+	hash    uint64
+	handler http.Handler
+	deps    *internal.Dependencies
+}
+
+// handlerKey holds the configuration a router handler is built from.
+type handlerKey struct {
+	Router            *dynamic.Router
+	Services          map[string]*dynamic.Service
+	Middlewares       map[string]*dynamic.Middleware
+	ServersTransports map[string]*dynamic.ServersTransport
+	// Files holds the content of the files read by the middlewares and the servers transports,
+	// as the handlers must be rebuilt when they change.
+	Files map[string][]byte
+}
+
+// NewHandlerCache creates a new HandlerCache.
+func NewHandlerCache() *HandlerCache {
+	return &HandlerCache{
+		conf:     &dynamic.HTTPConfiguration{},
+		handlers: make(map[string]*cachedHandler),
+	}
+}
+
+// Update sets the configuration the handlers of the following reload are built from,
+// and evicts the handlers of the routers that do not exist anymore.
+// The given configuration must not be mutated while the handlers are built.
+func (c *HandlerCache) Update(conf *dynamic.HTTPConfiguration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if conf == nil {
+		conf = &dynamic.HTTPConfiguration{}
+	}
+	c.conf = conf
+
+	for routerName := range c.handlers {
+		if _, ok := conf.Routers[routerName]; !ok {
+			delete(c.handlers, routerName)
+		}
+	}
+}
+
+// get returns the handler of the router, along with the dependencies it was built with,
+// if none of these dependencies changed since it was built.
+func (c *HandlerCache) get(routerName string) (http.Handler, *internal.Dependencies, bool) {
+	if c == nil {
+		return nil, nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.handlers[routerName]
+	if !ok {
+		return nil, nil, false
+	}
+
+	hash, err := c.hash(routerName, cached.deps)
+	if err != nil || hash != cached.hash {
+		delete(c.handlers, routerName)
+		return nil, nil, false
+	}
+
+	return cached.handler, cached.deps, true
+}
+
+// set keeps the handler of the router, built with the given dependencies.
+func (c *HandlerCache) set(routerName string, handler http.Handler, deps *internal.Dependencies) {
+	if c == nil || deps.Internal() {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hash, err := c.hash(routerName, deps)
+	if err != nil {
+		return
+	}
+
+	c.handlers[routerName] = &cachedHandler{hash: hash, handler: handler, deps: deps}
+}
+
+func (c *HandlerCache) hash(routerName string, deps *internal.Dependencies) (uint64, error) {
+	key := handlerKey{
+		Router:            c.conf.Routers[routerName],
+		Services:          make(map[string]*dynamic.Service),
+		Middlewares:       make(map[string]*dynamic.Middleware),
+		ServersTransports: make(map[string]*dynamic.ServersTransport),
+		Files:             make(map[string][]byte),
+	}
+
+	var files []string
+
+	// The elements which do not exist are hashed too, as nil values.
+	for _, name := range deps.Services() {
+		key.Services[name] = c.conf.Services[name]
+	}
+	for _, name := range deps.Middlewares() {
+		key.Middlewares[name] = c.conf.Middlewares[name]
+		files = append(files, middlewareFiles(key.Middlewares[name])...)
+	}
+	for _, name := range deps.ServersTransports() {
+		key.ServersTransports[name] = c.conf.ServersTransports[name]
+		files = append(files, serversTransportFiles(key.ServersTransports[name])...)
+	}
+
+	// The values which are not paths, but contents, are already hashed with the configuration.
+	for _, file := range files {
+		if content, err := ioutil.ReadFile(file); err == nil {
+			key.Files[file] = content
+		}
+	}
+
+	return hashstructure.Hash(key, nil)
+}
+
+// middlewareFiles returns the values of the middleware configuration which can be paths of files read by the middleware.
+func middlewareFiles(middleware *dynamic.Middleware) []string {
+	if middleware == nil {
+		return nil
+	}
+
+	var files []string
+	if middleware.BasicAuth != nil && middleware.BasicAuth.UsersFile != "" {
+		files = append(files, middleware.BasicAuth.UsersFile)
+	}
+	if middleware.DigestAuth != nil && middleware.DigestAuth.UsersFile != "" {
+		files = append(files, middleware.DigestAuth.UsersFile)
+	}
+	if middleware.ForwardAuth != nil && middleware.ForwardAuth.TLS != nil {
+		files = append(files, middleware.ForwardAuth.TLS.CA, middleware.ForwardAuth.TLS.Cert, middleware.ForwardAuth.TLS.Key)
+	}
+	return files
+}
+
+// serversTransportFiles returns the values of the servers transport configuration which can be paths of files read by the transport.
+func serversTransportFiles(serversTransport *dynamic.ServersTransport) []string {
+	if serversTransport == nil {
+		return nil
+	}
+
+	var files []string
+	for _, rootCA := range serversTransport.RootCAs {
+		files = append(files, rootCA.String())
+	}
+	for _, certificate := range serversTransport.Certificates {
+		files = append(files, certificate.CertFile.String(), certificate.KeyFile.String())
+	}
+	return files
+}
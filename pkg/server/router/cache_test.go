@@ -0,0 +1,166 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/responsemodifiers"
+	"github.com/containous/traefik/v2/pkg/server/middleware"
+	"github.com/containous/traefik/v2/pkg/server/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cache := NewHandlerCache()
+
+	build := func(conf *dynamic.HTTPConfiguration) (*Manager, *runtime.Configuration) {
+		cache.Update(conf)
+
+		// Building the handlers mutates the configuration, which the cache must not see.
+		rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: conf.DeepCopy()})
+		serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
+		middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+		responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
+		routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+		routerManager.SetCache(cache)
+
+		_ = routerManager.BuildHandlers(context.Background(), []string{"web"}, false)
+
+		return routerManager, rtConf
+	}
+
+	newConf := func() *dynamic.HTTPConfiguration {
+		return &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Middlewares: []string{"prefix"},
+					Rule:        "Host(`foo.bar`)",
+				},
+				"bar@file": {
+					EntryPoints: []string{"web"},
+					Service:     "bar",
+					Rule:        "Host(`bar.foo`)",
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@file": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: server.URL}},
+					},
+				},
+				"bar@file": {
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{{Name: "foo"}},
+					},
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"prefix@file": {AddPrefix: &dynamic.AddPrefix{Prefix: "/foo"}},
+			},
+		}
+	}
+
+	previous, _ := build(newConf())
+
+	// Nothing changed: the handlers are reused, and the registrations made while building them are replayed.
+	current, rtConf := build(newConf())
+
+	assert.True(t, previous.routerHandlers["foo@file"] == current.routerHandlers["foo@file"])
+	assert.True(t, previous.routerHandlers["bar@file"] == current.routerHandlers["bar@file"])
+	assert.Equal(t, []string{"prefix@file"}, rtConf.Routers["foo@file"].Middlewares)
+	assert.Equal(t, map[string]string{server.URL: "UP"}, rtConf.Services["foo@file"].GetAllStatus())
+
+	// The middleware of a router changed: only this router is rebuilt.
+	conf := newConf()
+	conf.Middlewares["prefix@file"].AddPrefix.Prefix = "/bar"
+	previous = current
+	current, _ = build(conf)
+
+	assert.False(t, previous.routerHandlers["foo@file"] == current.routerHandlers["foo@file"])
+	assert.True(t, previous.routerHandlers["bar@file"] == current.routerHandlers["bar@file"])
+
+	// A service used by both routers, directly or through a weighted service, changed.
+	conf = newConf()
+	conf.Middlewares["prefix@file"].AddPrefix.Prefix = "/bar"
+	conf.Services["foo@file"].LoadBalancer.Servers = append(conf.Services["foo@file"].LoadBalancer.Servers, dynamic.Server{URL: "http://127.0.0.1:8080"})
+	previous = current
+	current, _ = build(conf)
+
+	assert.False(t, previous.routerHandlers["foo@file"] == current.routerHandlers["foo@file"])
+	assert.False(t, previous.routerHandlers["bar@file"] == current.routerHandlers["bar@file"])
+
+	// The handlers of the removed routers are evicted.
+	delete(conf.Routers, "bar@file")
+	_, _ = build(conf)
+
+	require.Contains(t, cache.handlers, "foo@file")
+	assert.NotContains(t, cache.handlers, "bar@file")
+}
+
+func TestHandlerCache_files(t *testing.T) {
+	usersFile := filepath.Join(t.TempDir(), "users")
+	require.NoError(t, os.WriteFile(usersFile, []byte("test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"), 0o600))
+
+	cache := NewHandlerCache()
+
+	build := func() http.Handler {
+		conf := &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints: []string{"web"},
+					Service:     "foo",
+					Middlewares: []string{"auth"},
+					Rule:        "Host(`foo.bar`)",
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@file": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+					},
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"auth@file": {BasicAuth: &dynamic.BasicAuth{UsersFile: usersFile}},
+			},
+		}
+		cache.Update(conf)
+
+		rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: conf.DeepCopy()})
+		serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
+		middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+		responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
+		routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+		routerManager.SetCache(cache)
+
+		_ = routerManager.BuildHandlers(context.Background(), []string{"web"}, false)
+
+		return routerManager.routerHandlers["foo@file"]
+	}
+
+	previous := build()
+	require.NotNil(t, previous)
+
+	// Nothing changed: the handler is reused.
+	current := build()
+	assert.True(t, previous == current)
+
+	// The users file changed: the handler is rebuilt.
+	require.NoError(t, os.WriteFile(usersFile, []byte("test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0"), 0o600))
+
+	previous = current
+	current = build()
+	assert.False(t, previous == current)
+}
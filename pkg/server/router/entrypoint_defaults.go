@@ -0,0 +1,217 @@
+package router
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"reflect"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/redirect"
+)
+
+const redirectMiddlewareName = "traefik-internal-redirect"
+
+// EntryPointDefaults holds the HTTP defaults of the entry points:
+// the TLS configuration and the middlewares applied to their routers, and their redirection to another entry point.
+type EntryPointDefaults struct {
+	configs map[string]*static.HTTPConfig
+	// redirectPorts holds, for each entry point redirecting to another one, the port of the targeted entry point.
+	redirectPorts map[string]string
+}
+
+// NewEntryPointDefaults creates the defaults of the given entry points.
+func NewEntryPointDefaults(entryPoints static.EntryPoints) *EntryPointDefaults {
+	defaults := &EntryPointDefaults{
+		configs:       make(map[string]*static.HTTPConfig),
+		redirectPorts: make(map[string]string),
+	}
+
+	for name, entryPoint := range entryPoints {
+		if entryPoint == nil || entryPoint.HTTP == nil {
+			continue
+		}
+		defaults.configs[name] = entryPoint.HTTP
+
+		if entryPoint.HTTP.Redirections == nil || entryPoint.HTTP.Redirections.EntryPoint == nil {
+			continue
+		}
+
+		var port string
+		if target, ok := entryPoints[entryPoint.HTTP.Redirections.EntryPoint.To]; ok {
+			if network, address := target.GetAddress(); network == "tcp" {
+				_, port, _ = net.SplitHostPort(address)
+			}
+		}
+		defaults.redirectPorts[name] = port
+	}
+
+	return defaults
+}
+
+// entryPointModel holds what is applied to a router on an entry point.
+type entryPointModel struct {
+	tls         *dynamic.RouterTLSConfig
+	middlewares []string
+}
+
+// model returns what is applied to the given router on the entry point, or nil if the router is left as-is.
+func (d *EntryPointDefaults) model(entryPointName string, router *dynamic.Router) *entryPointModel {
+	config, ok := d.configs[entryPointName]
+	if !ok {
+		return nil
+	}
+
+	model := &entryPointModel{middlewares: config.Middlewares}
+	if router.TLS == nil && config.TLS != nil {
+		model.tls = &dynamic.RouterTLSConfig{
+			Options:      config.TLS.Options,
+			CertResolver: config.TLS.CertResolver,
+		}
+		for _, domain := range config.TLS.Domains {
+			model.tls.Domains = append(model.tls.Domains, *domain.DeepCopy())
+		}
+	}
+
+	if model.tls == nil && len(model.middlewares) == 0 {
+		return nil
+	}
+
+	return model
+}
+
+// Apply returns the given routers with the defaults of their entry points applied.
+// A router without TLS configuration gets the default one of its entry points, and the default middlewares are prepended to its own.
+// When the entry points of a router have different defaults, the router is split in a router per entry point with defaults,
+// named after the entry point, and the original router is kept on the entry points without defaults, if any.
+// The routers without entry points are linked to the given default ones, except the ones redirecting to another entry point,
+// where they would be served instead of the redirection.
+// The given routers are not modified.
+func (d *EntryPointDefaults) Apply(routers map[string]*dynamic.Router, defaultEntryPoints []string) map[string]*dynamic.Router {
+	result := make(map[string]*dynamic.Router, len(routers))
+
+	var notRedirecting []string
+	for _, entryPointName := range defaultEntryPoints {
+		if _, ok := d.redirectPorts[entryPointName]; !ok {
+			notRedirecting = append(notRedirecting, entryPointName)
+		}
+	}
+
+	for routerName, router := range routers {
+		entryPoints := router.EntryPoints
+		if len(entryPoints) == 0 {
+			entryPoints = defaultEntryPoints
+			if len(notRedirecting) > 0 && len(notRedirecting) < len(defaultEntryPoints) {
+				entryPoints = notRedirecting
+				router = applyModel(router, entryPoints, nil)
+			}
+		}
+
+		var noDefaults []string
+		models := make(map[string]*entryPointModel)
+		var withDefaults []string
+		for _, entryPointName := range entryPoints {
+			model := d.model(entryPointName, router)
+			if model == nil {
+				noDefaults = append(noDefaults, entryPointName)
+				continue
+			}
+			models[entryPointName] = model
+			withDefaults = append(withDefaults, entryPointName)
+		}
+
+		if len(withDefaults) == 0 {
+			result[routerName] = router
+			continue
+		}
+
+		if len(noDefaults) == 0 && sameModels(models) {
+			result[routerName] = applyModel(router, entryPoints, models[withDefaults[0]])
+			continue
+		}
+
+		if len(noDefaults) > 0 {
+			result[routerName] = applyModel(router, noDefaults, nil)
+		}
+		for _, entryPointName := range withDefaults {
+			result[entryPointName+"-"+routerName] = applyModel(router, []string{entryPointName}, models[entryPointName])
+		}
+	}
+
+	return result
+}
+
+func sameModels(models map[string]*entryPointModel) bool {
+	var first *entryPointModel
+	for _, model := range models {
+		if first == nil {
+			first = model
+			continue
+		}
+		if !reflect.DeepEqual(first, model) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyModel returns a copy of the router on the given entry points, with the model applied.
+func applyModel(router *dynamic.Router, entryPoints []string, model *entryPointModel) *dynamic.Router {
+	result := router.DeepCopy()
+	result.EntryPoints = append([]string(nil), entryPoints...)
+
+	if model == nil {
+		return result
+	}
+
+	if model.tls != nil {
+		result.TLS = model.tls.DeepCopy()
+	}
+
+	if len(model.middlewares) > 0 {
+		middlewares := append([]string(nil), model.middlewares...)
+		for _, name := range router.Middlewares {
+			if !contains(model.middlewares, name) {
+				middlewares = append(middlewares, name)
+			}
+		}
+		result.Middlewares = middlewares
+	}
+
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// redirectHandler returns the handler redirecting all the requests of the entry point to its targeted entry point,
+// or nil if the entry point does not redirect.
+func (d *EntryPointDefaults) redirectHandler(ctx context.Context, entryPointName string) (http.Handler, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	port, ok := d.redirectPorts[entryPointName]
+	if !ok {
+		return nil, nil
+	}
+
+	config := d.configs[entryPointName].Redirections.EntryPoint
+	if port == "" {
+		log.FromContext(ctx).Warnf("Unable to get the port of the entry point %q, the requests are redirected without port", config.To)
+	}
+
+	return redirect.NewRedirectScheme(ctx, http.NotFoundHandler(), dynamic.RedirectScheme{
+		Scheme:    config.Scheme,
+		Port:      port,
+		Permanent: config.Permanent,
+	}, redirectMiddlewareName)
+}
@@ -0,0 +1,281 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/responsemodifiers"
+	"github.com/containous/traefik/v2/pkg/server/middleware"
+	"github.com/containous/traefik/v2/pkg/server/service"
+	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryPointDefaults_Apply(t *testing.T) {
+	entryPoints := static.EntryPoints{
+		"web": {Address: ":80"},
+		"websecure": {
+			Address: ":443",
+			HTTP: &static.HTTPConfig{
+				Middlewares: []string{"secure@file"},
+				TLS:         &static.TLSConfig{CertResolver: "le"},
+			},
+		},
+		"websecure2": {
+			Address: ":8443",
+			HTTP: &static.HTTPConfig{
+				Middlewares: []string{"secure@file"},
+				TLS:         &static.TLSConfig{CertResolver: "le"},
+			},
+		},
+		"admin": {
+			Address: ":9443",
+			HTTP: &static.HTTPConfig{
+				Middlewares: []string{"auth@file"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		routers  map[string]*dynamic.Router
+		expected map[string]*dynamic.Router
+	}{
+		{
+			desc: "entry point without defaults",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"web"}, Service: "foo"},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"web"}, Service: "foo"},
+			},
+		},
+		{
+			desc: "default TLS and middlewares",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"websecure"}, Service: "foo", Middlewares: []string{"compress"}},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file", "compress"},
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+				},
+			},
+		},
+		{
+			desc: "router TLS overrides the default one",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"websecure"}, Service: "foo", TLS: &dynamic.RouterTLSConfig{Options: "strict"}},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file"},
+					TLS:         &dynamic.RouterTLSConfig{Options: "strict"},
+				},
+			},
+		},
+		{
+			desc: "default middleware already used by the router",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"websecure"}, Service: "foo", Middlewares: []string{"compress", "secure@file"}},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file", "compress"},
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+				},
+			},
+		},
+		{
+			desc: "entry points with the same defaults",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"websecure", "websecure2"}, Service: "foo"},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {
+					EntryPoints: []string{"websecure", "websecure2"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file"},
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+				},
+			},
+		},
+		{
+			desc: "entry points with different defaults",
+			routers: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"web", "websecure", "admin"}, Service: "foo"},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo": {EntryPoints: []string{"web"}, Service: "foo"},
+				"websecure-foo": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file"},
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+				},
+				"admin-foo": {
+					EntryPoints: []string{"admin"},
+					Service:     "foo",
+					Middlewares: []string{"auth@file"},
+				},
+			},
+		},
+		{
+			desc: "router without entry points",
+			routers: map[string]*dynamic.Router{
+				"foo@docker": {Service: "foo"},
+			},
+			expected: map[string]*dynamic.Router{
+				"foo@docker": {EntryPoints: []string{"web"}, Service: "foo"},
+				"websecure-foo@docker": {
+					EntryPoints: []string{"websecure"},
+					Service:     "foo",
+					Middlewares: []string{"secure@file"},
+					TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			defaults := NewEntryPointDefaults(entryPoints)
+
+			original := make(map[string]*dynamic.Router)
+			for name, router := range test.routers {
+				original[name] = router.DeepCopy()
+			}
+
+			routers := defaults.Apply(test.routers, []string{"web", "websecure"})
+
+			assert.Equal(t, test.expected, routers)
+			assert.Equal(t, original, test.routers)
+		})
+	}
+}
+
+func TestRouterManager_EntryPointDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Secure", req.Header.Get("X-Secure"))
+	}))
+	defer server.Close()
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints: []string{"web", "websecure"},
+					Service:     "foo",
+					Rule:        "Host(`foo.bar`)",
+				},
+				"baz@file": {
+					Service: "foo",
+					Rule:    "Host(`baz.foo`)",
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@file": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: server.URL}},
+					},
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"secure@file": {
+					Headers: &dynamic.Headers{CustomRequestHeaders: map[string]string{"X-Secure": "true"}},
+				},
+			},
+		},
+	})
+
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
+	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+	routerManager.SetEntryPointDefaults(NewEntryPointDefaults(static.EntryPoints{
+		"web": {
+			Address: ":80",
+			HTTP: &static.HTTPConfig{
+				Redirections: &static.Redirections{
+					EntryPoint: &static.RedirectEntryPoint{To: "websecure", Scheme: "https", Permanent: true},
+				},
+			},
+		},
+		"websecure": {
+			Address: ":8443",
+			HTTP: &static.HTTPConfig{
+				Middlewares: []string{"secure@file"},
+				TLS:         &static.TLSConfig{CertResolver: "le"},
+			},
+		},
+	}))
+
+	entryPoints := []string{"web", "websecure"}
+	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
+	handlersTLS := routerManager.BuildHandlers(context.Background(), entryPoints, true)
+
+	require.Contains(t, rtConf.Routers, "foo@file")
+	assert.Equal(t, []string{"web"}, rtConf.Routers["foo@file"].EntryPoints)
+	assert.Nil(t, rtConf.Routers["foo@file"].TLS)
+
+	require.Contains(t, rtConf.Routers, "websecure-foo@file")
+	assert.Equal(t, runtime.StatusEnabled, rtConf.Routers["websecure-foo@file"].Status)
+	assert.Equal(t, []string{"websecure"}, rtConf.Routers["websecure-foo@file"].EntryPoints)
+	assert.Equal(t, []string{"secure@file"}, rtConf.Routers["websecure-foo@file"].Middlewares)
+	assert.Equal(t, &dynamic.RouterTLSConfig{CertResolver: "le"}, rtConf.Routers["websecure-foo@file"].TLS)
+
+	// The routers of the redirecting entry point keep handling the requests they match.
+	rw := httptest.NewRecorder()
+	requestdecorator.New(nil).ServeHTTP(rw, testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/path", nil), handlers["web"].ServeHTTP)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Header().Get("X-Secure"))
+
+	// The other requests are redirected.
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://bar.foo/path", nil)
+	req.RequestURI = "/path"
+
+	rw = httptest.NewRecorder()
+	requestdecorator.New(nil).ServeHTTP(rw, req, handlers["web"].ServeHTTP)
+	assert.Equal(t, http.StatusMovedPermanently, rw.Code)
+	assert.Equal(t, "https://bar.foo:8443/path", rw.Header().Get("Location"))
+
+	// The routers without entry points are not served on the redirecting entry point.
+	require.Contains(t, rtConf.Routers, "baz@file")
+	assert.Equal(t, []string{"websecure"}, rtConf.Routers["baz@file"].EntryPoints)
+
+	req = testhelpers.MustNewRequest(http.MethodGet, "http://baz.foo/path", nil)
+	req.RequestURI = "/path"
+
+	rw = httptest.NewRecorder()
+	requestdecorator.New(nil).ServeHTTP(rw, req, handlers["web"].ServeHTTP)
+	assert.Equal(t, http.StatusMovedPermanently, rw.Code)
+	assert.Equal(t, "https://baz.foo:8443/path", rw.Header().Get("Location"))
+
+	require.NotContains(t, handlers, "websecure")
+	require.Contains(t, handlersTLS, "websecure")
+
+	rw = httptest.NewRecorder()
+	requestdecorator.New(nil).ServeHTTP(rw, testhelpers.MustNewRequest(http.MethodGet, "https://baz.foo/", nil), handlersTLS["websecure"].ServeHTTP)
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	rw = httptest.NewRecorder()
+	requestdecorator.New(nil).ServeHTTP(rw, testhelpers.MustNewRequest(http.MethodGet, "https://foo.bar/", nil), handlersTLS["websecure"].ServeHTTP)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "true", rw.Header().Get("X-Secure"))
+}
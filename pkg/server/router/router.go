@@ -6,7 +6,9 @@ import (
 	"net/http"
 
 	"github.com/containous/alice"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/v2/pkg/middlewares/recovery"
@@ -30,6 +32,8 @@ func NewManager(conf *runtime.Configuration,
 ) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
+		rulesRouters:       make(map[string]*rules.Router),
+		rulesRoutersTLS:    make(map[string]*rules.Router),
 		serviceManager:     serviceManager,
 		middlewaresBuilder: middlewaresBuilder,
 		modifierBuilder:    modifierBuilder,
@@ -44,6 +48,85 @@ type Manager struct {
 	middlewaresBuilder *middleware.Builder
 	modifierBuilder    *responsemodifiers.Builder
 	conf               *runtime.Configuration
+	cache              *HandlerCache
+	defaults           *EntryPointDefaults
+	defaultsApplied    bool
+	forwardedHeaders   map[string]*static.ForwardedHeaders
+	healthCheckOff     bool
+	// rulesRouters and rulesRoutersTLS hold the routers of the entry points, for the non-TLS and the TLS requests.
+	rulesRouters    map[string]*rules.Router
+	rulesRoutersTLS map[string]*rules.Router
+}
+
+// SetCache sets the cache keeping the router handlers across the configuration reloads.
+func (m *Manager) SetCache(cache *HandlerCache) {
+	m.cache = cache
+}
+
+// SetEntryPointDefaults sets the defaults of the entry points, applied to the routers when building the handlers.
+func (m *Manager) SetEntryPointDefaults(defaults *EntryPointDefaults) {
+	m.defaults = defaults
+}
+
+// DisableHealthCheck prevents the health checks of the services from being launched once the handlers are built,
+// e.g. when the configuration is only checked.
+func (m *Manager) DisableHealthCheck() {
+	m.healthCheckOff = true
+}
+
+// SetForwardedHeaders sets the forwarded headers configuration of the entry points,
+// for the ClientIP matcher to trust the X-Forwarded-For header as the entry point does.
+func (m *Manager) SetForwardedHeaders(forwardedHeaders map[string]*static.ForwardedHeaders) {
+	m.forwardedHeaders = forwardedHeaders
+}
+
+// applyEntryPointDefaults applies, once, the defaults of the entry points to the runtime routers.
+func (m *Manager) applyEntryPointDefaults(entryPoints []string) {
+	if m.defaultsApplied || m.defaults == nil || m.conf == nil || len(m.conf.Routers) == 0 {
+		return
+	}
+	m.defaultsApplied = true
+
+	routers := make(map[string]*dynamic.Router, len(m.conf.Routers))
+	for routerName, routerInfo := range m.conf.Routers {
+		routers[routerName] = routerInfo.Router
+	}
+
+	withDefaults := m.defaults.Apply(routers, entryPoints)
+
+	for routerName := range m.conf.Routers {
+		if _, ok := withDefaults[routerName]; !ok {
+			delete(m.conf.Routers, routerName)
+		}
+	}
+
+	for routerName, router := range withDefaults {
+		if routerInfo, ok := m.conf.Routers[routerName]; ok {
+			routerInfo.Router = router
+			continue
+		}
+		m.conf.Routers[routerName] = &runtime.RouterInfo{Router: router, Status: runtime.StatusEnabled}
+	}
+}
+
+// RulesRouter returns the router built for the non-TLS, or TLS, requests of the entry point, or nil if there is none.
+func (m *Manager) RulesRouter(entryPointName string, tls bool) *rules.Router {
+	if tls {
+		return m.rulesRoutersTLS[entryPointName]
+	}
+	return m.rulesRouters[entryPointName]
+}
+
+// RedirectTo returns the entry point targeted by the redirection of the non-TLS requests of the entry point, if any,
+// which applies to the requests matched by none of its routers.
+func (m *Manager) RedirectTo(entryPointName string) string {
+	if m.defaults == nil {
+		return ""
+	}
+	if _, ok := m.defaults.redirectPorts[entryPointName]; !ok {
+		return ""
+	}
+	return m.defaults.configs[entryPointName].Redirections.EntryPoint.To
 }
 
 func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls bool) map[string]map[string]*runtime.RouterInfo {
@@ -56,18 +139,26 @@ func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls
 
 // BuildHandlers Builds handler for all entry points
 func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, tls bool) map[string]http.Handler {
+	m.applyEntryPointDefaults(entryPoints)
+
 	entryPointHandlers := make(map[string]http.Handler)
 
 	for entryPointName, routers := range m.getHTTPRouters(rootCtx, entryPoints, tls) {
 		entryPointName := entryPointName
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, rulesRouter, err := m.buildEntryPointHandler(ctx, entryPointName, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
 		}
 
+		if tls {
+			m.rulesRoutersTLS[entryPointName] = rulesRouter
+		} else {
+			m.rulesRouters[entryPointName] = rulesRouter
+		}
+
 		handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
 			return accesslog.NewFieldHandler(next, log.EntryPointName, entryPointName, accesslog.AddOriginFields), nil
 		}).Then(handler)
@@ -79,15 +170,53 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		}
 	}
 
-	m.serviceManager.LaunchHealthCheck()
+	if !tls {
+		m.buildRedirectHandlers(rootCtx, entryPoints, entryPointHandlers)
+	}
+
+	if !m.healthCheckOff {
+		m.serviceManager.LaunchHealthCheck()
+	}
 
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
+// buildRedirectHandlers adds the redirection to the targeted entry point to the non-TLS handlers of the entry points redirecting to another one,
+// as a catch-all route with the lowest priority, so that the routers of the entry point keep handling the requests they match.
+func (m *Manager) buildRedirectHandlers(rootCtx context.Context, entryPoints []string, entryPointHandlers map[string]http.Handler) {
+	for _, entryPointName := range entryPoints {
+		entryPointName := entryPointName
+		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
+
+		handler, err := m.defaults.redirectHandler(ctx, entryPointName)
+		if err != nil {
+			log.FromContext(ctx).Error(err)
+			continue
+		}
+		if handler == nil {
+			continue
+		}
+
+		if rulesRouter, ok := m.rulesRouters[entryPointName]; ok {
+			rulesRouter.NotFoundHandler = handler
+			continue
+		}
+
+		entryPointHandlers[entryPointName] = accesslog.NewFieldHandler(handler, log.EntryPointName, entryPointName, accesslog.AddOriginFields)
+	}
+}
+
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.RouterInfo) (http.Handler, *rules.Router, error) {
 	router, err := rules.NewRouter()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if forwardedHeaders := m.forwardedHeaders[entryPointName]; forwardedHeaders != nil {
+		err = router.TrustForwardedHeaders(forwardedHeaders.Insecure, forwardedHeaders.TrustedIPs)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	for routerName, routerConfig := range configs {
@@ -101,7 +230,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		err = router.AddRoute(routerConfig.Rule, routerConfig.Priority, handler)
+		err = router.AddRoute(routerName, routerConfig.Rule, routerConfig.Priority, handler)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -116,7 +245,12 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		return recovery.New(ctx, next, recoveryMiddlewareName)
 	})
 
-	return chain.Then(router)
+	handler, err := chain.Then(router)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return handler, router, nil
 }
 
 func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, routerConfig *runtime.RouterInfo) (http.Handler, error) {
@@ -124,7 +258,25 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		return handler, nil
 	}
 
-	handler, err := m.buildHTTPHandler(ctx, routerConfig, routerName)
+	var qualifiedNames []string
+	for _, name := range routerConfig.Middlewares {
+		qualifiedNames = append(qualifiedNames, internal.GetQualifiedName(ctx, name))
+	}
+	routerConfig.Middlewares = qualifiedNames
+
+	if handler, deps, ok := m.cache.get(routerName); ok {
+		log.FromContext(ctx).Debug("Reusing the router handler, as its configuration did not change")
+
+		deps.Replay(m.conf)
+		m.serviceManager.AddBalancers(deps.Balancers())
+
+		m.routerHandlers[routerName] = handler
+		return handler, nil
+	}
+
+	deps := internal.NewDependencies()
+
+	handler, err := m.buildHTTPHandler(internal.AddDependenciesInContext(ctx, deps), routerConfig, routerName)
 	if err != nil {
 		return nil, err
 	}
@@ -139,16 +291,13 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		m.routerHandlers[routerName] = handlerWithAccessLog
 	}
 
+	m.cache.set(routerName, m.routerHandlers[routerName], deps)
+
 	return m.routerHandlers[routerName], nil
 }
 
 func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterInfo, routerName string) (http.Handler, error) {
-	var qualifiedNames []string
-	for _, name := range router.Middlewares {
-		qualifiedNames = append(qualifiedNames, internal.GetQualifiedName(ctx, name))
-	}
-	router.Middlewares = qualifiedNames
-	rm := m.modifierBuilder.Build(ctx, qualifiedNames)
+	rm := m.modifierBuilder.Build(ctx, router.Middlewares)
 
 	if router.Service == "" {
 		return nil, errors.New("the service is missing on the router")
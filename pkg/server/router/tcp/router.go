@@ -193,45 +193,72 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 		domains, err := rules.ParseHostSNI(routerConfig.Rule)
 		if err != nil {
-			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
+			routerErr := fmt.Errorf("invalid rule %s, error: %v", routerConfig.Rule, err)
 			routerConfig.AddError(routerErr, true)
 			logger.Error(routerErr)
 			continue
 		}
 
-		for _, domain := range domains {
-			logger.Debugf("Adding route %s on TCP", domain)
-			switch {
-			case routerConfig.TLS != nil:
-				if routerConfig.TLS.Passthrough {
-					router.AddRoute(domain, handler)
-				} else {
-					tlsOptionsName := routerConfig.TLS.Options
-
-					if len(tlsOptionsName) == 0 {
-						tlsOptionsName = defaultTLSConfigName
-					}
-
-					if tlsOptionsName != defaultTLSConfigName {
-						tlsOptionsName = internal.GetQualifiedName(ctxRouter, tlsOptionsName)
-					}
-
-					tlsConf, err := m.tlsManager.Get("default", tlsOptionsName)
-					if err != nil {
-						routerConfig.AddError(err, true)
-						logger.Debug(err)
-						continue
-					}
-
-					router.AddRouteTLS(domain, handler, tlsConf)
-				}
-			case domain == "*":
-				router.AddCatchAllNoTLS(handler)
-			default:
+		logger.Debugf("Adding route %s on TCP", routerConfig.Rule)
+
+		switch {
+		case routerConfig.TLS == nil:
+			if !isCatchAll(domains) {
 				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
+				continue
+			}
+
+			if protos, _ := rules.ParseALPN(routerConfig.Rule); len(protos) > 0 {
+				logger.Warn("TCP Router ignored, cannot specify an ALPN rule without TLS")
+				continue
+			}
+
+			err = router.AddRouteNoTLS(routerName, routerConfig.Rule, routerConfig.Priority, handler)
+		case routerConfig.TLS.Passthrough:
+			err = router.AddRoute(routerName, routerConfig.Rule, routerConfig.Priority, handler)
+		default:
+			tlsOptionsName := routerConfig.TLS.Options
+
+			if len(tlsOptionsName) == 0 {
+				tlsOptionsName = defaultTLSConfigName
+			}
+
+			if tlsOptionsName != defaultTLSConfigName {
+				tlsOptionsName = internal.GetQualifiedName(ctxRouter, tlsOptionsName)
+			}
+
+			var tlsConf *tls.Config
+			tlsConf, err = m.tlsManager.Get("default", tlsOptionsName)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Debug(err)
+				continue
 			}
+
+			// The protocols matched by the rule are the ones negotiated when terminating TLS.
+			if protos, _ := rules.ParseALPN(routerConfig.Rule); len(protos) > 0 && tlsConf != nil {
+				tlsConf = tlsConf.Clone()
+				tlsConf.NextProtos = protos
+			}
+
+			err = router.AddRouteTLS(routerName, routerConfig.Rule, routerConfig.Priority, handler, tlsConf)
+		}
+
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
 		}
 	}
 
 	return router, nil
 }
+
+// isCatchAll tells whether the given HostSNI domains match all the connections.
+func isCatchAll(domains []string) bool {
+	for _, domain := range domains {
+		if domain != "*" {
+			return false
+		}
+	}
+	return true
+}
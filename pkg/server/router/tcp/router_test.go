@@ -65,6 +65,67 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 0,
 		},
+		{
+			desc: "Routers with ClientIP and ALPN rules",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`foo.bar`) && ALPN(`h2`) && !ClientIP(`10.0.0.0/8`)",
+						Priority:    10,
+						TLS:         &dynamic.RouterTCPTLSConfig{},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`*`) && ClientIP(`192.168.0.0/16`)",
+					},
+				},
+			},
+			expectedError: 0,
+		},
+		{
+			desc: "Router with invalid ClientIP",
+			serviceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "ClientIP(`foo`)",
+					},
+				},
+			},
+			expectedError: 1,
+		},
 		{
 			desc: "One router with wrong rule",
 			serviceConfig: map[string]*runtime.TCPServiceInfo{
@@ -16,9 +16,13 @@ import (
 	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/containous/traefik/v2/pkg/middlewares/cache"
 	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/containous/traefik/v2/pkg/provider"
 	"github.com/containous/traefik/v2/pkg/safe"
+	"github.com/containous/traefik/v2/pkg/server/router"
+	"github.com/containous/traefik/v2/pkg/server/service"
+	tcpservice "github.com/containous/traefik/v2/pkg/server/service/tcp"
 	"github.com/containous/traefik/v2/pkg/tls"
 	"github.com/containous/traefik/v2/pkg/tracing"
 	"github.com/containous/traefik/v2/pkg/tracing/jaeger"
@@ -37,15 +41,34 @@ type Server struct {
 	accessLoggerMiddleware     *accesslog.Handler
 	tracer                     *tracing.Tracing
 	routinesPool               *safe.Pool
-	defaultRoundTripper        http.RoundTripper
+	roundTripperManager        *service.RoundTripperManager
 	metricsRegistry            metrics.Registry
+	cacheManager               *cache.Manager
+	routerHandlerCache         *router.HandlerCache
+	tcpHandlerCache            *tcpservice.HandlerCache
+	weightsStore               *service.WeightsStore
+	entryPointDefaults         *router.EntryPointDefaults
+	forwardedHeaders           map[string]*static.ForwardedHeaders
+	healthCheckDisabled        bool
+	reloadHistory              *runtime.ReloadHistory
+	tlsConfiguration           *dynamic.TLSConfiguration
+	tlsFiles                   map[string]string
 	provider                   provider.Provider
 	configurationListeners     []func(dynamic.Configuration)
+	runtimeListeners           []func(*runtime.Configuration)
 	requestDecorator           *requestdecorator.RequestDecorator
 	providersThrottleDuration  time.Duration
 	tlsManager                 *tls.Manager
 	api                        func(configuration *runtime.Configuration) http.Handler
 	restHandler                http.Handler
+	cancel                     context.CancelFunc
+	readyOnce                  sync.Once
+}
+
+// emptyProvider is implemented by the providers which may provide no configuration at all,
+// such as the aggregator of the providers when none is configured.
+type emptyProvider interface {
+	Empty() bool
 }
 
 // RouteAppenderFactory the route appender factory interface
@@ -130,12 +153,12 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 		server.providersThrottleDuration = time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration)
 	}
 
-	transport, err := createHTTPTransport(staticConfiguration.ServersTransport)
+	transport, err := service.NewRoundTripper(defaultServersTransport(staticConfiguration.ServersTransport))
 	if err != nil {
 		log.WithoutContext().Errorf("Could not configure HTTP Transport, fallbacking on default transport: %v", err)
-		server.defaultRoundTripper = http.DefaultTransport
+		server.roundTripperManager = service.NewRoundTripperManager(http.DefaultTransport)
 	} else {
-		server.defaultRoundTripper = transport
+		server.roundTripperManager = service.NewRoundTripperManager(transport)
 	}
 
 	server.routinesPool = safe.NewPool(context.Background())
@@ -153,6 +176,22 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 	server.requestDecorator = requestdecorator.New(staticConfiguration.HostResolver)
 
 	server.metricsRegistry = registerMetricClients(staticConfiguration.Metrics)
+	server.cacheManager = cache.NewManager(server.metricsRegistry)
+	server.routerHandlerCache = router.NewHandlerCache()
+	server.tcpHandlerCache = tcpservice.NewHandlerCache()
+	server.weightsStore = service.NewWeightsStore()
+	server.entryPointDefaults = router.NewEntryPointDefaults(staticConfiguration.EntryPoints)
+	server.forwardedHeaders = make(map[string]*static.ForwardedHeaders)
+	for entryPointName, entryPoint := range staticConfiguration.EntryPoints {
+		if entryPoint != nil && entryPoint.ForwardedHeaders != nil {
+			server.forwardedHeaders[entryPointName] = entryPoint.ForwardedHeaders
+		}
+	}
+	reloadHistorySize := defaultReloadHistorySize
+	if staticConfiguration.API != nil && staticConfiguration.API.ReloadHistorySize > 0 {
+		reloadHistorySize = staticConfiguration.API.ReloadHistorySize
+	}
+	server.reloadHistory = runtime.NewReloadHistory(reloadHistorySize)
 
 	if staticConfiguration.AccessLog != nil {
 		var err error
@@ -166,6 +205,8 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 
 // Start starts the server and Stop/Close it when context is Done
 func (s *Server) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
 	go func() {
 		defer s.Close()
 		<-ctx.Done()
@@ -186,6 +227,16 @@ func (s *Server) Start(ctx context.Context) {
 	s.routinesPool.Go(func(stop chan bool) {
 		s.listenSignals(stop)
 	})
+
+	// Without providers, no configuration is ever loaded.
+	if p, ok := s.provider.(emptyProvider); ok && p.Empty() {
+		s.notifyReady()
+	}
+}
+
+// notifyReady reports, once, that the server is ready, i.e. that it serves the first configuration of the providers.
+func (s *Server) notifyReady() {
+	s.readyOnce.Do(notifyUpgradeReady)
 }
 
 // Wait blocks until server is shutted down.
@@ -248,13 +299,14 @@ func (s *Server) Close() {
 
 func (s *Server) startTCPServers() {
 	// Use an empty configuration in order to initialize the default handlers with internal routes
-	routers := s.loadConfigurationTCP(dynamic.Configurations{})
+	routers, _ := s.loadConfigurationTCP(dynamic.Configurations{})
 	for entryPointName, router := range routers {
 		s.entryPointsTCP[entryPointName].switchRouter(router)
 	}
 
 	for entryPointName, serverEntryPoint := range s.entryPointsTCP {
 		ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
+		serverEntryPoint.setMetricsRegistry(s.metricsRegistry, entryPointName)
 		go serverEntryPoint.startTCP(ctx)
 	}
 }
@@ -286,6 +338,11 @@ func (s *Server) AddListener(listener func(dynamic.Configuration)) {
 	s.configurationListeners = append(s.configurationListeners, listener)
 }
 
+// AddRuntimeListener adds a new listener function used when a new runtime configuration has been built
+func (s *Server) AddRuntimeListener(listener func(*runtime.Configuration)) {
+	s.runtimeListeners = append(s.runtimeListeners, listener)
+}
+
 func (s *Server) startProvider() {
 	logger := log.WithoutContext()
 
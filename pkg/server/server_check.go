@@ -0,0 +1,151 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+)
+
+// ConfigurationError is an error found while building an element of the dynamic configuration.
+type ConfigurationError struct {
+	// Kind is the kind of the element: router, middleware, service, tcpRouter, tcpService, tlsOptions, tlsStore or certificate.
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+func (e ConfigurationError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Kind, e.Name, e.Message)
+}
+
+// CheckConfiguration builds the routers, middlewares, services and TLS configurations of the given dynamic configurations,
+// as on a configuration reload but without binding the entry points,
+// and returns the errors found while building them, sorted by kind and name.
+func CheckConfiguration(staticConfiguration static.Configuration, configurations dynamic.Configurations) []ConfigurationError {
+	// The observability backends are not needed to build the handlers, and must not be started.
+	staticConfiguration.Metrics = nil
+	staticConfiguration.Tracing = nil
+	staticConfiguration.AccessLog = nil
+
+	entryPoints := make(TCPEntryPoints)
+	for entryPointName := range staticConfiguration.EntryPoints {
+		entryPoints[entryPointName] = &TCPEntryPoint{}
+	}
+
+	s := NewServer(staticConfiguration, nil, entryPoints, traefiktls.NewManager())
+	defer s.routinesPool.Cleanup()
+
+	// The health checks would send requests to the servers, and replace the ones of a running instance.
+	s.healthCheckDisabled = true
+
+	newConfigurations := make(dynamic.Configurations)
+	for providerName, configuration := range configurations {
+		if !isEmptyConfiguration(configuration) {
+			newConfigurations[providerName] = configuration
+		}
+	}
+
+	_, rtConf := s.loadConfigurationTCP(newConfigurations)
+
+	errs := runtimeErrors(rtConf)
+	errs = append(errs, certResolverErrors(staticConfiguration, rtConf)...)
+	errs = append(errs, s.tlsErrors(mergeConfiguration(newConfigurations).TLS)...)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Kind != errs[j].Kind {
+			return errs[i].Kind < errs[j].Kind
+		}
+		return errs[i].Name < errs[j].Name
+	})
+
+	return errs
+}
+
+// runtimeErrors returns the errors recorded on the elements of the runtime configuration.
+func runtimeErrors(rtConf *runtime.Configuration) []ConfigurationError {
+	var errs []ConfigurationError
+
+	add := func(kind, name string, messages []string) {
+		for _, message := range messages {
+			errs = append(errs, ConfigurationError{Kind: kind, Name: name, Message: message})
+		}
+	}
+
+	for name, rt := range rtConf.Routers {
+		add("router", name, rt.Err)
+	}
+	for name, mi := range rtConf.Middlewares {
+		add("middleware", name, mi.Err)
+	}
+	for name, si := range rtConf.Services {
+		add("service", name, si.Err)
+	}
+	for name, rt := range rtConf.TCPRouters {
+		add("tcpRouter", name, rt.Err)
+	}
+	for name, si := range rtConf.TCPServices {
+		add("tcpService", name, si.Err)
+	}
+
+	return errs
+}
+
+// certResolverErrors returns an error for each router using a certificate resolver which is not defined in the static configuration.
+func certResolverErrors(staticConfiguration static.Configuration, rtConf *runtime.Configuration) []ConfigurationError {
+	var errs []ConfigurationError
+
+	for name, rt := range rtConf.Routers {
+		if rt.TLS == nil || rt.TLS.CertResolver == "" {
+			continue
+		}
+		if _, ok := staticConfiguration.CertificatesResolvers[rt.TLS.CertResolver]; !ok {
+			errs = append(errs, ConfigurationError{Kind: "router", Name: name, Message: fmt.Sprintf("the certificate resolver %q does not exist", rt.TLS.CertResolver)})
+		}
+	}
+
+	for name, rt := range rtConf.TCPRouters {
+		if rt.TLS == nil || rt.TLS.CertResolver == "" {
+			continue
+		}
+		if _, ok := staticConfiguration.CertificatesResolvers[rt.TLS.CertResolver]; !ok {
+			errs = append(errs, ConfigurationError{Kind: "tcpRouter", Name: name, Message: fmt.Sprintf("the certificate resolver %q does not exist", rt.TLS.CertResolver)})
+		}
+	}
+
+	return errs
+}
+
+// tlsErrors returns the errors of the TLS options, which are only reported by the routers using them,
+// and of the certificates, which are only logged when loading them.
+func (s *Server) tlsErrors(conf *dynamic.TLSConfiguration) []ConfigurationError {
+	var errs []ConfigurationError
+
+	for name := range conf.Options {
+		if _, err := s.tlsManager.Get("default", name); err != nil {
+			errs = append(errs, ConfigurationError{Kind: "tlsOptions", Name: name, Message: err.Error()})
+		}
+	}
+
+	for name, store := range conf.Stores {
+		if store.DefaultCertificate == nil {
+			continue
+		}
+		if err := store.DefaultCertificate.AppendCertificate(make(map[string]map[string]*tls.Certificate), name); err != nil {
+			errs = append(errs, ConfigurationError{Kind: "tlsStore", Name: name, Message: err.Error()})
+		}
+	}
+
+	for _, cert := range conf.Certificates {
+		if err := cert.Certificate.AppendCertificate(make(map[string]map[string]*tls.Certificate), "default"); err != nil {
+			errs = append(errs, ConfigurationError{Kind: "certificate", Name: strings.TrimSpace(cert.GetTruncatedCertificateName()), Message: err.Error()})
+		}
+	}
+
+	return errs
+}
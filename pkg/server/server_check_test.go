@@ -0,0 +1,170 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/healthcheck"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConfiguration(t *testing.T) {
+	staticConfiguration := static.Configuration{
+		EntryPoints: static.EntryPoints{
+			"web": {Address: ":80"},
+		},
+		CertificatesResolvers: map[string]static.CertificateResolver{
+			"le": {},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		config   *dynamic.Configuration
+		expected []ConfigurationError
+	}{
+		{
+			desc: "valid configuration",
+			config: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							EntryPoints: []string{"web"},
+							Rule:        "Host(`foo.bar`)",
+							Service:     "foo",
+							TLS:         &dynamic.RouterTLSConfig{CertResolver: "le"},
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"foo": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "broken references",
+			config: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							EntryPoints: []string{"web"},
+							Rule:        "Host(`foo.bar`)",
+							Service:     "foo",
+							Middlewares: []string{"unknown"},
+						},
+						"bar": {
+							EntryPoints: []string{"web"},
+							Rule:        "Host(`bar.foo`)",
+							Service:     "unknown",
+							TLS:         &dynamic.RouterTLSConfig{CertResolver: "unknown"},
+						},
+						"baz": {
+							EntryPoints: []string{"unknown"},
+							Rule:        "Host(`baz.foo`)",
+							Service:     "foo",
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"foo": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+							},
+						},
+					},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"foo": {
+							EntryPoints: []string{"web"},
+							Rule:        "HostSNI(`foo.bar`)",
+							Service:     "unknown",
+						},
+					},
+				},
+			},
+			expected: []ConfigurationError{
+				{Kind: "router", Name: "bar@file", Message: `the service "unknown@file" does not exist`},
+				{Kind: "router", Name: "bar@file", Message: `the certificate resolver "unknown" does not exist`},
+				{Kind: "router", Name: "baz@file", Message: `entryPoint "unknown" doesn't exist`},
+				{Kind: "router", Name: "baz@file", Message: "no valid entryPoint for this router"},
+				{Kind: "router", Name: "foo@file", Message: `middleware "unknown@file" does not exist`},
+				{Kind: "tcpRouter", Name: "foo@file", Message: `the service "unknown@file" does not exist`},
+			},
+		},
+		{
+			desc: "invalid certificate",
+			config: &dynamic.Configuration{
+				TLS: &dynamic.TLSConfiguration{
+					Certificates: []*traefiktls.CertAndStores{
+						{Certificate: traefiktls.Certificate{CertFile: "/not/found.crt", KeyFile: "/not/found.key"}},
+					},
+				},
+			},
+			expected: []ConfigurationError{
+				{Kind: "certificate", Name: "/not/found.crt", Message: "unable to generate TLS certificate : tls: failed to find any PEM data in certificate input"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			errs := CheckConfiguration(staticConfiguration, dynamic.Configurations{"file": test.config})
+
+			assert.Equal(t, test.expected, errs)
+		})
+	}
+}
+
+func TestCheckConfiguration_noHealthCheck(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	staticConfiguration := static.Configuration{
+		EntryPoints: static.EntryPoints{
+			"web": {Address: ":80"},
+		},
+	}
+
+	config := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"healthchecked": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.bar`)",
+					Service:     "healthchecked",
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"healthchecked": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers:     []dynamic.Server{{URL: server.URL}},
+						HealthCheck: &dynamic.HealthCheck{Path: "/health", Interval: "10ms"},
+					},
+				},
+			},
+		},
+	}
+
+	errs := CheckConfiguration(staticConfiguration, dynamic.Configurations{"file": config})
+	require.Empty(t, errs)
+
+	assert.NotContains(t, healthcheck.GetHealthCheck().Backends, "healthchecked@file")
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Zero(t, atomic.LoadInt32(&requests))
+}
@@ -23,11 +23,16 @@ import (
 	"github.com/containous/traefik/v2/pkg/server/service"
 	"github.com/containous/traefik/v2/pkg/server/service/tcp"
 	tcpCore "github.com/containous/traefik/v2/pkg/tcp"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
 	"github.com/eapache/channels"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
+// defaultReloadHistorySize is the number of reloads of the dynamic configuration kept in the history exposed by the API,
+// when the API does not set it.
+const defaultReloadHistorySize = 10
+
 // loadConfiguration manages dynamically routers, middlewares, servers and TLS configurations
 func (s *Server) loadConfiguration(configMsg dynamic.Message) {
 	currentConfigurations := s.currentConfigurations.Get().(dynamic.Configurations)
@@ -38,7 +43,7 @@ func (s *Server) loadConfiguration(configMsg dynamic.Message) {
 
 	s.metricsRegistry.ConfigReloadsCounter().Add(1)
 
-	handlersTCP := s.loadConfigurationTCP(newConfigurations)
+	handlersTCP, rtConf := s.loadConfigurationTCP(newConfigurations)
 	for entryPointName, router := range handlersTCP {
 		s.entryPointsTCP[entryPointName].switchRouter(router)
 	}
@@ -46,11 +51,19 @@ func (s *Server) loadConfiguration(configMsg dynamic.Message) {
 	s.metricsRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 
 	s.currentConfigurations.Set(newConfigurations)
+	s.reloadHistory.Add(configMsg.ProviderName, rtConf.DynamicConfiguration())
 
+	listenerConf := s.withEntryPointDefaults(configMsg.Configuration)
 	for _, listener := range s.configurationListeners {
-		listener(*configMsg.Configuration)
+		listener(*listenerConf)
+	}
+
+	for _, listener := range s.runtimeListeners {
+		listener(rtConf)
 	}
 
+	s.notifyReady()
+
 	if s.metricsRegistry.IsEpEnabled() || s.metricsRegistry.IsSvcEnabled() {
 		var entrypoints []string
 		for key := range s.entryPointsTCP {
@@ -60,9 +73,26 @@ func (s *Server) loadConfiguration(configMsg dynamic.Message) {
 	}
 }
 
+// withEntryPointDefaults returns a copy of the configuration of a provider, with the defaults of the entry points applied to its routers,
+// so that the configuration listeners, such as the ACME providers, see the routers as they are built.
+func (s *Server) withEntryPointDefaults(configuration *dynamic.Configuration) *dynamic.Configuration {
+	if s.entryPointDefaults == nil || configuration == nil || configuration.HTTP == nil || len(configuration.HTTP.Routers) == 0 {
+		return configuration
+	}
+
+	var entryPoints []string
+	for entryPointName := range s.entryPointsTCP {
+		entryPoints = append(entryPoints, entryPointName)
+	}
+
+	conf := configuration.DeepCopy()
+	conf.HTTP.Routers = s.entryPointDefaults.Apply(conf.HTTP.Routers, entryPoints)
+	return conf
+}
+
 // loadConfigurationTCP returns a new gorilla.mux Route from the specified global configuration and the dynamic
-// provider configurations.
-func (s *Server) loadConfigurationTCP(configurations dynamic.Configurations) map[string]*tcpCore.Router {
+// provider configurations, along with the runtime configuration it was built from.
+func (s *Server) loadConfigurationTCP(configurations dynamic.Configurations) (map[string]*tcpCore.Router, *runtime.Configuration) {
 	ctx := context.Background()
 
 	var entryPoints []string
@@ -70,16 +100,85 @@ func (s *Server) loadConfigurationTCP(configurations dynamic.Configurations) map
 		entryPoints = append(entryPoints, entryPointName)
 	}
 
-	conf := mergeConfiguration(configurations)
+	// The handlers are built from a copy of the configuration, as building them mutates it,
+	// while the router handlers are reused by comparing the original configuration across the reloads.
+	original := mergeConfiguration(configurations)
+	conf := *original.DeepCopy()
+
+	// Rebuilding the TLS configurations regenerates the default certificate,
+	// so it is only done when they change, or when the files they read, such as rotated certificates, change.
+	tlsFiles := readTLSFiles(original.TLS)
+	if !reflect.DeepEqual(original.TLS, s.tlsConfiguration) || !reflect.DeepEqual(tlsFiles, s.tlsFiles) {
+		s.tlsManager.UpdateConfigs(ctx, conf.TLS.Stores, conf.TLS.Options, conf.TLS.Certificates)
+		s.tlsConfiguration = original.TLS
+		s.tlsFiles = tlsFiles
+	}
 
-	s.tlsManager.UpdateConfigs(ctx, conf.TLS.Stores, conf.TLS.Options, conf.TLS.Certificates)
+	s.roundTripperManager.Update(conf.HTTP.ServersTransports)
+	if s.routerHandlerCache != nil {
+		s.routerHandlerCache.Update(original.HTTP)
+	}
+	s.tcpHandlerCache.Update(original.TCP)
 
 	rtConf := runtime.NewConfig(conf)
-	handlersNonTLS, handlersTLS := s.createHTTPHandlers(ctx, rtConf, entryPoints)
+	rtConf.SetDynamicConfiguration(&original)
+	rtConf.SetReloadHistory(s.reloadHistory)
+
+	handlersNonTLS, handlersTLS, routerManager := s.createHTTPHandlers(ctx, rtConf, entryPoints)
 	routersTCP := s.createTCPRouters(ctx, rtConf, entryPoints, handlersNonTLS, handlersTLS)
 	rtConf.PopulateUsedBy()
 
-	return routersTCP
+	tester := &ruleTester{
+		entryPoints:      s.entryPointsTCP,
+		rtConf:           rtConf,
+		routerManager:    routerManager,
+		routersTCP:       routersTCP,
+		requestDecorator: s.requestDecorator,
+	}
+	rtConf.SetRuleTester(tester.test)
+
+	return routersTCP, rtConf
+}
+
+// readTLSFiles returns the contents of the files read by the given TLS configuration, by path.
+func readTLSFiles(conf *dynamic.TLSConfiguration) map[string]string {
+	files := make(map[string]string)
+	if conf == nil {
+		return files
+	}
+
+	read := func(fileOrContent traefiktls.FileOrContent) {
+		if !fileOrContent.IsPath() {
+			return
+		}
+
+		content, err := fileOrContent.Read()
+		if err != nil {
+			log.WithoutContext().Debugf("Unable to read the TLS file %s: %v", fileOrContent, err)
+			return
+		}
+		files[fileOrContent.String()] = string(content)
+	}
+
+	for _, cert := range conf.Certificates {
+		if cert != nil {
+			read(cert.Certificate.CertFile)
+			read(cert.Certificate.KeyFile)
+		}
+	}
+	for _, options := range conf.Options {
+		for _, caFile := range options.ClientAuth.CAFiles {
+			read(caFile)
+		}
+	}
+	for _, store := range conf.Stores {
+		if store.DefaultCertificate != nil {
+			read(store.DefaultCertificate.CertFile)
+			read(store.DefaultCertificate.KeyFile)
+		}
+	}
+
+	return files
 }
 
 // the given configuration must not be nil. its fields will get mutated.
@@ -89,23 +188,32 @@ func (s *Server) createTCPRouters(ctx context.Context, configuration *runtime.Co
 	}
 
 	serviceManager := tcp.NewManager(configuration)
+	serviceManager.SetCache(s.tcpHandlerCache)
 
 	routerManager := routertcp.NewManager(configuration, serviceManager, handlers, handlersTLS, s.tlsManager)
 
 	return routerManager.BuildHandlers(ctx, entryPoints)
 }
 
-// createHTTPHandlers returns, for the given configuration and entryPoints, the HTTP handlers for non-TLS connections, and for the TLS ones. the given configuration must not be nil. its fields will get mutated.
-func (s *Server) createHTTPHandlers(ctx context.Context, configuration *runtime.Configuration, entryPoints []string) (map[string]http.Handler, map[string]http.Handler) {
+// createHTTPHandlers returns, for the given configuration and entryPoints, the HTTP handlers for non-TLS connections, and for the TLS ones,
+// along with the router manager which built them. the given configuration must not be nil. its fields will get mutated.
+func (s *Server) createHTTPHandlers(ctx context.Context, configuration *runtime.Configuration, entryPoints []string) (map[string]http.Handler, map[string]http.Handler, *router.Manager) {
 	var apiHandler http.Handler
 	if s.api != nil {
 		apiHandler = s.api(configuration)
 	}
 
-	serviceManager := service.NewManager(configuration.Services, s.defaultRoundTripper, s.metricsRegistry, s.routinesPool, apiHandler, s.restHandler)
-	middlewaresBuilder := middleware.NewBuilder(configuration.Middlewares, serviceManager)
+	serviceManager := service.NewManager(configuration.Services, s.roundTripperManager, s.metricsRegistry, s.routinesPool, apiHandler, s.restHandler)
+	serviceManager.SetWeightsStore(s.weightsStore)
+	middlewaresBuilder := middleware.NewBuilder(configuration.Middlewares, serviceManager, s.cacheManager)
 	responseModifierFactory := responsemodifiers.NewBuilder(configuration.Middlewares)
 	routerManager := router.NewManager(configuration, serviceManager, middlewaresBuilder, responseModifierFactory)
+	routerManager.SetCache(s.routerHandlerCache)
+	routerManager.SetEntryPointDefaults(s.entryPointDefaults)
+	routerManager.SetForwardedHeaders(s.forwardedHeaders)
+	if s.healthCheckDisabled {
+		routerManager.DisableHealthCheck()
+	}
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, entryPoints, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, entryPoints, true)
@@ -165,7 +273,7 @@ func (s *Server) createHTTPHandlers(ctx context.Context, configuration *runtime.
 		}
 	}
 
-	return routerHandlers, handlersTLS
+	return routerHandlers, handlersTLS, routerManager
 }
 
 func isEmptyConfiguration(conf *dynamic.Configuration) bool {
@@ -213,6 +321,8 @@ func (s *Server) preLoadConfiguration(configMsg dynamic.Message) {
 
 	if isEmptyConfiguration(configMsg.Configuration) {
 		logger.Infof("Skipping empty Configuration for provider %s", configMsg.ProviderName)
+		// There is nothing to apply: the server is as ready as it would be once the configuration loaded.
+		s.notifyReady()
 		return
 	}
 
@@ -2,8 +2,11 @@ package server
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -11,7 +14,10 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/runtime"
 	"github.com/containous/traefik/v2/pkg/config/static"
 	th "github.com/containous/traefik/v2/pkg/testhelpers"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/containous/traefik/v2/pkg/tls/generate"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReuseService(t *testing.T) {
@@ -48,7 +54,7 @@ func TestReuseService(t *testing.T) {
 	srv := NewServer(staticConfig, nil, entryPoints, nil)
 
 	rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: dynamicConfigs})
-	entrypointsHandlers, _ := srv.createHTTPHandlers(context.Background(), rtConf, []string{"http"})
+	entrypointsHandlers, _, _ := srv.createHTTPHandlers(context.Background(), rtConf, []string{"http"})
 
 	// Test that the /ok path returns a status 200.
 	responseRecorderOk := &httptest.ResponseRecorder{}
@@ -120,3 +126,94 @@ func TestThrottleProviderConfigReload(t *testing.T) {
 		t.Error("Last config was not published in time")
 	}
 }
+
+func TestServer_reloadRotatedCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-tls")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeCertificate := func(domain string) {
+		certPEM, keyPEM, err := generate.KeyPair(domain, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(certFile, certPEM, 0o600))
+		require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0o600))
+	}
+
+	tlsManager := traefiktls.NewManager()
+	srv := NewServer(static.Configuration{}, nil, TCPEntryPoints{}, tlsManager)
+
+	configurations := dynamic.Configurations{
+		"file": &dynamic.Configuration{
+			TLS: &dynamic.TLSConfiguration{
+				Stores: map[string]traefiktls.Store{
+					"default": {
+						DefaultCertificate: &traefiktls.Certificate{
+							CertFile: traefiktls.FileOrContent(certFile),
+							KeyFile:  traefiktls.FileOrContent(keyFile),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	writeCertificate("foo.bar")
+	srv.loadConfigurationTCP(configurations)
+
+	defaultCertificate := tlsManager.GetStore("default").DefaultCertificate
+	require.NotNil(t, defaultCertificate)
+
+	// Nothing changed: the TLS configurations are not rebuilt.
+	srv.loadConfigurationTCP(configurations)
+	assert.True(t, defaultCertificate == tlsManager.GetStore("default").DefaultCertificate)
+
+	// The certificate files are rotated, while the configuration stays the same.
+	writeCertificate("bar.foo")
+	srv.loadConfigurationTCP(configurations)
+
+	rotated := tlsManager.GetStore("default").DefaultCertificate
+	require.NotNil(t, rotated)
+	assert.NotEqual(t, defaultCertificate.Certificate, rotated.Certificate)
+}
+
+func TestNewServer_reloadHistorySize(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		api      *static.API
+		expected int
+	}{
+		{
+			desc:     "without API",
+			expected: 10,
+		},
+		{
+			desc:     "unset size",
+			api:      &static.API{},
+			expected: 10,
+		},
+		{
+			desc:     "configured size",
+			api:      &static.API{ReloadHistorySize: 3},
+			expected: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srv := NewServer(static.Configuration{API: test.api}, nil, TCPEntryPoints{}, nil)
+			defer srv.routinesPool.Cleanup()
+
+			for i := 0; i < 20; i++ {
+				srv.reloadHistory.Add("file", &dynamic.Configuration{})
+			}
+
+			assert.Len(t, srv.reloadHistory.Reloads(), test.expected)
+		})
+	}
+}
@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	stdlog "log"
 	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -13,10 +15,13 @@ import (
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/ip"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
 	"github.com/containous/traefik/v2/pkg/middlewares"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodylimit"
 	"github.com/containous/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/tcp"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -52,17 +57,25 @@ type TCPEntryPoints map[string]*TCPEntryPoint
 
 // TCPEntryPoint is the TCP server
 type TCPEntryPoint struct {
+	address                string
 	listener               net.Listener
 	switcher               *tcp.HandlerSwitcher
 	RouteAppenderFactory   RouteAppenderFactory
 	transportConfiguration *static.EntryPointsTransport
 	tracker                *connectionTracker
+	rateLimiter            *connectionRateLimiter
+	rejectedConnsCounter   gokitmetrics.Counter
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	http3Server            *http3server
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint
 func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*TCPEntryPoint, error) {
+	if network, _ := configuration.GetAddress(); configuration.HTTP3 != nil && network != "tcp" {
+		return nil, fmt.Errorf("HTTP/3 is not supported on %s entry points", network)
+	}
+
 	tracker := newConnectionTracker()
 
 	listener, err := buildListener(ctx, configuration)
@@ -84,18 +97,31 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 		return nil, fmt.Errorf("error preparing httpsServer: %v", err)
 	}
 
+	h3server, err := newHTTP3Server(ctx, configuration, listener, httpsServer)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing http3Server: %v", err)
+	}
+
 	router.HTTPSForwarder(httpsServer.Forwarder)
 
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(router)
 
+	rateLimiter, err := newConnectionRateLimiter(configuration.Transport.ConnectionRateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing connection rate limiter: %v", err)
+	}
+
 	return &TCPEntryPoint{
+		address:                configuration.Address,
 		listener:               listener,
 		switcher:               tcpSwitcher,
 		transportConfiguration: configuration.Transport,
 		tracker:                tracker,
+		rateLimiter:            rateLimiter,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
+		http3Server:            h3server,
 	}, nil
 }
 
@@ -122,6 +148,10 @@ func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 		return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
 	case *net.TCPConn:
 		return typedConn, nil
+	case *net.UnixConn:
+		return typedConn, nil
+	case tcp.WriteCloser:
+		return typedConn, nil
 	default:
 		return nil, fmt.Errorf("unknown connection type %T", typedConn)
 	}
@@ -131,6 +161,14 @@ func (e *TCPEntryPoint) startTCP(ctx context.Context) {
 	logger := log.FromContext(ctx)
 	logger.Debugf("Start TCP Server")
 
+	if e.http3Server != nil {
+		go func() {
+			if err := e.http3Server.Start(); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("Error while serving HTTP/3: %v", err)
+			}
+		}()
+	}
+
 	for {
 		conn, err := e.listener.Accept()
 		if err != nil {
@@ -140,12 +178,73 @@ func (e *TCPEntryPoint) startTCP(ctx context.Context) {
 
 		writeCloser, err := writeCloser(conn)
 		if err != nil {
-			panic(err)
+			logger.Errorf("Error while handling connection from %s: %v", conn.RemoteAddr(), err)
+			if err := conn.Close(); err != nil {
+				logger.Debugf("Error while closing connection: %v", err)
+			}
+			continue
 		}
 
+		// The connections are limited before starting the goroutine serving them.
+		if maxConnections := e.transportConfiguration.MaxConnections; maxConnections > 0 && e.tracker.count() >= maxConnections {
+			e.reject(ctx, writeCloser, rejectMaxConnections)
+			continue
+		}
+
+		trackedConn := newTrackedConnection(writeCloser, e.tracker)
 		safe.Go(func() {
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.serveTCP(ctx, trackedConn)
+		})
+	}
+}
+
+// serveTCP enforces the ClientHello timeout and the connection rate limit, before routing the connection.
+func (e *TCPEntryPoint) serveTCP(ctx context.Context, conn tcp.WriteCloser) {
+	// The deadline also covers the PROXY protocol header, read along with the remote address.
+	if timeout := time.Duration(e.transportConfiguration.ClientHelloTimeout); timeout > 0 {
+		helloConn, err := newClientHelloConn(conn, timeout, func() {
+			e.countRejection(rejectClientHelloTimeout)
 		})
+		if err != nil {
+			log.FromContext(ctx).Errorf("Error while setting read deadline on connection from %s: %v", conn.RemoteAddr(), err)
+			e.closeConn(ctx, conn)
+			return
+		}
+		conn = helloConn
+	}
+
+	if e.rateLimiter != nil && !e.rateLimiter.allow(conn.RemoteAddr()) {
+		e.reject(ctx, conn, rejectRateLimit)
+		return
+	}
+
+	e.switcher.ServeTCP(conn)
+}
+
+// setMetricsRegistry sets the registry in which the rejected connections are counted.
+func (e *TCPEntryPoint) setMetricsRegistry(registry metrics.Registry, entryPointName string) {
+	if counter := registry.EntryPointRejectedConnsCounter(); counter != nil {
+		e.rejectedConnsCounter = counter.With("entrypoint", entryPointName)
+	}
+}
+
+// reject closes a connection exceeding the limits of the entry point.
+// The remote address is not logged, as reading it can block on the PROXY protocol header.
+func (e *TCPEntryPoint) reject(ctx context.Context, conn net.Conn, reason string) {
+	log.FromContext(ctx).Debugf("Rejecting connection: %s", reason)
+	e.countRejection(reason)
+	e.closeConn(ctx, conn)
+}
+
+func (e *TCPEntryPoint) countRejection(reason string) {
+	if e.rejectedConnsCounter != nil {
+		e.rejectedConnsCounter.With("reason", reason).Add(1)
+	}
+}
+
+func (e *TCPEntryPoint) closeConn(ctx context.Context, conn net.Conn) {
+	if err := conn.Close(); err != nil {
+		log.FromContext(ctx).Debugf("Error while closing connection: %v", err)
 	}
 }
 
@@ -196,6 +295,20 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 		}()
 	}
 
+	if e.http3Server != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.http3Server.Shutdown(ctx); err != nil {
+				if ctx.Err() == context.DeadlineExceeded {
+					logger.Debugf("Wait HTTP/3 server shutdown is overdue to: %s", err)
+				} else {
+					logger.Error(err)
+				}
+			}
+		}()
+	}
+
 	if e.tracker != nil {
 		wg.Add(1)
 		go func() {
@@ -214,6 +327,10 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 }
 
 func (e *TCPEntryPoint) switchRouter(router *tcp.Router) {
+	if http2Config := e.transportConfiguration.HTTP2; http2Config != nil && !http2Config.Enabled {
+		router.DisableHTTP2()
+	}
+
 	router.HTTPForwarder(e.httpServer.Forwarder)
 	router.HTTPSForwarder(e.httpsServer.Forwarder)
 
@@ -228,6 +345,9 @@ func (e *TCPEntryPoint) switchRouter(router *tcp.Router) {
 
 	e.httpServer.Switcher.UpdateHandler(httpHandler)
 	e.httpsServer.Switcher.UpdateHandler(httpsHandler)
+	if e.http3Server != nil {
+		e.http3Server.Switch(router)
+	}
 	e.switcher.Switch(router)
 }
 
@@ -293,13 +413,22 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 }
 
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.Address)
-
+	// During a hot upgrade, the listener handed over by the previous process is used instead of opening a new one.
+	listener, inherited, err := getInheritedListener(entryPoint.Address)
 	if err != nil {
-		return nil, fmt.Errorf("error opening listener: %v", err)
+		return nil, fmt.Errorf("error retrieving inherited listener: %v", err)
 	}
 
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
+	if !inherited {
+		listener, err = listen(entryPoint)
+		if err != nil {
+			return nil, fmt.Errorf("error opening listener: %v", err)
+		}
+	}
+
+	if tcpListener, ok := listener.(*net.TCPListener); ok {
+		listener = tcpKeepAliveListener{tcpListener}
+	}
 
 	if entryPoint.ProxyProtocol != nil {
 		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
@@ -310,6 +439,29 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 	return listener, nil
 }
 
+func listen(entryPoint *static.EntryPoint) (net.Listener, error) {
+	switch network, address := entryPoint.GetAddress(); network {
+	case "unix":
+		return buildUnixListener(address)
+	case "systemd":
+		return getSystemdListener(address)
+	default:
+		return net.Listen("tcp", address)
+	}
+}
+
+// buildUnixListener listens on the unix socket at the given path,
+// removing the socket left by a previous instance, if any.
+func buildUnixListener(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return net.Listen("unix", path)
+}
+
 func newConnectionTracker() *connectionTracker {
 	return &connectionTracker{
 		conns: make(map[net.Conn]struct{}),
@@ -335,6 +487,12 @@ func (c *connectionTracker) RemoveConnection(conn net.Conn) {
 	delete(c.conns, conn)
 }
 
+func (c *connectionTracker) count() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.conns)
+}
+
 func (c *connectionTracker) isEmpty() bool {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
@@ -394,13 +552,42 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
-	if withH2c {
-		handler = h2c.NewHandler(handler, &http2.Server{})
+	if configuration.Transport.MaxRequestBodyBytes > 0 {
+		handler = bodylimit.New(configuration.Transport.MaxRequestBodyBytes, handler)
+	}
+
+	http2Config := configuration.Transport.HTTP2
+	http2Enabled := http2Config == nil || http2Config.Enabled
+
+	http2Server := &http2.Server{}
+	if http2Config != nil && http2Config.MaxConcurrentStreams > 0 {
+		http2Server.MaxConcurrentStreams = uint32(http2Config.MaxConcurrentStreams)
+	}
+
+	if withH2c && http2Enabled {
+		handler = h2c.NewHandler(handler, http2Server)
 	}
 
 	serverHTTP := &http.Server{
-		Handler:  handler,
-		ErrorLog: httpServerLogger,
+		Handler:        handler,
+		ErrorLog:       httpServerLogger,
+		MaxHeaderBytes: configuration.Transport.MaxHeaderBytes,
+	}
+
+	if timeouts := configuration.Transport.RespondingTimeouts; timeouts != nil {
+		serverHTTP.ReadTimeout = time.Duration(timeouts.ReadTimeout)
+		serverHTTP.ReadHeaderTimeout = time.Duration(timeouts.ReadHeaderTimeout)
+		serverHTTP.WriteTimeout = time.Duration(timeouts.WriteTimeout)
+		serverHTTP.IdleTimeout = time.Duration(timeouts.IdleTimeout)
+	}
+
+	if http2Enabled {
+		if err := http2.ConfigureServer(serverHTTP, http2Server); err != nil {
+			return nil, err
+		}
+	} else {
+		// A non-nil empty map disables HTTP/2 over TLS.
+		serverHTTP.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
 	}
 
 	listener := newHTTPForwarder(ln)
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// http3server serves the HTTPS routers of an entry point over QUIC, on the UDP port of the entry point.
+type http3server struct {
+	*http3.Server
+
+	http3conn net.PacketConn
+
+	lock   sync.RWMutex
+	getter func(info *tls.ClientHelloInfo) (*tls.Config, error)
+
+	// requests is the number of requests being served, awaited on shutdown,
+	// and draining is set once the shutdown started.
+	requestsLock sync.RWMutex
+	requests     int
+	draining     bool
+}
+
+// newHTTP3Server listens on the UDP port of the given TCP listener of the entry point, if HTTP/3 is enabled.
+func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, listener net.Listener, httpsServer *httpServer) (*http3server, error) {
+	if configuration.HTTP3 == nil {
+		return nil, nil
+	}
+
+	// The TCP address is used, for its port to be the same when the entry point listens on a random one.
+	address := listener.Addr().String()
+
+	if configuration.HTTP3.AdvertisedPort < 0 {
+		return nil, errors.New("advertised port must be greater than or equal to zero")
+	}
+
+	conn, err := buildPacketConn(configuration.Address, address)
+	if err != nil {
+		return nil, fmt.Errorf("error opening UDP listener: %v", err)
+	}
+
+	h3 := &http3server{
+		http3conn: conn,
+		getter: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			return nil, errors.New("no TLS config")
+		},
+	}
+
+	serverHTTPS, ok := httpsServer.Server.(*http.Server)
+	if !ok {
+		return nil, fmt.Errorf("unexpected HTTPS server type %T", httpsServer.Server)
+	}
+
+	h3.Server = &http3.Server{
+		Addr:           address,
+		Port:           configuration.HTTP3.AdvertisedPort,
+		Handler:        h3.track(serverHTTPS.Handler),
+		TLSConfig:      &tls.Config{GetConfigForClient: h3.getGetConfigForClient},
+		QuicConfig:     &quic.Config{},
+		MaxHeaderBytes: configuration.Transport.MaxHeaderBytes,
+	}
+
+	// The HTTP/1 and HTTP/2 responses advertise HTTP/3 with the Alt-Svc header.
+	previousHandler := serverHTTPS.Handler
+	serverHTTPS.Handler = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !h3.isDraining() {
+			if err := h3.Server.SetQuicHeaders(rw.Header()); err != nil {
+				log.FromContext(ctx).Debugf("Failed to set the HTTP/3 headers: %v", err)
+			}
+		}
+
+		previousHandler.ServeHTTP(rw, req)
+	})
+
+	return h3, nil
+}
+
+// Start serves HTTP/3 until the server is closed.
+func (e *http3server) Start() error {
+	return e.Serve(e.http3conn)
+}
+
+// Switch uses the TLS configs of the HTTPS routers of the given router.
+func (e *http3server) Switch(rt *tcp.Router) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.getter = rt.GetTLSGetClientInfo()
+}
+
+// Shutdown stops advertising HTTP/3, waits for the requests being served to end, and closes the QUIC connections.
+func (e *http3server) Shutdown(ctx context.Context) error {
+	e.requestsLock.Lock()
+	e.draining = true
+	e.requestsLock.Unlock()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for e.countRequests() > 0 {
+		select {
+		case <-ctx.Done():
+			if err := e.Close(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return e.Close()
+}
+
+func (e *http3server) getGetConfigForClient(info *tls.ClientHelloInfo) (*tls.Config, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	return e.getter(info)
+}
+
+// track counts the requests being served.
+func (e *http3server) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		e.requestsLock.Lock()
+		e.requests++
+		e.requestsLock.Unlock()
+
+		defer func() {
+			e.requestsLock.Lock()
+			e.requests--
+			e.requestsLock.Unlock()
+		}()
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func (e *http3server) countRequests() int {
+	e.requestsLock.RLock()
+	defer e.requestsLock.RUnlock()
+	return e.requests
+}
+
+func (e *http3server) isDraining() bool {
+	e.requestsLock.RLock()
+	defer e.requestsLock.RUnlock()
+	return e.draining
+}
+
+// buildPacketConn returns the UDP listener handed over by the previous process for the given entry point address, if any,
+// and listens on the given UDP address otherwise.
+func buildPacketConn(entryPointAddress, address string) (net.PacketConn, error) {
+	conn, inherited, err := getInheritedPacketConn(entryPointAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving inherited UDP listener: %v", err)
+	}
+
+	if inherited {
+		return conn, nil
+	}
+
+	return net.ListenPacket("udp", address)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/containous/traefik/v2/pkg/tls/generate"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP3(t *testing.T) {
+	entryPoint := startHTTP3EntryPoint(t, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Proto))
+	}))
+	defer entryPoint.listener.Close()
+	defer entryPoint.http3Server.Close()
+
+	_, port, err := net.SplitHostPort(entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		Timeout:   5 * time.Second,
+	}
+	resp, err := client.Get("https://127.0.0.1:" + port)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Alt-Svc"), `h3=":`+port+`"`)
+
+	roundTripper := &http3.RoundTripper{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	defer roundTripper.Close()
+
+	client = &http.Client{Transport: roundTripper, Timeout: 5 * time.Second}
+	resp, err = client.Get("https://127.0.0.1:" + port)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/3.0", string(body))
+}
+
+func TestShutdownHTTP3(t *testing.T) {
+	entryPoint := startHTTP3EntryPoint(t, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(1 * time.Second)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer entryPoint.listener.Close()
+
+	roundTripper := &http3.RoundTripper{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	defer roundTripper.Close()
+
+	client := &http.Client{Transport: roundTripper, Timeout: 5 * time.Second}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		// The request is received before the shutdown starts.
+		time.Sleep(500 * time.Millisecond)
+		shutdown <- entryPoint.http3Server.Shutdown(context.Background())
+	}()
+
+	resp, err := client.Get("https://" + entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, <-shutdown)
+
+	// The server does not advertise HTTP/3 anymore, and the QUIC connections are closed.
+	assert.True(t, entryPoint.http3Server.isDraining())
+	client.Timeout = time.Second
+	_, err = client.Get("https://" + entryPoint.listener.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestHTTP3_unixSocket(t *testing.T) {
+	_, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "unix://" + t.Name() + ".sock",
+		HTTP3:            &static.HTTP3Config{},
+		Transport:        &static.EntryPointsTransport{},
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	assert.EqualError(t, err, "HTTP/3 is not supported on unix entry points")
+}
+
+func startHTTP3EntryPoint(t *testing.T, handler http.Handler) *TCPEntryPoint {
+	t.Helper()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address: "127.0.0.1:0",
+		HTTP3:   &static.HTTP3Config{},
+		Transport: &static.EntryPointsTransport{
+			LifeCycle: &static.LifeCycle{
+				GraceTimeOut: types.Duration(5 * time.Second),
+			},
+		},
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	go entryPoint.startTCP(context.Background())
+
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	router := &tcp.Router{}
+	router.HTTPSHandler(handler, &tls.Config{Certificates: []tls.Certificate{*cert}})
+	entryPoint.switchRouter(router)
+
+	return entryPoint
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/mailgun/ttlmap"
+	"golang.org/x/time/rate"
+)
+
+// The reasons for which a connection is rejected before being routed.
+const (
+	rejectMaxConnections     = "max_connections"
+	rejectRateLimit          = "rate_limit"
+	rejectClientHelloTimeout = "client_hello_timeout"
+)
+
+const maxRateLimitedSources = 65536
+
+// connectionRateLimiter limits the rate of the connections accepted from each source IP,
+// with one token bucket per source.
+type connectionRateLimiter struct {
+	rate  rate.Limit
+	burst int
+	// ttl is the duration, in seconds, after which the bucket of an idle source is full again, and can be forgotten.
+	ttl int
+
+	bucketsMu sync.Mutex
+	buckets   *ttlmap.TtlMap
+}
+
+// newConnectionRateLimiter returns a connectionRateLimiter, or nil if the connections are not limited.
+func newConnectionRateLimiter(config *static.ConnectionRateLimit) (*connectionRateLimiter, error) {
+	if config == nil || config.Average <= 0 {
+		return nil, nil
+	}
+
+	buckets, err := ttlmap.NewMap(maxRateLimitedSources)
+	if err != nil {
+		return nil, err
+	}
+
+	burst := config.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &connectionRateLimiter{
+		rate:    rate.Limit(config.Average),
+		burst:   int(burst),
+		ttl:     int(burst/config.Average) + 1,
+		buckets: buckets,
+	}, nil
+}
+
+// allow reports whether a connection from the given address can be accepted.
+func (l *connectionRateLimiter) allow(addr net.Addr) bool {
+	source := addr.String()
+	if host, _, err := net.SplitHostPort(source); err == nil {
+		source = host
+	}
+
+	l.bucketsMu.Lock()
+	defer l.bucketsMu.Unlock()
+
+	var bucket *rate.Limiter
+	if value, exists := l.buckets.Get(source); exists {
+		bucket = value.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(l.rate, l.burst)
+	}
+
+	// The expiry of the bucket is pushed back on each connection, so that it is not forgotten while not full.
+	if err := l.buckets.Set(source, bucket, l.ttl); err != nil {
+		return true
+	}
+
+	return bucket.Allow()
+}
+
+// clientHelloConn reports when the read deadline of the ClientHello timeout expires on a connection,
+// which is the case until the router clears the read deadline, once the connection is routed.
+type clientHelloConn struct {
+	tcp.WriteCloser
+	deadline  time.Time
+	done      int32
+	onTimeout func()
+}
+
+// newClientHelloConn sets the read deadline of the ClientHello timeout on the connection.
+func newClientHelloConn(conn tcp.WriteCloser, timeout time.Duration, onTimeout func()) (*clientHelloConn, error) {
+	deadline := time.Now().Add(timeout)
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	return &clientHelloConn{
+		WriteCloser: conn,
+		deadline:    deadline,
+		onTimeout:   onTimeout,
+	}, nil
+}
+
+func (c *clientHelloConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if err != nil && !time.Now().Before(c.deadline) && atomic.CompareAndSwapInt32(&c.done, 0, 1) {
+		c.onTimeout()
+	}
+	return n, err
+}
+
+func (c *clientHelloConn) SetReadDeadline(t time.Time) error {
+	atomic.StoreInt32(&c.done, 1)
+	return c.WriteCloser.SetReadDeadline(t)
+}
+
+func (c *clientHelloConn) SetDeadline(t time.Time) error {
+	atomic.StoreInt32(&c.done, 1)
+	return c.WriteCloser.SetDeadline(t)
+}
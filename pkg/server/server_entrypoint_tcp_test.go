@@ -3,14 +3,20 @@ package server
 import (
 	"bufio"
 	"context"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/containous/traefik/v2/pkg/config/static"
 	"github.com/containous/traefik/v2/pkg/tcp"
 	"github.com/containous/traefik/v2/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -78,6 +84,7 @@ func TestShutdownHTTPHijacked(t *testing.T) {
 		err = resp.Write(conn)
 		require.NoError(t, err)
 	}))
+	require.NoError(t, err)
 
 	entryPoint.switchRouter(router)
 
@@ -113,7 +120,7 @@ func TestShutdownTCPConn(t *testing.T) {
 	go entryPoint.startTCP(context.Background())
 
 	router := &tcp.Router{}
-	router.AddCatchAllNoTLS(tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+	err = router.AddRouteNoTLS("foo", "HostSNI(`*`)", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
 		_, err := http.ReadRequest(bufio.NewReader(conn))
 		require.NoError(t, err)
 		time.Sleep(1 * time.Second)
@@ -122,6 +129,7 @@ func TestShutdownTCPConn(t *testing.T) {
 		err = resp.Write(conn)
 		require.NoError(t, err)
 	}))
+	require.NoError(t, err)
 
 	entryPoint.switchRouter(router)
 
@@ -140,3 +148,278 @@ func TestShutdownTCPConn(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, resp.StatusCode, http.StatusOK)
 }
+
+func TestUnixSocketEntryPoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "traefik-entrypoint")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	socket := filepath.Join(dir, "traefik.sock")
+
+	// Leaves a socket behind, as a previous instance killed before closing its listener would.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: socket, Net: "unix"})
+	require.NoError(t, err)
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address: "unix://" + socket,
+		Transport: &static.EntryPointsTransport{
+			LifeCycle: &static.LifeCycle{
+				GraceTimeOut: types.Duration(5 * time.Second),
+			},
+		},
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+	defer entryPoint.listener.Close()
+
+	go entryPoint.startTCP(context.Background())
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	entryPoint.switchRouter(router)
+
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	request, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	require.NoError(t, err)
+
+	err = request.Write(conn)
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestWriteCloser(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcpListener.Close()
+
+	tcpConn, err := net.Dial("tcp", tcpListener.Addr().String())
+	require.NoError(t, err)
+	defer tcpConn.Close()
+
+	_, err = writeCloser(tcpConn)
+	assert.NoError(t, err)
+
+	pipeConn, _ := net.Pipe()
+	defer pipeConn.Close()
+
+	_, err = writeCloser(pipeConn)
+	assert.Error(t, err)
+}
+
+func TestClientHelloTimeout(t *testing.T) {
+	entryPoint, rejections := startLimitedEntryPoint(t, &static.EntryPointsTransport{
+		ClientHelloTimeout: types.Duration(100 * time.Millisecond),
+	})
+	defer entryPoint.listener.Close()
+
+	// The timeout does not apply anymore once the connection is routed.
+	conn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assertRequest(t, conn)
+	time.Sleep(200 * time.Millisecond)
+	assertRequest(t, conn)
+
+	idleConn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer idleConn.Close()
+
+	assertClosed(t, idleConn)
+	assert.Equal(t, map[string]float64{rejectClientHelloTimeout: 1}, rejections.values())
+}
+
+func TestMaxConnections(t *testing.T) {
+	entryPoint, rejections := startLimitedEntryPoint(t, &static.EntryPointsTransport{
+		MaxConnections: 1,
+	})
+	defer entryPoint.listener.Close()
+
+	conn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assertRequest(t, conn)
+
+	rejectedConn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer rejectedConn.Close()
+
+	assertClosed(t, rejectedConn)
+	assert.Equal(t, map[string]float64{rejectMaxConnections: 1}, rejections.values())
+
+	require.NoError(t, conn.Close())
+
+	// Closing the first connection makes room for a new one.
+	assert.Eventually(t, func() bool { return entryPoint.tracker.isEmpty() }, 5*time.Second, 10*time.Millisecond)
+
+	newConn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer newConn.Close()
+
+	assertRequest(t, newConn)
+}
+
+func TestConnectionRateLimit(t *testing.T) {
+	entryPoint, rejections := startLimitedEntryPoint(t, &static.EntryPointsTransport{
+		ConnectionRateLimit: &static.ConnectionRateLimit{Average: 1, Burst: 2},
+	})
+	defer entryPoint.listener.Close()
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+		require.NoError(t, err)
+
+		assertRequest(t, conn)
+		require.NoError(t, conn.Close())
+	}
+
+	rejectedConn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	defer rejectedConn.Close()
+
+	assertClosed(t, rejectedConn)
+	assert.Equal(t, map[string]float64{rejectRateLimit: 1}, rejections.values())
+}
+
+func startLimitedEntryPoint(t *testing.T, transport *static.EntryPointsTransport) (*TCPEntryPoint, *rejectionsCounter) {
+	t.Helper()
+
+	transport.LifeCycle = &static.LifeCycle{
+		GraceTimeOut: types.Duration(5 * time.Second),
+	}
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        transport,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	rejections := &rejectionsCounter{lock: &sync.Mutex{}, counts: make(map[string]float64)}
+	entryPoint.rejectedConnsCounter = rejections
+
+	go entryPoint.startTCP(context.Background())
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	entryPoint.switchRouter(router)
+
+	return entryPoint, rejections
+}
+
+func assertRequest(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	request, err := http.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, request.Write(conn))
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func assertClosed(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	_, err := conn.Read(make([]byte, 1))
+	require.Error(t, err)
+
+	netErr, ok := err.(net.Error)
+	assert.False(t, ok && netErr.Timeout(), "the connection was not closed by the entry point")
+}
+
+// rejectionsCounter counts the rejected connections by reason.
+type rejectionsCounter struct {
+	lock   *sync.Mutex
+	reason string
+	counts map[string]float64
+}
+
+func (c *rejectionsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	return &rejectionsCounter{lock: c.lock, reason: strings.Join(labelValues[1:], ","), counts: c.counts}
+}
+
+func (c *rejectionsCounter) Add(delta float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts[c.reason] += delta
+}
+
+func (c *rejectionsCounter) values() map[string]float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	values := make(map[string]float64, len(c.counts))
+	for reason, count := range c.counts {
+		values[reason] = count
+	}
+	return values
+}
+
+func TestHTTPServerLimits(t *testing.T) {
+	entryPoint, _ := startLimitedEntryPoint(t, &static.EntryPointsTransport{
+		MaxHeaderBytes:      1024,
+		MaxRequestBodyBytes: 10,
+	})
+	defer entryPoint.listener.Close()
+
+	testCases := []struct {
+		desc         string
+		header       string
+		body         string
+		expectedCode int
+	}{
+		{
+			desc:         "within the limits",
+			header:       "foo",
+			body:         "0123456789",
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "headers too large",
+			header:       strings.Repeat("a", 10*1024),
+			expectedCode: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			desc:         "body too large",
+			header:       "foo",
+			body:         "0123456789a",
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			conn, err := net.Dial("tcp", entryPoint.listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			request, err := http.NewRequest(http.MethodPost, "http://127.0.0.1", strings.NewReader(test.body))
+			require.NoError(t, err)
+			request.Header.Set("X-Foo", test.header)
+
+			require.NoError(t, request.Write(conn))
+
+			resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCode, resp.StatusCode)
+		})
+	}
+}
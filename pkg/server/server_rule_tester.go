@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/containous/traefik/v2/pkg/rules"
+	"github.com/containous/traefik/v2/pkg/server/internal"
+	"github.com/containous/traefik/v2/pkg/server/router"
+	tcpCore "github.com/containous/traefik/v2/pkg/tcp"
+)
+
+// ruleTester evaluates synthetic requests against the routers built from a configuration,
+// in the order the entry points evaluate them: the TCP routers, the HTTP routers, and then the redirection of the entry point.
+type ruleTester struct {
+	entryPoints      TCPEntryPoints
+	rtConf           *runtime.Configuration
+	routerManager    *router.Manager
+	routersTCP       map[string]*tcpCore.Router
+	requestDecorator *requestdecorator.RequestDecorator
+}
+
+func (t *ruleTester) test(request runtime.RuleTestRequest) (*runtime.RuleTestResult, error) {
+	if _, ok := t.entryPoints[request.EntryPoint]; !ok {
+		return nil, fmt.Errorf("entry point %q does not exist", request.EntryPoint)
+	}
+
+	isTLS := request.TLS || request.SNI != ""
+
+	result := &runtime.RuleTestResult{
+		EntryPoint: request.EntryPoint,
+		TLS:        isTLS,
+	}
+
+	if routerTCP, ok := t.routersTCP[request.EntryPoint]; ok {
+		connData := rules.ConnData{
+			ServerName: strings.ToLower(request.SNI),
+			RemoteIP:   request.ClientIP,
+		}
+		if t.evaluate(result, "tcp", routerTCP.Explain(connData, isTLS), request.Verbose) {
+			return result, nil
+		}
+	}
+
+	if rulesRouter := t.routerManager.RulesRouter(request.EntryPoint, isTLS); rulesRouter != nil {
+		req, err := t.newRequest(request, isTLS)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.evaluate(result, "http", rulesRouter.Explain(req), request.Verbose) {
+			return result, nil
+		}
+	}
+
+	if !isTLS {
+		result.RedirectTo = t.routerManager.RedirectTo(request.EntryPoint)
+	}
+
+	return result, nil
+}
+
+// evaluate fills the result with the first matching router, and tells whether there is one.
+// When verbose, the routers evaluated until the matching one are added to the result.
+func (t *ruleTester) evaluate(result *runtime.RuleTestResult, protocol string, matches []rules.RouteMatch, verbose bool) bool {
+	for _, match := range matches {
+		if verbose {
+			result.Routers = append(result.Routers, runtime.RuleTestRouter{
+				Name:     match.Name,
+				Protocol: protocol,
+				Rule:     match.Rule,
+				Priority: match.Priority,
+				Matched:  match.Matched,
+				Reasons:  match.Reasons,
+			})
+		}
+
+		if !match.Matched {
+			continue
+		}
+
+		result.Protocol = protocol
+		result.Router = match.Name
+		result.Rule = match.Rule
+		result.Priority = match.Priority
+
+		ctx := internal.AddProviderInContext(context.Background(), match.Name)
+		if protocol == "tcp" {
+			if routerInfo, ok := t.rtConf.TCPRouters[match.Name]; ok {
+				result.Service = internal.GetQualifiedName(ctx, routerInfo.Service)
+			}
+			return true
+		}
+
+		if routerInfo, ok := t.rtConf.Routers[match.Name]; ok {
+			result.Middlewares = routerInfo.Middlewares
+			result.Service = internal.GetQualifiedName(ctx, routerInfo.Service)
+		}
+		return true
+	}
+
+	return false
+}
+
+// newRequest builds the HTTP request described by the synthetic one, as received by the HTTP routers.
+func (t *ruleTester) newRequest(request runtime.RuleTestRequest, isTLS bool) (*http.Request, error) {
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	path := request.Path
+	if path == "" {
+		path = "/"
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(method), path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+	req.RequestURI = path
+
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Host = request.Host
+	if req.Host == "" {
+		req.Host = req.Header.Get("Host")
+	}
+
+	if request.ClientIP != "" {
+		req.RemoteAddr = net.JoinHostPort(request.ClientIP, "0")
+	}
+
+	if isTLS {
+		req.TLS = &tls.ConnectionState{ServerName: request.SNI}
+	}
+
+	// The request decorator canonizes the host for the Host matcher.
+	t.requestDecorator.ServeHTTP(nil, req, func(_ http.ResponseWriter, decorated *http.Request) {
+		req = decorated
+	})
+
+	return req, nil
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleTester(t *testing.T) {
+	staticConfiguration := static.Configuration{
+		EntryPoints: static.EntryPoints{
+			"web": {
+				Address: ":80",
+				HTTP: &static.HTTPConfig{
+					Redirections: &static.Redirections{
+						EntryPoint: &static.RedirectEntryPoint{To: "websecure", Scheme: "https", Permanent: true},
+					},
+				},
+			},
+			"websecure": {Address: ":443"},
+			"internal":  {Address: ":8080"},
+		},
+	}
+
+	entryPoints := make(TCPEntryPoints)
+	for entryPointName := range staticConfiguration.EntryPoints {
+		entryPoints[entryPointName] = &TCPEntryPoint{}
+	}
+
+	s := NewServer(staticConfiguration, nil, entryPoints, traefiktls.NewManager())
+	defer s.routinesPool.Cleanup()
+
+	_, rtConf := s.loadConfigurationTCP(dynamic.Configurations{
+		"file": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"api": {
+						EntryPoints: []string{"websecure", "internal"},
+						Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+						Service:     "api",
+						Middlewares: []string{"auth"},
+						TLS:         &dynamic.RouterTLSConfig{},
+					},
+					"front": {
+						EntryPoints: []string{"websecure"},
+						Rule:        "Host(`foo.bar`)",
+						Service:     "front",
+						TLS:         &dynamic.RouterTLSConfig{},
+					},
+					"plain": {
+						EntryPoints: []string{"web"},
+						Rule:        "Host(`plain.foo.bar`)",
+						Service:     "front",
+					},
+					"internal": {
+						EntryPoints: []string{"internal"},
+						Rule:        "PathPrefix(`/`)",
+						Service:     "front",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth": {BasicAuth: &dynamic.BasicAuth{Users: []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}}},
+				},
+				Services: map[string]*dynamic.Service{
+					"api": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: "http://127.0.0.1:8081"}}},
+					},
+					"front": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: "http://127.0.0.1:8082"}}},
+					},
+				},
+			},
+			TCP: &dynamic.TCPConfiguration{
+				Routers: map[string]*dynamic.TCPRouter{
+					"db": {
+						EntryPoints: []string{"websecure"},
+						Rule:        "HostSNI(`db.foo.bar`)",
+						Service:     "db",
+						TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+					},
+				},
+				Services: map[string]*dynamic.TCPService{
+					"db": {
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{Servers: []dynamic.TCPServer{{Address: "127.0.0.1:5432"}}},
+					},
+				},
+			},
+		},
+	})
+
+	testCases := []struct {
+		desc          string
+		request       runtime.RuleTestRequest
+		expected      *runtime.RuleTestResult
+		expectedError bool
+	}{
+		{
+			desc:    "HTTP router",
+			request: runtime.RuleTestRequest{EntryPoint: "websecure", Host: "foo.bar", Path: "/api/users", SNI: "foo.bar"},
+			expected: &runtime.RuleTestResult{
+				EntryPoint:  "websecure",
+				TLS:         true,
+				Protocol:    "http",
+				Router:      "api@file",
+				Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+				Priority:    37,
+				Middlewares: []string{"auth@file"},
+				Service:     "api@file",
+			},
+		},
+		{
+			desc:    "HTTP router, verbose",
+			request: runtime.RuleTestRequest{EntryPoint: "websecure", Host: "foo.bar", Path: "/", SNI: "foo.bar", Verbose: true},
+			expected: &runtime.RuleTestResult{
+				EntryPoint: "websecure",
+				TLS:        true,
+				Protocol:   "http",
+				Router:     "front@file",
+				Rule:       "Host(`foo.bar`)",
+				Priority:   15,
+				Service:    "front@file",
+				Routers: []runtime.RuleTestRouter{
+					{
+						Name:     "db@file",
+						Protocol: "tcp",
+						Rule:     "HostSNI(`db.foo.bar`)",
+						Priority: 21,
+						Reasons:  []string{"HostSNI(`db.foo.bar`) does not match"},
+					},
+					{
+						Name:     "api@file",
+						Protocol: "http",
+						Rule:     "Host(`foo.bar`) && PathPrefix(`/api`)",
+						Priority: 37,
+						Reasons:  []string{"PathPrefix(`/api`) does not match"},
+					},
+					{
+						Name:     "front@file",
+						Protocol: "http",
+						Rule:     "Host(`foo.bar`)",
+						Priority: 15,
+						Matched:  true,
+					},
+				},
+			},
+		},
+		{
+			desc:    "TCP router",
+			request: runtime.RuleTestRequest{EntryPoint: "websecure", SNI: "db.foo.bar"},
+			expected: &runtime.RuleTestResult{
+				EntryPoint: "websecure",
+				TLS:        true,
+				Protocol:   "tcp",
+				Router:     "db@file",
+				Rule:       "HostSNI(`db.foo.bar`)",
+				Priority:   21,
+				Service:    "db@file",
+			},
+		},
+		{
+			desc:    "redirection",
+			request: runtime.RuleTestRequest{EntryPoint: "web", Host: "foo.bar"},
+			expected: &runtime.RuleTestResult{
+				EntryPoint: "web",
+				RedirectTo: "websecure",
+			},
+		},
+		{
+			desc:    "router of the redirecting entry point",
+			request: runtime.RuleTestRequest{EntryPoint: "web", Host: "plain.foo.bar"},
+			expected: &runtime.RuleTestResult{
+				EntryPoint: "web",
+				Protocol:   "http",
+				Router:     "plain@file",
+				Rule:       "Host(`plain.foo.bar`)",
+				Priority:   21,
+				Service:    "front@file",
+			},
+		},
+		{
+			desc:    "no matching router",
+			request: runtime.RuleTestRequest{EntryPoint: "websecure", Host: "bar.foo", SNI: "bar.foo"},
+			expected: &runtime.RuleTestResult{
+				EntryPoint: "websecure",
+				TLS:        true,
+			},
+		},
+		{
+			desc:          "unknown entry point",
+			request:       runtime.RuleTestRequest{EntryPoint: "nope"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			result, err := rtConf.TestRule(test.request)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
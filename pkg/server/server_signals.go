@@ -10,7 +10,7 @@ import (
 )
 
 func (s *Server) configureSignals() {
-	signal.Notify(s.signals, syscall.SIGUSR1)
+	signal.Notify(s.signals, syscall.SIGUSR1, syscall.SIGUSR2)
 }
 
 func (s *Server) listenSignals(stop chan bool) {
@@ -32,6 +32,14 @@ func (s *Server) listenSignals(stop chan bool) {
 					log.WithoutContext().Errorf("Error rotating traefik log: %v", err)
 				}
 			}
+
+			if sig == syscall.SIGUSR2 {
+				log.WithoutContext().Infof("Handing the entry points over to a new process: %+v", sig)
+
+				if err := s.upgrade(); err != nil {
+					log.WithoutContext().Errorf("Error upgrading: %v", err)
+				}
+			}
 		}
 	}
 }
@@ -0,0 +1,42 @@
+// +build !windows
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/coreos/go-systemd/activation"
+)
+
+var (
+	systemdListenersOnce sync.Once
+	systemdListenersLock sync.Mutex
+	systemdListeners     map[string][]net.Listener
+	systemdListenersErr  error
+)
+
+// getSystemdListener returns a listener on the socket passed by systemd under the given name (FileDescriptorName),
+// each socket being returned only once.
+func getSystemdListener(name string) (net.Listener, error) {
+	// The sockets passed by systemd can only be retrieved once, as the environment describing them is then unset.
+	systemdListenersOnce.Do(func() {
+		systemdListeners, systemdListenersErr = activation.ListenersWithNames()
+	})
+
+	if systemdListenersErr != nil {
+		return nil, systemdListenersErr
+	}
+
+	systemdListenersLock.Lock()
+	defer systemdListenersLock.Unlock()
+
+	listeners := systemdListeners[name]
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("no socket named %q passed by systemd", name)
+	}
+
+	systemdListeners[name] = listeners[1:]
+	return listeners[0], nil
+}
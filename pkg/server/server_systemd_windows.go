@@ -0,0 +1,12 @@
+// +build windows
+
+package server
+
+import (
+	"errors"
+	"net"
+)
+
+func getSystemdListener(name string) (net.Listener, error) {
+	return nil, errors.New("systemd socket activation is not supported on Windows")
+}
@@ -255,7 +255,7 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 
 			srv := NewServer(globalConfig, nil, entryPointsConfig, nil)
 			rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: test.config(testServer.URL)})
-			entryPoints, _ := srv.createHTTPHandlers(context.Background(), rtConf, []string{"http"})
+			entryPoints, _, _ := srv.createHTTPHandlers(context.Background(), rtConf, []string{"http"})
 
 			responseRecorder := &httptest.ResponseRecorder{}
 			request := httptest.NewRequest(http.MethodGet, testServer.URL+requestPath, nil)
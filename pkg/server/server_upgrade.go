@@ -0,0 +1,289 @@
+// +build !windows
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	proxyprotocol "github.com/c0va23/go-proxyprotocol"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/coreos/go-systemd/daemon"
+)
+
+const (
+	// envUpgradeListeners holds the file descriptors of the listeners handed over by the previous process,
+	// as a JSON object keyed by entry point address (prefixed with udpUpgradePrefix for the HTTP/3 listeners).
+	envUpgradeListeners = "TRAEFIK_UPGRADE_LISTENERS"
+	// envUpgradeReadyFD holds the file descriptor on which the new process reports its readiness.
+	envUpgradeReadyFD = "TRAEFIK_UPGRADE_READY_FD"
+
+	udpUpgradePrefix = "udp:"
+
+	upgradeReadyTimeout = 30 * time.Second
+)
+
+var (
+	upgradeOnce      sync.Once
+	upgradeLock      sync.Mutex
+	upgradeListeners map[string]*os.File
+	upgradeReady     *os.File
+)
+
+// loadUpgradeFiles retrieves, once, the files handed over by the previous process, and unsets the environment describing them.
+func loadUpgradeFiles() {
+	upgradeOnce.Do(func() {
+		logger := log.WithoutContext()
+
+		if raw, ok := os.LookupEnv(envUpgradeListeners); ok {
+			listeners, err := parseUpgradeListeners(raw)
+			if err != nil {
+				logger.Errorf("Unable to retrieve the listeners handed over by the previous process: %v", err)
+			}
+			upgradeListeners = listeners
+		}
+
+		if raw, ok := os.LookupEnv(envUpgradeReadyFD); ok {
+			fd, err := strconv.Atoi(raw)
+			if err != nil {
+				logger.Errorf("Invalid %s: %v", envUpgradeReadyFD, err)
+			} else {
+				upgradeReady = os.NewFile(uintptr(fd), "upgrade-ready")
+			}
+		}
+
+		_ = os.Unsetenv(envUpgradeListeners)
+		_ = os.Unsetenv(envUpgradeReadyFD)
+	})
+}
+
+func parseUpgradeListeners(raw string) (map[string]*os.File, error) {
+	fds := map[string]int{}
+	if err := json.Unmarshal([]byte(raw), &fds); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*os.File, len(fds))
+	for address, fd := range fds {
+		files[address] = os.NewFile(uintptr(fd), address)
+	}
+	return files, nil
+}
+
+// getInheritedListener returns the listener handed over by the previous process for the given entry point address, if any.
+func getInheritedListener(address string) (net.Listener, bool, error) {
+	file, ok := takeUpgradeListener(address)
+	if !ok {
+		return nil, false, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, false, err
+	}
+	return listener, true, nil
+}
+
+// getInheritedPacketConn returns the HTTP/3 listener handed over by the previous process for the given entry point address, if any.
+func getInheritedPacketConn(address string) (net.PacketConn, bool, error) {
+	file, ok := takeUpgradeListener(udpUpgradePrefix + address)
+	if !ok {
+		return nil, false, nil
+	}
+	defer func() { _ = file.Close() }()
+
+	conn, err := net.FilePacketConn(file)
+	if err != nil {
+		return nil, false, err
+	}
+	return conn, true, nil
+}
+
+// takeUpgradeListener removes, and returns, the file of the listener handed over by the previous process for the given key.
+func takeUpgradeListener(key string) (*os.File, bool) {
+	loadUpgradeFiles()
+
+	upgradeLock.Lock()
+	defer upgradeLock.Unlock()
+
+	file, ok := upgradeListeners[key]
+	if ok {
+		delete(upgradeListeners, key)
+	}
+	return file, ok
+}
+
+// notifyUpgradeReady reports to the previous process, if any, that the entry points serve the configuration of the providers,
+// and closes the listeners it handed over that are not used anymore.
+func notifyUpgradeReady() {
+	loadUpgradeFiles()
+
+	upgradeLock.Lock()
+	defer upgradeLock.Unlock()
+
+	for address, file := range upgradeListeners {
+		log.WithoutContext().Infof("Closing the listener on %s handed over by the previous process, as no entry point uses it", address)
+		_ = file.Close()
+	}
+	upgradeListeners = nil
+
+	if upgradeReady == nil {
+		return
+	}
+
+	if _, err := upgradeReady.Write([]byte{1}); err != nil {
+		log.WithoutContext().Errorf("Unable to report readiness to the previous process: %v", err)
+	}
+	_ = upgradeReady.Close()
+	upgradeReady = nil
+}
+
+// upgrade starts a new process of the current binary, hands the listeners of the entry points over to it,
+// and waits for it to be ready. The server is then stopped, draining the connections as during a regular shutdown.
+func (s *Server) upgrade() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	fds := make(map[string]int)
+	for entryPointName, entryPoint := range s.entryPointsTCP {
+		file, err := listenerFile(entryPoint.listener)
+		if err != nil {
+			return fmt.Errorf("unable to hand the listener of the entry point %s over: %v", entryPointName, err)
+		}
+
+		// The extra files of the new process start after stdin, stdout and stderr.
+		fds[entryPoint.address] = 3 + len(files)
+		files = append(files, file)
+
+		if entryPoint.http3Server == nil {
+			continue
+		}
+
+		file, err = packetConnFile(entryPoint.http3Server.http3conn)
+		if err != nil {
+			return fmt.Errorf("unable to hand the HTTP/3 listener of the entry point %s over: %v", entryPointName, err)
+		}
+
+		fds[udpUpgradePrefix+entryPoint.address] = 3 + len(files)
+		files = append(files, file)
+	}
+
+	rawFDs, err := json.Marshal(fds)
+	if err != nil {
+		return err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = readyReader.Close() }()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	cmd.Env = append(os.Environ(),
+		envUpgradeListeners+"="+string(rawFDs),
+		envUpgradeReadyFD+"="+strconv.Itoa(3+len(files)),
+	)
+
+	err = cmd.Start()
+	_ = readyWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	log.WithoutContext().Infof("Started the new process %d, waiting for it to be ready", cmd.Process.Pid)
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := readyReader.Read(make([]byte, 1))
+		ready <- err
+	}()
+
+	select {
+	case err = <-ready:
+		if err != nil {
+			err = fmt.Errorf("the new process exited before being ready: %v", err)
+		}
+	case <-time.After(upgradeReadyTimeout):
+		err = fmt.Errorf("the new process was not ready after %s", upgradeReadyTimeout)
+	}
+
+	if err != nil {
+		if errKill := cmd.Process.Kill(); errKill != nil {
+			log.WithoutContext().Debugf("Error while killing the new process: %v", errKill)
+		}
+		_ = cmd.Wait()
+		return err
+	}
+
+	if _, err := daemon.SdNotify(false, fmt.Sprintf("MAINPID=%d", cmd.Process.Pid)); err != nil {
+		log.WithoutContext().Errorf("Failed to notify the new main process: %v", err)
+	}
+
+	// The new process now serves on the unix sockets, which must outlive the current listeners.
+	for _, entryPoint := range s.entryPointsTCP {
+		if unixListener, ok := unwrapListener(entryPoint.listener).(*net.UnixListener); ok {
+			unixListener.SetUnlinkOnClose(false)
+		}
+	}
+
+	log.WithoutContext().Infof("The new process %d is ready, stopping", cmd.Process.Pid)
+	s.cancel()
+
+	return nil
+}
+
+// listenerFile returns a copy of the file descriptor of the given listener.
+func listenerFile(listener net.Listener) (*os.File, error) {
+	switch typedListener := unwrapListener(listener).(type) {
+	case *net.TCPListener:
+		return typedListener.File()
+	case *net.UnixListener:
+		return typedListener.File()
+	default:
+		return nil, fmt.Errorf("unknown listener type %T", typedListener)
+	}
+}
+
+// packetConnFile returns a copy of the file descriptor of the given UDP listener.
+func packetConnFile(conn net.PacketConn) (*os.File, error) {
+	udpConn, ok := conn.(*net.UDPConn)
+	if !ok {
+		return nil, fmt.Errorf("unknown listener type %T", conn)
+	}
+	return udpConn.File()
+}
+
+// unwrapListener returns the listener underlying the keep-alive and proxy protocol listeners.
+func unwrapListener(listener net.Listener) net.Listener {
+	for {
+		switch typedListener := listener.(type) {
+		case proxyprotocol.Listener:
+			listener = typedListener.Listener
+		case tcpKeepAliveListener:
+			return typedListener.TCPListener
+		default:
+			return listener
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// +build !windows
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/static"
+	"github.com/containous/traefik/v2/pkg/safe"
+	th "github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/containous/traefik/v2/pkg/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeHandover(t *testing.T) {
+	loadUpgradeFiles()
+
+	entryPoint := &static.EntryPoint{
+		Address:       "127.0.0.1:0",
+		ProxyProtocol: &static.ProxyProtocol{Insecure: true},
+	}
+
+	previous, err := buildListener(context.Background(), entryPoint)
+	require.NoError(t, err)
+
+	file, err := listenerFile(previous)
+	require.NoError(t, err)
+
+	address := previous.Addr().String()
+	require.NoError(t, previous.Close())
+
+	// A file handed over for an address no entry point uses anymore.
+	unused, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+
+	upgradeListeners, err = parseUpgradeListeners(fmt.Sprintf(`{%q: %d, "127.0.0.1:1": %d}`, address, file.Fd(), unused.Fd()))
+	require.NoError(t, err)
+
+	readyReader, readyWriter, err := os.Pipe()
+	require.NoError(t, err)
+	defer readyReader.Close()
+
+	upgradeReady = readyWriter
+
+	listener, err := buildListener(context.Background(), &static.EntryPoint{Address: address})
+	require.NoError(t, err)
+	defer listener.Close()
+
+	assert.Equal(t, address, listener.Addr().String())
+
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	accepted, err := listener.Accept()
+	require.NoError(t, err)
+	require.NoError(t, accepted.Close())
+
+	notifyUpgradeReady()
+
+	assert.Nil(t, upgradeListeners)
+	assert.Nil(t, upgradeReady)
+
+	ready := make([]byte, 1)
+	_, err = readyReader.Read(ready)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, ready)
+}
+
+// channelProvider provides the configurations sent on its channel, until it is closed.
+type channelProvider struct {
+	messages chan dynamic.Message
+	empty    bool
+}
+
+func (p channelProvider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	go func() {
+		for message := range p.messages {
+			configurationChan <- message
+		}
+	}()
+	return nil
+}
+
+func (p channelProvider) Init() error {
+	return nil
+}
+
+func (p channelProvider) Empty() bool {
+	return p.empty
+}
+
+func TestServer_upgradeReady(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		provider channelProvider
+		messages []dynamic.Message
+	}{
+		{
+			desc:     "after the first configuration of the providers",
+			provider: channelProvider{messages: make(chan dynamic.Message)},
+			messages: []dynamic.Message{{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(
+						th.WithRouters(th.WithRouter("foo", th.WithServiceName("bar"))),
+						th.WithLoadBalancerServices(th.WithService("bar")),
+					),
+				},
+			}},
+		},
+		{
+			desc:     "after an empty configuration of the providers",
+			provider: channelProvider{messages: make(chan dynamic.Message)},
+			messages: []dynamic.Message{{ProviderName: "mock", Configuration: &dynamic.Configuration{}}},
+		},
+		{
+			desc:     "without providers",
+			provider: channelProvider{messages: make(chan dynamic.Message), empty: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			loadUpgradeFiles()
+
+			readyReader, readyWriter, err := os.Pipe()
+			require.NoError(t, err)
+			defer readyReader.Close()
+
+			// Reset once the readiness is reported.
+			upgradeReady = readyWriter
+
+			ready := make(chan struct{})
+			go func() {
+				_, _ = readyReader.Read(make([]byte, 1))
+				close(ready)
+			}()
+
+			server := NewServer(static.Configuration{}, test.provider, TCPEntryPoints{}, tls.NewManager())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			server.Start(ctx)
+			defer func() {
+				close(test.provider.messages)
+				cancel()
+				server.Wait()
+				// The stop channel is closed once the server is closed.
+				<-server.stopChan
+			}()
+
+			if len(test.messages) > 0 {
+				select {
+				case <-ready:
+					t.Fatal("The server must not be ready before the configuration of the providers is loaded")
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
+			for _, message := range test.messages {
+				test.provider.messages <- message
+			}
+
+			select {
+			case <-ready:
+			case <-time.After(5 * time.Second):
+				t.Fatal("The server is not ready")
+			}
+
+			if len(test.messages) > 0 && !isEmptyConfiguration(test.messages[0].Configuration) {
+				configurations := server.currentConfigurations.Get().(dynamic.Configurations)
+				assert.Contains(t, configurations, "mock")
+			}
+		})
+	}
+}
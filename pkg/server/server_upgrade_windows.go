@@ -0,0 +1,15 @@
+// +build windows
+
+package server
+
+import "net"
+
+func getInheritedListener(address string) (net.Listener, bool, error) {
+	return nil, false, nil
+}
+
+func getInheritedPacketConn(address string) (net.PacketConn, bool, error) {
+	return nil, false, nil
+}
+
+func notifyUpgradeReady() {}
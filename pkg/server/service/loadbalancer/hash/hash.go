@@ -0,0 +1,162 @@
+package hash
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/hashring"
+	"github.com/vulcand/oxy/roundrobin"
+	"github.com/vulcand/oxy/utils"
+)
+
+// KeyFunc returns the key of the request used to pick a server on the ring,
+// or an empty string if the request does not hold any.
+type KeyFunc func(req *http.Request) string
+
+// NewKeyFunc returns the key function of the given sticky configuration,
+// or nil if the sticky configuration does not use a consistent hash (no stickiness, or cookie-based).
+func NewKeyFunc(sticky *dynamic.Sticky) (KeyFunc, error) {
+	if sticky == nil {
+		return nil, nil
+	}
+
+	var count int
+	for _, set := range []bool{sticky.Cookie != nil, sticky.Header != nil, sticky.Query != nil, sticky.SourceIP != nil} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return nil, errors.New("only one of cookie, header, query or sourceIP sticky sessions can be set")
+	}
+
+	switch {
+	case sticky.Header != nil:
+		if sticky.Header.Name == "" {
+			return nil, errors.New("the header name of the sticky sessions is required")
+		}
+
+		name := sticky.Header.Name
+		return func(req *http.Request) string {
+			return req.Header.Get(name)
+		}, nil
+
+	case sticky.Query != nil:
+		if sticky.Query.Name == "" {
+			return nil, errors.New("the query parameter name of the sticky sessions is required")
+		}
+
+		name := sticky.Query.Name
+		return func(req *http.Request) string {
+			return req.URL.Query().Get(name)
+		}, nil
+
+	case sticky.SourceIP != nil:
+		strategy, err := sticky.SourceIP.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		return strategy.GetIP, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// Balancer is a load-balancer of servers sending the requests with the same key to the same server,
+// using a consistent hash ring of the servers.
+// The requests without key are load-balanced with round robin.
+type Balancer struct {
+	*roundrobin.RoundRobin
+
+	next http.Handler
+	key  KeyFunc
+	ring *hashring.Ring
+
+	lock    sync.RWMutex
+	servers map[string]*url.URL
+}
+
+// New creates a new load-balancer forwarding the requests to next.
+func New(next http.Handler, key KeyFunc) (*Balancer, error) {
+	rr, err := roundrobin.New(next)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Balancer{
+		RoundRobin: rr,
+		next:       next,
+		key:        key,
+		ring:       hashring.New(),
+		servers:    make(map[string]*url.URL),
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key := b.key(req)
+	if key == "" {
+		b.RoundRobin.ServeHTTP(rw, req)
+		return
+	}
+
+	u, ok := b.server(key)
+	if !ok {
+		// No server: the round robin answers the error.
+		b.RoundRobin.ServeHTTP(rw, req)
+		return
+	}
+
+	newReq := *req
+	newReq.URL = u
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// UpsertServer adds the server, or updates its options.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if err := b.RoundRobin.UpsertServer(u, options...); err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	b.servers[u.String()] = utils.CopyURL(u)
+	b.lock.Unlock()
+
+	b.ring.Add(u.String(), 1)
+	return nil
+}
+
+// RemoveServer removes the server: only the keys sent to this server are sent to other ones.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	if err := b.RoundRobin.RemoveServer(u); err != nil {
+		return err
+	}
+
+	b.ring.Remove(u.String())
+
+	b.lock.Lock()
+	delete(b.servers, u.String())
+	b.lock.Unlock()
+
+	return nil
+}
+
+func (b *Balancer) server(key string) (*url.URL, bool) {
+	name, ok := b.ring.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	u, ok := b.servers[name]
+	if !ok {
+		return nil, false
+	}
+	return utils.CopyURL(u), true
+}
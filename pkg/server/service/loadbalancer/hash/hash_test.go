@@ -0,0 +1,166 @@
+package hash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyFunc(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		sticky        *dynamic.Sticky
+		req           func() *http.Request
+		expectedNil   bool
+		expectedKey   string
+		expectedError bool
+	}{
+		{
+			desc:        "no sticky",
+			expectedNil: true,
+		},
+		{
+			desc:        "cookie",
+			sticky:      &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+			expectedNil: true,
+		},
+		{
+			desc:   "header",
+			sticky: &dynamic.Sticky{Header: &dynamic.StickyHeader{Name: "X-Client"}},
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+				req.Header.Set("X-Client", "foo")
+				return req
+			},
+			expectedKey: "foo",
+		},
+		{
+			desc:          "header without name",
+			sticky:        &dynamic.Sticky{Header: &dynamic.StickyHeader{}},
+			expectedError: true,
+		},
+		{
+			desc:   "query",
+			sticky: &dynamic.Sticky{Query: &dynamic.StickyQuery{Name: "client"}},
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "http://localhost?client=bar", nil)
+			},
+			expectedKey: "bar",
+		},
+		{
+			desc:   "source IP",
+			sticky: &dynamic.Sticky{SourceIP: &dynamic.StickySourceIP{}},
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+				req.RemoteAddr = "10.0.0.1:1234"
+				return req
+			},
+			expectedKey: "10.0.0.1",
+		},
+		{
+			desc:   "source IP with depth",
+			sticky: &dynamic.Sticky{SourceIP: &dynamic.StickySourceIP{IPStrategy: &dynamic.IPStrategy{Depth: 1}}},
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+				req.Header.Set("X-Forwarded-For", "10.0.0.2, 10.0.0.3")
+				return req
+			},
+			expectedKey: "10.0.0.3",
+		},
+		{
+			desc: "several affinities",
+			sticky: &dynamic.Sticky{
+				Query:    &dynamic.StickyQuery{Name: "client"},
+				SourceIP: &dynamic.StickySourceIP{},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := NewKeyFunc(test.sticky)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if test.expectedNil {
+				assert.Nil(t, key)
+				return
+			}
+
+			require.NotNil(t, key)
+			assert.Equal(t, test.expectedKey, key(test.req()))
+		})
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	balancer, err := New(next, func(req *http.Request) string {
+		return req.Header.Get("X-Client")
+	})
+	require.NoError(t, err)
+
+	var servers []*url.URL
+	for i := 0; i < 3; i++ {
+		u, err := url.Parse("http://10.0.0." + strconv.Itoa(i) + ":80")
+		require.NoError(t, err)
+
+		require.NoError(t, balancer.UpsertServer(u))
+		servers = append(servers, u)
+	}
+
+	serve := func(client string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		if client != "" {
+			req.Header.Set("X-Client", client)
+		}
+
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		return recorder.Header().Get("server")
+	}
+
+	assignments := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		client := strconv.Itoa(i)
+		assignments[client] = serve(client)
+		assert.Equal(t, assignments[client], serve(client))
+	}
+
+	// Requests without key are load-balanced with round robin.
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		seen[serve("")] = true
+	}
+	assert.Len(t, seen, 3)
+
+	require.NoError(t, balancer.RemoveServer(servers[0]))
+	assert.Len(t, balancer.Servers(), 2)
+
+	for client, host := range assignments {
+		if host == servers[0].Host {
+			assert.NotEqual(t, servers[0].Host, serve(client))
+			continue
+		}
+		// The clients of the other servers are not remapped.
+		assert.Equal(t, host, serve(client))
+	}
+}
@@ -0,0 +1,150 @@
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/metrics"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	mismatchStatus = "status"
+	mismatchBody   = "body"
+)
+
+// Comparator compares the responses of the mirrors with the main response.
+// The mismatches are counted in the metrics, and a sample of them is logged.
+type Comparator struct {
+	service    string
+	body       bool
+	mismatches gokitmetrics.Counter
+	logLimiter *rate.Limiter
+}
+
+// NewComparator creates a comparator for the mirrors of the given service.
+func NewComparator(service string, conf dynamic.MirroringCompare, registry metrics.Registry) *Comparator {
+	comparator := &Comparator{
+		service: service,
+		body:    conf.Body,
+		// At most one mismatch per second is logged.
+		logLimiter: rate.NewLimiter(rate.Every(time.Second), 1),
+	}
+
+	if registry != nil && registry.IsSvcEnabled() {
+		comparator.mismatches = registry.ServiceMirrorMismatchCounter()
+	}
+
+	return comparator
+}
+
+func (c *Comparator) compare(req *http.Request, mirror string, main, mirrored *responseRecorder) {
+	if main.hijacked || mirrored.hijacked {
+		return
+	}
+
+	var reason string
+	switch {
+	case main.statusCode() != mirrored.statusCode():
+		reason = mismatchStatus
+	case c.body && !bytes.Equal(main.sum(), mirrored.sum()):
+		reason = mismatchBody
+	default:
+		return
+	}
+
+	if c.mismatches != nil {
+		c.mismatches.With("service", c.service, "mirror", mirror, "reason", reason).Add(1)
+	}
+
+	if !c.logLimiter.Allow() {
+		return
+	}
+
+	msg := fmt.Sprintf("Response of mirror %s differs (%s) for %s %s: status code %d instead of %d",
+		mirror, reason, req.Method, req.URL.RequestURI(), mirrored.statusCode(), main.statusCode())
+	if c.body {
+		msg += fmt.Sprintf(", body hash %x instead of %x", mirrored.sum(), main.sum())
+	}
+
+	log.WithoutContext().WithField(log.ServiceName, c.service).Info(msg)
+}
+
+// responseRecorder records the status code, and the hash of the body if needed,
+// of the response written to the underlying response writer.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	code     int
+	hash     hash.Hash
+	hijacked bool
+}
+
+func newResponseRecorder(rw http.ResponseWriter, body bool) *responseRecorder {
+	recorder := &responseRecorder{ResponseWriter: rw}
+	if body {
+		recorder.hash = sha256.New()
+	}
+	return recorder
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+
+	if r.hash != nil {
+		_, _ = r.hash.Write(p)
+	}
+
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		r.hijacked = true
+	}
+	return conn, rw, err
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+	return r.code
+}
+
+func (r *responseRecorder) sum() []byte {
+	if r.hash == nil {
+		return nil
+	}
+	return r.hash.Sum(nil)
+}
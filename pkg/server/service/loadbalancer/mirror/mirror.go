@@ -2,12 +2,16 @@ package mirror
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"sync"
 
+	"github.com/containous/traefik/v2/pkg/log"
 	"github.com/containous/traefik/v2/pkg/safe"
 )
 
@@ -17,17 +21,23 @@ type Mirroring struct {
 	mirrorHandlers []*mirrorHandler
 	rw             http.ResponseWriter
 	routinePool    *safe.Pool
+	maxBodySize    int64
+	comparator     *Comparator
 
 	lock  sync.RWMutex
 	total uint64
 }
 
 // New returns a new instance of *Mirroring.
-func New(handler http.Handler, pool *safe.Pool) *Mirroring {
+// The requests with a body larger than maxBodySize are not mirrored, a negative maxBodySize meaning no limit.
+// If comparator is not nil, the responses of the mirrors are compared with the response of handler.
+func New(handler http.Handler, pool *safe.Pool, maxBodySize int64, comparator *Comparator) *Mirroring {
 	return &Mirroring{
 		routinePool: pool,
 		handler:     handler,
 		rw:          blackholeResponseWriter{},
+		maxBodySize: maxBodySize,
+		comparator:  comparator,
 	}
 }
 
@@ -40,6 +50,7 @@ func (m *Mirroring) inc() uint64 {
 
 type mirrorHandler struct {
 	http.Handler
+	name    string
 	percent int
 
 	lock  sync.RWMutex
@@ -47,7 +58,26 @@ type mirrorHandler struct {
 }
 
 func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	m.handler.ServeHTTP(rw, req)
+	mirrors := m.pickMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	body, ok := m.bufferBody(req)
+
+	var recorder *responseRecorder
+	if ok && m.comparator != nil {
+		recorder = newResponseRecorder(rw, m.comparator.body)
+		m.handler.ServeHTTP(recorder, req)
+	} else {
+		m.handler.ServeHTTP(rw, req)
+	}
+
+	if !ok {
+		log.FromContext(req.Context()).Debug("Request not mirrored: its body is too large or cannot be read")
+		return
+	}
 
 	select {
 	case <-req.Context().Done():
@@ -57,33 +87,85 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	m.routinePool.GoCtx(func(_ context.Context) {
-		total := m.inc()
-		for _, handler := range m.mirrorHandlers {
-			handler.lock.Lock()
-			if handler.count*100 < total*uint64(handler.percent) {
-				handler.count++
-				handler.lock.Unlock()
-				// When a request served by m.handler is successful, req.Context will be canceled,
-				// which would trigger a cancellation of the ongoing mirrored requests.
-				// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
-				// so they can terminate by themselves.
-				handler.ServeHTTP(m.rw, req.WithContext(contextStopPropagation{req.Context()}))
-			} else {
-				handler.lock.Unlock()
+		for _, handler := range mirrors {
+			// When a request served by m.handler is successful, req.Context will be canceled,
+			// which would trigger a cancellation of the ongoing mirrored requests.
+			// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
+			// so they can terminate by themselves.
+			mirrorReq := req.WithContext(contextStopPropagation{req.Context()})
+			if body != nil {
+				mirrorReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+
+			if recorder == nil {
+				handler.ServeHTTP(m.rw, mirrorReq)
+				continue
 			}
+
+			mirrorRecorder := newResponseRecorder(m.rw, m.comparator.body)
+			handler.ServeHTTP(mirrorRecorder, mirrorReq)
+			m.comparator.compare(req, handler.name, recorder, mirrorRecorder)
 		}
 	})
 }
 
+// pickMirrors returns the mirrors the request is sent to, according to their percentages.
+// The body of the request is only buffered when at least one mirror is picked.
+func (m *Mirroring) pickMirrors() []*mirrorHandler {
+	total := m.inc()
+
+	var mirrors []*mirrorHandler
+	for _, handler := range m.mirrorHandlers {
+		handler.lock.Lock()
+		if handler.count*100 < total*uint64(handler.percent) {
+			handler.count++
+			mirrors = append(mirrors, handler)
+		}
+		handler.lock.Unlock()
+	}
+
+	return mirrors
+}
+
+// bufferBody reads the body of the request, so that it can be sent again to the mirrors.
+// It returns false if the body is larger than maxBodySize or cannot be read:
+// the request is then not mirrored, but the main handler still receives the whole body.
+func (m *Mirroring) bufferBody(req *http.Request) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true
+	}
+
+	original := req.Body
+
+	reader := io.Reader(original)
+	if m.maxBodySize >= 0 {
+		reader = io.LimitReader(original, m.maxBodySize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil || (m.maxBodySize >= 0 && int64(len(body)) > m.maxBodySize) {
+		req.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+		return nil, false
+	}
+
+	req.Body = &readCloser{Reader: bytes.NewReader(body), Closer: original}
+	return body, true
+}
+
 // AddMirror adds an httpHandler to mirror to.
-func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
+func (m *Mirroring) AddMirror(name string, handler http.Handler, percent int) error {
 	if percent < 0 || percent > 100 {
 		return errors.New("percent must be between 0 and 100")
 	}
-	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, name: name, percent: percent})
 	return nil
 }
 
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 type blackholeResponseWriter struct{}
 
 func (b blackholeResponseWriter) Flush() {}
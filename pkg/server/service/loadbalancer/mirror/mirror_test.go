@@ -1,14 +1,22 @@
 package mirror
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/safe"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMirroringOn100(t *testing.T) {
@@ -17,13 +25,13 @@ func TestMirroringOn100(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	mirror := New(handler, pool, -1, nil)
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err = mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror2, 1)
 	}), 50)
 	assert.NoError(t, err)
@@ -46,13 +54,13 @@ func TestMirroringOn10(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	mirror := New(handler, pool, -1, nil)
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err = mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror2, 1)
 	}), 50)
 	assert.NoError(t, err)
@@ -70,17 +78,17 @@ func TestMirroringOn10(t *testing.T) {
 }
 
 func TestInvalidPercent(t *testing.T) {
-	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()))
-	err := mirror.AddMirror(nil, -1)
+	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()), -1, nil)
+	err := mirror.AddMirror("mirror", nil, -1)
 	assert.Error(t, err)
 
-	err = mirror.AddMirror(nil, 101)
+	err = mirror.AddMirror("mirror", nil, 101)
 	assert.Error(t, err)
 
-	err = mirror.AddMirror(nil, 100)
+	err = mirror.AddMirror("mirror", nil, 100)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(nil, 0)
+	err = mirror.AddMirror("mirror", nil, 0)
 	assert.NoError(t, err)
 }
 
@@ -89,10 +97,10 @@ func TestHijack(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, -1, nil)
 
 	var mirrorRequest bool
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		hijacker, ok := rw.(http.Hijacker)
 		assert.Equal(t, true, ok)
 
@@ -113,10 +121,10 @@ func TestFlush(t *testing.T) {
 		rw.WriteHeader(http.StatusOK)
 	})
 	pool := safe.NewPool(context.Background())
-	mirror := New(handler, pool)
+	mirror := New(handler, pool, -1, nil)
 
 	var mirrorRequest bool
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		hijacker, ok := rw.(http.Flusher)
 		assert.Equal(t, true, ok)
 
@@ -131,3 +139,218 @@ func TestFlush(t *testing.T) {
 	pool.Stop()
 	assert.Equal(t, true, mirrorRequest)
 }
+
+func TestMirroringWithBody(t *testing.T) {
+	const numMirrors = 10
+
+	var (
+		countMirror int32
+		body        = []byte(`body`)
+	)
+
+	pool := safe.NewPool(context.Background())
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		bb, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, bb)
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	mirror := New(handler, pool, -1, nil)
+
+	for i := 0; i < numMirrors; i++ {
+		err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			bb, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, bb)
+			atomic.AddInt32(&countMirror, 1)
+		}), 100)
+		assert.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+	mirror.ServeHTTP(httptest.NewRecorder(), req)
+
+	pool.Stop()
+
+	assert.Equal(t, numMirrors, int(atomic.LoadInt32(&countMirror)))
+}
+
+func TestMirroringMaxBodySize(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		maxBodySize      int64
+		body             string
+		expectedMirrored int32
+	}{
+		{
+			desc:             "body smaller than the limit",
+			maxBodySize:      4,
+			body:             "body",
+			expectedMirrored: 1,
+		},
+		{
+			desc:        "body larger than the limit",
+			maxBodySize: 3,
+			body:        "body",
+		},
+		{
+			desc:             "no body",
+			maxBodySize:      0,
+			expectedMirrored: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var countMirror int32
+
+			pool := safe.NewPool(context.Background())
+
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// The main handler always gets the whole body.
+				bb, err := ioutil.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, test.body, string(bb))
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			mirror := New(handler, pool, test.maxBodySize, nil)
+			err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&countMirror, 1)
+			}), 100)
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", body))
+
+			pool.Stop()
+
+			assert.Equal(t, test.expectedMirrored, atomic.LoadInt32(&countMirror))
+		})
+	}
+}
+
+func TestMirroringBodyNotBufferedWithoutMirror(t *testing.T) {
+	pool := safe.NewPool(context.Background())
+
+	body := strings.NewReader("body")
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// No mirror is picked: the main handler reads the body of the client directly.
+		_, buffered := req.Body.(*readCloser)
+		assert.False(t, buffered)
+		assert.Equal(t, 4, body.Len())
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	mirror := New(handler, pool, -1, nil)
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Error("The request should not be mirrored")
+	}), 0)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	mirror.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", body))
+
+	pool.Stop()
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type counterMock struct {
+	lock        sync.Mutex
+	labelValues [][]string
+}
+
+func (c *counterMock) With(labelValues ...string) gokitmetrics.Counter {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.labelValues = append(c.labelValues, labelValues)
+	return c
+}
+
+func (c *counterMock) Add(float64) {}
+
+func TestMirroringCompare(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		compareBody        bool
+		mirrorStatus       int
+		mirrorBody         string
+		expectedMismatches [][]string
+	}{
+		{
+			desc:         "same responses",
+			compareBody:  true,
+			mirrorStatus: http.StatusOK,
+			mirrorBody:   "foo",
+		},
+		{
+			desc:         "different status codes",
+			mirrorStatus: http.StatusNotFound,
+			mirrorBody:   "foo",
+			expectedMismatches: [][]string{
+				{"service", "service", "mirror", "mirror", "reason", "status"},
+			},
+		},
+		{
+			desc:         "different bodies",
+			compareBody:  true,
+			mirrorStatus: http.StatusOK,
+			mirrorBody:   "bar",
+			expectedMismatches: [][]string{
+				{"service", "service", "mirror", "mirror", "reason", "body"},
+			},
+		},
+		{
+			desc:         "different bodies not compared",
+			mirrorStatus: http.StatusOK,
+			mirrorBody:   "bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pool := safe.NewPool(context.Background())
+
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte("foo"))
+			})
+
+			counter := &counterMock{}
+			comparator := NewComparator("service", dynamic.MirroringCompare{Body: test.compareBody}, nil)
+			comparator.mismatches = counter
+
+			mirror := New(handler, pool, -1, comparator)
+			err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(test.mirrorStatus)
+				_, _ = rw.Write([]byte(test.mirrorBody))
+			}), 100)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mirror.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			pool.Stop()
+
+			// The response of the main handler is not altered.
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "foo", recorder.Body.String())
+
+			assert.Equal(t, test.expectedMismatches, counter.labelValues)
+		})
+	}
+}
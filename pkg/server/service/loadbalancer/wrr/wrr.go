@@ -6,13 +6,37 @@ import (
 	"sync"
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/hashring"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/hash"
 )
 
 type namedHandler struct {
 	http.Handler
 	name   string
 	weight int
+	match  *dynamic.WRRMatch
+}
+
+func (h *namedHandler) matches(req *http.Request) bool {
+	if h.match == nil {
+		return false
+	}
+
+	for name, value := range h.match.Headers {
+		if req.Header.Get(name) == value {
+			return true
+		}
+	}
+
+	for name, value := range h.match.Cookies {
+		cookie, err := req.Cookie(name)
+		if err == nil && cookie.Value == value {
+			return true
+		}
+	}
+
+	return false
 }
 
 type stickyCookie struct {
@@ -22,7 +46,7 @@ type stickyCookie struct {
 }
 
 // New creates a new load balancer.
-func New(sticky *dynamic.Sticky) *Balancer {
+func New(sticky *dynamic.Sticky) (*Balancer, error) {
 	balancer := &Balancer{
 		mutex: &sync.Mutex{},
 		index: -1,
@@ -34,7 +58,17 @@ func New(sticky *dynamic.Sticky) *Balancer {
 			httpOnly: sticky.Cookie.HTTPOnly,
 		}
 	}
-	return balancer
+
+	hashKey, err := hash.NewKeyFunc(sticky)
+	if err != nil {
+		return nil, err
+	}
+	if hashKey != nil {
+		balancer.hashKey = hashKey
+		balancer.ring = hashring.New()
+	}
+
+	return balancer, nil
 }
 
 // Balancer is a WeightedRoundRobin load balancer.
@@ -45,6 +79,9 @@ type Balancer struct {
 	index         int
 	currentWeight int
 	stickyCookie  *stickyCookie
+	// hashKey and ring are set when the stickiness uses a consistent hash of the requests.
+	hashKey hash.KeyFunc
+	ring    *hashring.Ring
 }
 
 func (b *Balancer) maxWeight() int {
@@ -113,6 +150,21 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 }
 
 func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// The match conditions take precedence over the stickiness and the weights.
+	for _, handler := range b.handlers {
+		if handler.matches(req) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	if b.hashKey != nil {
+		if handler := b.hashedHandler(req); handler != nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
 	if b.stickyCookie != nil {
 		cookie, err := req.Cookie(b.stickyCookie.name)
 
@@ -152,4 +204,70 @@ func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
 		w = *weight
 	}
 	b.handlers = append(b.handlers, &namedHandler{Handler: handler, name: name, weight: w})
+
+	if b.ring != nil {
+		b.ring.Add(name, w)
+	}
+}
+
+// SetMatch sets the conditions sending the requests to the named service, whatever its weight.
+// It is not thread safe with ServeHTTP.
+func (b *Balancer) SetMatch(name string, match *dynamic.WRRMatch) error {
+	for _, handler := range b.handlers {
+		if handler.name == name {
+			handler.match = match
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown service %q", name)
+}
+
+// SetWeight updates the weight of the named service.
+// It is safe to call it while the balancer is serving requests.
+func (b *Balancer) SetWeight(name string, weight int) error {
+	if weight < 0 {
+		return fmt.Errorf("invalid weight %d: it must not be negative", weight)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, handler := range b.handlers {
+		if handler.name != name {
+			continue
+		}
+
+		handler.weight = weight
+		// Restarts the rotation with the new weights.
+		b.index = -1
+		b.currentWeight = 0
+
+		if b.ring != nil {
+			b.ring.Add(name, weight)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown service %q", name)
+}
+
+// hashedHandler returns the handler owning the hash key of the request,
+// or nil if the request does not hold any key.
+func (b *Balancer) hashedHandler(req *http.Request) *namedHandler {
+	key := b.hashKey(req)
+	if key == "" {
+		return nil
+	}
+
+	name, ok := b.ring.Get(key)
+	if !ok {
+		return nil
+	}
+
+	for _, handler := range b.handlers {
+		if handler.name == name {
+			return handler
+		}
+	}
+	return nil
 }
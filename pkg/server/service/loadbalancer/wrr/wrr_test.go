@@ -7,6 +7,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Int(v int) *int { return &v }
@@ -22,7 +23,8 @@ func (r *responseRecorder) WriteHeader(statusCode int) {
 }
 
 func TestBalancer(t *testing.T) {
-	balancer := New(nil)
+	balancer, err := New(nil)
+	require.NoError(t, err)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -44,7 +46,8 @@ func TestBalancer(t *testing.T) {
 }
 
 func TestBalancerNoService(t *testing.T) {
-	balancer := New(nil)
+	balancer, err := New(nil)
+	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
 	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
@@ -53,7 +56,8 @@ func TestBalancerNoService(t *testing.T) {
 }
 
 func TestBalancerOneServerZeroWeight(t *testing.T) {
-	balancer := New(nil)
+	balancer, err := New(nil)
+	require.NoError(t, err)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -71,7 +75,8 @@ func TestBalancerOneServerZeroWeight(t *testing.T) {
 }
 
 func TestBalancerAllServersZeroWeight(t *testing.T) {
-	balancer := New(nil)
+	balancer, err := New(nil)
+	require.NoError(t, err)
 
 	balancer.AddService("test", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(0))
 	balancer.AddService("test2", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(0))
@@ -83,9 +88,10 @@ func TestBalancerAllServersZeroWeight(t *testing.T) {
 }
 
 func TestSticky(t *testing.T) {
-	balancer := New(&dynamic.Sticky{
+	balancer, err := New(&dynamic.Sticky{
 		Cookie: &dynamic.Cookie{Name: "test"},
 	})
+	require.NoError(t, err)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -112,3 +118,156 @@ func TestSticky(t *testing.T) {
 	assert.Equal(t, 0, recorder.save["first"])
 	assert.Equal(t, 3, recorder.save["second"])
 }
+
+func TestStickyHash(t *testing.T) {
+	balancer, err := New(&dynamic.Sticky{
+		Header: &dynamic.StickyHeader{Name: "X-Client"},
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second", "third"} {
+		name := name
+		balancer.AddService(name, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+			rw.WriteHeader(http.StatusOK)
+		}), Int(1))
+	}
+
+	for _, client := range []string{"a", "b", "c", "d"} {
+		recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Client", client)
+			balancer.ServeHTTP(recorder, req)
+		}
+
+		// All the requests of a client go to the same service.
+		assert.Len(t, recorder.save, 1)
+	}
+
+	// The requests without key are load-balanced.
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 3; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Len(t, recorder.save, 3)
+}
+
+func TestNew_invalidSticky(t *testing.T) {
+	_, err := New(&dynamic.Sticky{
+		Cookie: &dynamic.Cookie{Name: "test"},
+		Header: &dynamic.StickyHeader{Name: "X-Client"},
+	})
+	assert.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	balancer, err := New(&dynamic.Sticky{
+		Cookie: &dynamic.Cookie{Name: "test"},
+	})
+	require.NoError(t, err)
+
+	balancer.AddService("stable", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "stable")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.AddService("canary", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "canary")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(0))
+
+	err = balancer.SetMatch("canary", &dynamic.WRRMatch{
+		Headers: map[string]string{"X-Canary": "true"},
+		Cookies: map[string]string{"beta": "1"},
+	})
+	require.NoError(t, err)
+
+	assert.Error(t, balancer.SetMatch("unknown", &dynamic.WRRMatch{}))
+
+	testCases := []struct {
+		desc     string
+		header   string
+		cookie   *http.Cookie
+		expected string
+	}{
+		{
+			desc:     "no condition",
+			expected: "stable",
+		},
+		{
+			desc:     "matching header",
+			header:   "true",
+			expected: "canary",
+		},
+		{
+			desc:     "other header value",
+			header:   "false",
+			expected: "stable",
+		},
+		{
+			desc:     "matching cookie",
+			cookie:   &http.Cookie{Name: "beta", Value: "1"},
+			expected: "canary",
+		},
+		{
+			desc:     "matching header over sticky cookie",
+			header:   "true",
+			cookie:   &http.Cookie{Name: "test", Value: "stable"},
+			expected: "canary",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set("X-Canary", test.header)
+			}
+			if test.cookie != nil {
+				req.AddCookie(test.cookie)
+			}
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Header().Get("server"))
+		})
+	}
+}
+
+func TestSetWeight(t *testing.T) {
+	balancer, err := New(nil)
+	require.NoError(t, err)
+
+	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.AddService("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(0))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 4; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Equal(t, 4, recorder.save["first"])
+	assert.Equal(t, 0, recorder.save["second"])
+
+	require.NoError(t, balancer.SetWeight("second", 3))
+
+	recorder = &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+	for i := 0; i < 4; i++ {
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Equal(t, 1, recorder.save["first"])
+	assert.Equal(t, 3, recorder.save["second"])
+
+	assert.Error(t, balancer.SetWeight("second", -1))
+	assert.Error(t, balancer.SetWeight("unknown", 1))
+}
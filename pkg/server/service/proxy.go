@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/middlewares/bodylimit"
 	"github.com/containous/traefik/v2/pkg/types"
 )
 
@@ -75,6 +77,8 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 				statusCode = http.StatusBadGateway
 			case err == context.Canceled:
 				statusCode = StatusClientClosedRequest
+			case errors.Is(err, bodylimit.ErrBodyTooLarge):
+				statusCode = http.StatusRequestEntityTooLarge
 			default:
 				if e, ok := err.(net.Error); ok {
 					if e.Timeout() {
@@ -7,7 +7,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/containous/traefik/v2/pkg/middlewares/bodylimit"
 	"github.com/containous/traefik/v2/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type staticTransport struct {
@@ -35,3 +38,30 @@ func BenchmarkProxy(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+func TestProxyBodyTooLarge(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = ioutil.ReadAll(req.Body)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	proxy, err := buildProxy(Bool(false), nil, http.DefaultTransport, nil, nil)
+	require.NoError(t, err)
+
+	frontend := httptest.NewServer(bodylimit.New(10, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		req.URL = testhelpers.MustParseURL(backend.URL)
+		proxy.ServeHTTP(rw, req)
+	})))
+	defer frontend.Close()
+
+	// Without a Content-Length, the body is only found too large while being forwarded.
+	req := testhelpers.MustNewRequest(http.MethodPost, frontend.URL, ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 100))))
+	req.ContentLength = -1
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/log"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"golang.org/x/net/http2"
+)
+
+// RoundTripperManager handles the round-trippers built from the servers transports of the dynamic configuration.
+type RoundTripperManager struct {
+	rtLock              sync.RWMutex
+	defaultRoundTripper http.RoundTripper
+	configs             map[string]*dynamic.ServersTransport
+	files               map[string][][]byte
+	roundTrippers       map[string]http.RoundTripper
+}
+
+// NewRoundTripperManager creates a new RoundTripperManager,
+// the default round-tripper being used by the load-balancers that do not reference any servers transport.
+func NewRoundTripperManager(defaultRoundTripper http.RoundTripper) *RoundTripperManager {
+	return &RoundTripperManager{
+		defaultRoundTripper: defaultRoundTripper,
+		configs:             make(map[string]*dynamic.ServersTransport),
+		files:               make(map[string][][]byte),
+		roundTrippers:       make(map[string]http.RoundTripper),
+	}
+}
+
+// Update updates the round-trippers with the given servers transports configurations.
+// The round-trippers of unchanged configurations are kept, so that their connections can be reused,
+// unless the content of the certificate files they reference changed.
+func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTransport) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	newFiles := make(map[string][][]byte)
+	for newConfigName, newConfig := range newConfigs {
+		newFiles[newConfigName] = readFiles(newConfig)
+	}
+
+	for configName, config := range r.configs {
+		newConfig, ok := newConfigs[configName]
+		if ok && reflect.DeepEqual(newConfig, config) && reflect.DeepEqual(newFiles[configName], r.files[configName]) {
+			continue
+		}
+
+		if rt, ok := r.roundTrippers[configName].(*http.Transport); ok {
+			rt.CloseIdleConnections()
+		}
+
+		delete(r.configs, configName)
+		delete(r.files, configName)
+		delete(r.roundTrippers, configName)
+	}
+
+	for newConfigName, newConfig := range newConfigs {
+		if _, ok := r.configs[newConfigName]; ok {
+			continue
+		}
+
+		r.configs[newConfigName] = newConfig
+		r.files[newConfigName] = newFiles[newConfigName]
+
+		rt, err := NewRoundTripper(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure HTTP Transport %s: %v", newConfigName, err)
+			continue
+		}
+
+		r.roundTrippers[newConfigName] = rt
+	}
+}
+
+// Get returns the round-tripper of the given servers transport, or the default round-tripper if the name is empty.
+func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
+	if len(name) == 0 {
+		return r.defaultRoundTripper, nil
+	}
+
+	r.rtLock.RLock()
+	defer r.rtLock.RUnlock()
+
+	if rt, ok := r.roundTrippers[name]; ok {
+		return rt, nil
+	}
+
+	if _, ok := r.configs[name]; ok {
+		return nil, fmt.Errorf("servers transport %s is invalid", name)
+	}
+
+	return nil, fmt.Errorf("servers transport %s does not exist", name)
+}
+
+// readFiles returns the content of the files referenced by the servers transport.
+func readFiles(cfg *dynamic.ServersTransport) [][]byte {
+	if cfg == nil {
+		return nil
+	}
+
+	var files []traefiktls.FileOrContent
+	files = append(files, cfg.RootCAs...)
+	for _, cert := range cfg.Certificates {
+		files = append(files, cert.CertFile, cert.KeyFile)
+	}
+
+	var contents [][]byte
+	for _, file := range files {
+		if !file.IsPath() {
+			continue
+		}
+
+		// The content of a file which can't be read is nil.
+		content, _ := file.Read()
+		contents = append(contents, content)
+	}
+
+	return contents
+}
+
+type h2cTransportWrapper struct {
+	*http2.Transport
+}
+
+func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	return t.Transport.RoundTrip(req)
+}
+
+// NewRoundTripper creates an http.Transport configured with the servers transport settings.
+// For the settings that can't be configured in Traefik it uses the default http.Transport settings.
+// An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost
+// in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
+// behavior and backwards compatibility issues.
+func NewRoundTripper(cfg *dynamic.ServersTransport) (*http.Transport, error) {
+	if cfg == nil {
+		return nil, errors.New("no transport configuration given")
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
+
+	// The zero timeouts, from the providers not setting the default values, keep the default ones.
+	if cfg.ForwardingTimeouts != nil && cfg.ForwardingTimeouts.DialTimeout > 0 {
+		dialer.Timeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
+	}
+
+	tlsConfig, err := createTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+
+	if cfg.ForwardingTimeouts != nil {
+		transport.ResponseHeaderTimeout = time.Duration(cfg.ForwardingTimeouts.ResponseHeaderTimeout)
+		if cfg.ForwardingTimeouts.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = time.Duration(cfg.ForwardingTimeouts.IdleConnTimeout)
+		}
+	}
+
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map disables the automatic HTTP/2 support of the transport.
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		return transport, nil
+	}
+
+	transport.RegisterProtocol("h2c", &h2cTransportWrapper{
+		Transport: &http2.Transport{
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+			AllowHTTP: true,
+		},
+	})
+
+	err = http2.ConfigureTransport(transport)
+	if err != nil {
+		return nil, err
+	}
+
+	return transport, nil
+}
+
+func createTLSConfig(cfg *dynamic.ServersTransport) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	// The root CAs take precedence over insecureSkipVerify.
+	if len(cfg.RootCAs) > 0 {
+		tlsConfig.RootCAs = createRootCACertPool(cfg.RootCAs)
+		tlsConfig.InsecureSkipVerify = false
+	}
+
+	for _, cert := range cfg.Certificates {
+		certContent, err := cert.CertFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		keyContent, err := cert.KeyFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		certificate, err := tls.X509KeyPair(certContent, keyContent)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+
+	if len(cfg.PeerCertURI) > 0 {
+		// The server name of the peer certificate is not checked (the standard verification is disabled),
+		// the chain and the URI SAN are checked instead.
+		roots := tlsConfig.RootCAs
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyPeerCertificate(cfg.PeerCertURI, roots, rawCerts)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
+	roots := x509.NewCertPool()
+
+	for _, cert := range rootCAs {
+		certContent, err := cert.Read()
+		if err != nil {
+			log.WithoutContext().Error("Error while read RootCAs", err)
+			continue
+		}
+
+		if !roots.AppendCertsFromPEM(certContent) {
+			log.WithoutContext().Error("Error while parsing RootCAs: no certificate found")
+		}
+	}
+
+	return roots
+}
+
+// verifyPeerCertificate verifies the chain of the peer certificate against the given roots,
+// and checks that the leaf certificate holds the given URI SAN.
+func verifyPeerCertificate(uri string, roots *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no peer certificate")
+	}
+
+	var certs []*x509.Certificate
+	for _, rawCert := range rawCerts {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, certURI := range certs[0].URIs {
+		if certURI.String() == uri {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("peer certificate does not match the URI %s", uri)
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/containous/traefik/v2/pkg/tls"
+	"github.com/containous/traefik/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM encoded certificate and key of a leaf holding the given URI SAN.
+func (ca *testCA) issue(t *testing.T, uri string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{u},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestRoundTripperManager_Get(t *testing.T) {
+	defaultRoundTripper := &http.Transport{}
+
+	rtManager := NewRoundTripperManager(defaultRoundTripper)
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"test@file": {},
+		"invalid@file": {
+			Certificates: traefiktls.Certificates{
+				{CertFile: "not a certificate", KeyFile: "not a key"},
+			},
+		},
+	})
+
+	rt, err := rtManager.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, defaultRoundTripper, rt)
+
+	rt, err = rtManager.Get("test@file")
+	require.NoError(t, err)
+	assert.NotNil(t, rt)
+
+	_, err = rtManager.Get("invalid@file")
+	assert.EqualError(t, err, "servers transport invalid@file is invalid")
+
+	_, err = rtManager.Get("unknown@file")
+	assert.EqualError(t, err, "servers transport unknown@file does not exist")
+}
+
+func TestRoundTripperManager_Update(t *testing.T) {
+	rtManager := NewRoundTripperManager(http.DefaultTransport)
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"unchanged@file": {ServerName: "foo"},
+		"changed@file":   {ServerName: "foo"},
+		"removed@file":   {ServerName: "foo"},
+	})
+
+	unchanged, err := rtManager.Get("unchanged@file")
+	require.NoError(t, err)
+	changed, err := rtManager.Get("changed@file")
+	require.NoError(t, err)
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"unchanged@file": {ServerName: "foo"},
+		"changed@file":   {ServerName: "bar"},
+	})
+
+	rt, err := rtManager.Get("unchanged@file")
+	require.NoError(t, err)
+	assert.Same(t, unchanged, rt)
+
+	rt, err = rtManager.Get("changed@file")
+	require.NoError(t, err)
+	assert.False(t, changed == rt)
+	assert.Equal(t, "bar", rt.(*http.Transport).TLSClientConfig.ServerName)
+
+	_, err = rtManager.Get("removed@file")
+	assert.Error(t, err)
+}
+
+func TestRoundTripperManager_Update_filesRotation(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "spiffe://foo")
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	newConfigs := func() map[string]*dynamic.ServersTransport {
+		return map[string]*dynamic.ServersTransport{
+			"test@file": {
+				RootCAs: []traefiktls.FileOrContent{traefiktls.FileOrContent(caFile)},
+				Certificates: traefiktls.Certificates{
+					{CertFile: traefiktls.FileOrContent(certFile), KeyFile: traefiktls.FileOrContent(keyFile)},
+				},
+			},
+		}
+	}
+
+	rtManager := NewRoundTripperManager(http.DefaultTransport)
+	rtManager.Update(newConfigs())
+
+	previous, err := rtManager.Get("test@file")
+	require.NoError(t, err)
+
+	// Nothing changed: the round-tripper is kept.
+	rtManager.Update(newConfigs())
+
+	rt, err := rtManager.Get("test@file")
+	require.NoError(t, err)
+	assert.Same(t, previous, rt)
+
+	// The certificate is rotated: the round-tripper is rebuilt with the new one.
+	newCertPEM, newKeyPEM := ca.issue(t, "spiffe://bar")
+	require.NoError(t, os.WriteFile(certFile, newCertPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, newKeyPEM, 0o600))
+
+	rtManager.Update(newConfigs())
+
+	rt, err = rtManager.Get("test@file")
+	require.NoError(t, err)
+	assert.False(t, previous == rt)
+
+	expected, err := tls.X509KeyPair(newCertPEM, newKeyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Certificate, rt.(*http.Transport).TLSClientConfig.Certificates[0].Certificate)
+}
+
+func TestRoundTripper_peerCertURI(t *testing.T) {
+	ca := newTestCA(t)
+
+	serverCert, serverKey := ca.issue(t, "spiffe://test.consul/ns/default/dc/dc1/svc/whoami")
+	certificate, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	clientCert, clientKey := ca.issue(t, "spiffe://test.consul/ns/default/dc/dc1/svc/traefik")
+
+	testCases := []struct {
+		desc          string
+		config        dynamic.ServersTransport
+		expectedError bool
+	}{
+		{
+			desc: "matching URI",
+			config: dynamic.ServersTransport{
+				RootCAs:      []traefiktls.FileOrContent{traefiktls.FileOrContent(ca.certPEM)},
+				Certificates: traefiktls.Certificates{{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)}},
+				PeerCertURI:  "spiffe://test.consul/ns/default/dc/dc1/svc/whoami",
+			},
+		},
+		{
+			desc: "other URI",
+			config: dynamic.ServersTransport{
+				RootCAs:      []traefiktls.FileOrContent{traefiktls.FileOrContent(ca.certPEM)},
+				Certificates: traefiktls.Certificates{{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)}},
+				PeerCertURI:  "spiffe://test.consul/ns/default/dc/dc1/svc/other",
+			},
+			expectedError: true,
+		},
+		{
+			desc: "unknown CA",
+			config: dynamic.ServersTransport{
+				RootCAs:      []traefiktls.FileOrContent{traefiktls.FileOrContent(newTestCA(t).certPEM)},
+				Certificates: traefiktls.Certificates{{CertFile: traefiktls.FileOrContent(clientCert), KeyFile: traefiktls.FileOrContent(clientKey)}},
+				PeerCertURI:  "spiffe://test.consul/ns/default/dc/dc1/svc/whoami",
+			},
+			expectedError: true,
+		},
+		{
+			desc: "no client certificate",
+			config: dynamic.ServersTransport{
+				RootCAs:     []traefiktls.FileOrContent{traefiktls.FileOrContent(ca.certPEM)},
+				PeerCertURI: "spiffe://test.consul/ns/default/dc/dc1/svc/whoami",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rt, err := NewRoundTripper(&test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+			req.RequestURI = ""
+
+			resp, err := rt.RoundTrip(req)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewRoundTripper(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.ServersTransport
+		expected func(t *testing.T, transport *http.Transport)
+	}{
+		{
+			desc:   "default timeouts",
+			config: dynamic.ServersTransport{},
+			expected: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+				assert.Equal(t, time.Duration(0), transport.ResponseHeaderTimeout)
+				assert.Equal(t, 0, transport.MaxIdleConnsPerHost)
+			},
+		},
+		{
+			desc: "forwarding timeouts and max idle connections",
+			config: dynamic.ServersTransport{
+				MaxIdleConnsPerHost: 42,
+				ForwardingTimeouts: &dynamic.ForwardingTimeouts{
+					DialTimeout:           types.Duration(time.Second),
+					ResponseHeaderTimeout: types.Duration(2 * time.Second),
+					IdleConnTimeout:       types.Duration(3 * time.Second),
+				},
+			},
+			expected: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, 3*time.Second, transport.IdleConnTimeout)
+				assert.Equal(t, 2*time.Second, transport.ResponseHeaderTimeout)
+				assert.Equal(t, 42, transport.MaxIdleConnsPerHost)
+			},
+		},
+		{
+			desc: "zero forwarding timeouts",
+			config: dynamic.ServersTransport{
+				ForwardingTimeouts: &dynamic.ForwardingTimeouts{},
+			},
+			expected: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+				assert.Equal(t, time.Duration(0), transport.ResponseHeaderTimeout)
+			},
+		},
+		{
+			desc: "root CAs take precedence over insecure",
+			config: dynamic.ServersTransport{
+				InsecureSkipVerify: true,
+				RootCAs:            []traefiktls.FileOrContent{"not a certificate"},
+			},
+			expected: func(t *testing.T, transport *http.Transport) {
+				assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+				assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+			},
+		},
+		{
+			desc: "server name and insecure",
+			config: dynamic.ServersTransport{
+				ServerName:         "foo.bar",
+				InsecureSkipVerify: true,
+			},
+			expected: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, "foo.bar", transport.TLSClientConfig.ServerName)
+				assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transport, err := NewRoundTripper(&test.config)
+			require.NoError(t, err)
+
+			test.expected(t, transport)
+		})
+	}
+}
+
+func TestNewRoundTripper_HTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	rootCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	testCases := []struct {
+		desc          string
+		disableHTTP2  bool
+		expectedProto int
+	}{
+		{
+			desc:          "HTTP/2 enabled",
+			expectedProto: 2,
+		},
+		{
+			desc:          "HTTP/2 disabled",
+			disableHTTP2:  true,
+			expectedProto: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			rt, err := NewRoundTripper(&dynamic.ServersTransport{
+				ServerName:   "example.com",
+				RootCAs:      []traefiktls.FileOrContent{traefiktls.FileOrContent(rootCA)},
+				DisableHTTP2: test.disableHTTP2,
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+			req.RequestURI = ""
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectedProto, resp.ProtoMajor)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/containous/traefik/v2/pkg/safe"
 	"github.com/containous/traefik/v2/pkg/server/cookie"
 	"github.com/containous/traefik/v2/pkg/server/internal"
+	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/hash"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/mirror"
 	"github.com/containous/traefik/v2/pkg/server/service/loadbalancer/wrr"
 	"github.com/vulcand/oxy/roundrobin"
@@ -34,12 +35,12 @@ const (
 )
 
 // NewManager creates a new Manager
-func NewManager(configs map[string]*runtime.ServiceInfo, defaultRoundTripper http.RoundTripper, metricsRegistry metrics.Registry, routinePool *safe.Pool, api http.Handler, rest http.Handler) *Manager {
+func NewManager(configs map[string]*runtime.ServiceInfo, roundTripperManager *RoundTripperManager, metricsRegistry metrics.Registry, routinePool *safe.Pool, api http.Handler, rest http.Handler) *Manager {
 	return &Manager{
 		routinePool:         routinePool,
 		metricsRegistry:     metricsRegistry,
 		bufferPool:          newBufferPool(),
-		defaultRoundTripper: defaultRoundTripper,
+		roundTripperManager: roundTripperManager,
 		balancers:           make(map[string][]healthcheck.BalancerHandler),
 		configs:             configs,
 		api:                 api,
@@ -52,16 +53,23 @@ type Manager struct {
 	routinePool         *safe.Pool
 	metricsRegistry     metrics.Registry
 	bufferPool          httputil.BufferPool
-	defaultRoundTripper http.RoundTripper
+	roundTripperManager *RoundTripperManager
 	balancers           map[string][]healthcheck.BalancerHandler
 	configs             map[string]*runtime.ServiceInfo
+	weightsStore        *WeightsStore
 	api                 http.Handler
 	rest                http.Handler
 }
 
+// SetWeightsStore sets the store keeping the weights of the weighted services updated at runtime across the configuration reloads.
+func (m *Manager) SetWeightsStore(store *WeightsStore) {
+	m.weightsStore = store
+}
+
 // BuildHTTP Creates a http.Handler for a service configuration.
 func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, responseModifier func(*http.Response) error) (http.Handler, error) {
 	if serviceName == "api@internal" {
+		internal.GetDependencies(rootCtx).SetInternal()
 		if m.api == nil {
 			return nil, errors.New("api is not enabled")
 		}
@@ -69,6 +77,7 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, respons
 	}
 
 	if serviceName == "rest@internal" {
+		internal.GetDependencies(rootCtx).SetInternal()
 		if m.rest == nil {
 			return nil, errors.New("rest is not enabled")
 		}
@@ -80,6 +89,8 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string, respons
 	serviceName = internal.GetQualifiedName(ctx, serviceName)
 	ctx = internal.AddProviderInContext(ctx, serviceName)
 
+	internal.GetDependencies(ctx).AddService(serviceName)
+
 	conf, ok := m.configs[serviceName]
 	if !ok {
 		return nil, fmt.Errorf("the service %q does not exist", serviceName)
@@ -137,14 +148,25 @@ func (m *Manager) getLoadBalancerMirrorServiceHandler(ctx context.Context, servi
 		return nil, err
 	}
 
-	handler := mirror.New(serviceHandler, m.routinePool)
+	// The bodies are buffered up to 1 MiB by default.
+	maxBodySize := int64(1 << 20)
+	if config.MaxBodySize != nil {
+		maxBodySize = *config.MaxBodySize
+	}
+
+	var comparator *mirror.Comparator
+	if config.Compare != nil {
+		comparator = mirror.NewComparator(serviceName, *config.Compare, m.metricsRegistry)
+	}
+
+	handler := mirror.New(serviceHandler, m.routinePool, maxBodySize, comparator)
 	for _, mirrorConfig := range config.Mirrors {
 		mirrorHandler, err := m.BuildHTTP(ctx, mirrorConfig.Name, responseModifier)
 		if err != nil {
 			return nil, err
 		}
 
-		err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+		err = handler.AddMirror(mirrorConfig.Name, mirrorHandler, mirrorConfig.Percent)
 		if err != nil {
 			return nil, err
 		}
@@ -158,7 +180,11 @@ func (m *Manager) getLoadBalancerWRRServiceHandler(ctx context.Context, serviceN
 		config.Sticky.Cookie.Name = cookie.GetName(config.Sticky.Cookie.Name, serviceName)
 	}
 
-	balancer := wrr.New(config.Sticky)
+	balancer, err := wrr.New(config.Sticky)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, service := range config.Services {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name, responseModifier)
 		if err != nil {
@@ -166,7 +192,45 @@ func (m *Manager) getLoadBalancerWRRServiceHandler(ctx context.Context, serviceN
 		}
 
 		balancer.AddService(service.Name, serviceHandler, service.Weight)
+
+		if service.Match != nil {
+			if err := balancer.SetMatch(service.Name, service.Match); err != nil {
+				return nil, err
+			}
+		}
 	}
+
+	configured := getConfiguredWeights(config)
+
+	m.onServiceInfo(ctx, serviceName, func(info *runtime.ServiceInfo) {
+		// The weights updated at runtime survive the reloads, until the configured weights change.
+		weights := m.weightsStore.get(serviceName, configured)
+
+		for name, weight := range configured {
+			if runtimeWeight, ok := weights[name]; ok {
+				weight = runtimeWeight
+			}
+			if err := balancer.SetWeight(name, weight); err != nil {
+				log.FromContext(ctx).Errorf("Unable to set the weight of %s: %v", name, err)
+			}
+		}
+
+		info.AddWeightUpdater(func(name string, weight int) error {
+			if err := balancer.SetWeight(name, weight); err != nil {
+				return err
+			}
+
+			m.weightsStore.set(serviceName, configured, name, weight)
+			return nil
+		})
+
+		if len(weights) > 0 {
+			if err := info.UpdateWeights(weights); err != nil {
+				log.FromContext(ctx).Errorf("Unable to restore the weights updated at runtime: %v", err)
+			}
+		}
+	})
+
 	return balancer, nil
 }
 
@@ -181,7 +245,12 @@ func (m *Manager) getLoadBalancerServiceHandler(
 		service.PassHostHeader = &defaultPassHostHeader
 	}
 
-	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, m.defaultRoundTripper, m.bufferPool, responseModifier)
+	roundTripper, err := m.getRoundTripper(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool, responseModifier)
 	if err != nil {
 		return nil, err
 	}
@@ -206,11 +275,44 @@ func (m *Manager) getLoadBalancerServiceHandler(
 
 	// TODO rename and checks
 	m.balancers[serviceName] = append(m.balancers[serviceName], balancer)
+	internal.GetDependencies(ctx).AddBalancer(serviceName, balancer)
 
 	// Empty (backend with no servers)
 	return emptybackendhandler.New(balancer), nil
 }
 
+func (m *Manager) getRoundTripper(ctx context.Context, service *dynamic.ServersLoadBalancer) (http.RoundTripper, error) {
+	if len(service.ServersTransport) == 0 {
+		return m.roundTripperManager.Get("")
+	}
+
+	serversTransportName := internal.GetQualifiedName(ctx, service.ServersTransport)
+	internal.GetDependencies(ctx).AddServersTransport(serversTransportName)
+
+	return m.roundTripperManager.Get(serversTransportName)
+}
+
+// AddBalancers adds the load-balancers of a reused handler to the ones to health check.
+func (m *Manager) AddBalancers(balancers map[string][]healthcheck.BalancerHandler) {
+	for serviceName, serviceBalancers := range balancers {
+		m.balancers[serviceName] = append(m.balancers[serviceName], serviceBalancers...)
+	}
+}
+
+// onServiceInfo calls fn with the runtime information of the service,
+// and records the call to be replayed by the reloads reusing the handler being built.
+func (m *Manager) onServiceInfo(ctx context.Context, serviceName string, fn func(info *runtime.ServiceInfo)) {
+	if info, ok := m.configs[serviceName]; ok {
+		fn(info)
+	}
+
+	internal.GetDependencies(ctx).AddRegistration(func(conf *runtime.Configuration) {
+		if info, ok := conf.Services[serviceName]; ok {
+			fn(info)
+		}
+	})
+}
+
 // LaunchHealthCheck Launches the health checks.
 func (m *Manager) LaunchHealthCheck() {
 	backendConfigs := make(map[string]*healthcheck.BackendConfig)
@@ -229,7 +331,13 @@ func (m *Manager) LaunchHealthCheck() {
 		if hcOpts := buildHealthCheckOptions(ctx, balancer, serviceName, service.HealthCheck); hcOpts != nil {
 			log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
-			hcOpts.Transport = m.defaultRoundTripper
+			roundTripper, err := m.getRoundTripper(internal.AddProviderInContext(ctx, serviceName), service)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Cannot set up healthcheck for service %s: %v", serviceName, err)
+				continue
+			}
+
+			hcOpts.Transport = roundTripper
 			backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, serviceName)
 		}
 
@@ -243,12 +351,22 @@ func (m *Manager) LaunchHealthCheck() {
 }
 
 func buildHealthCheckOptions(ctx context.Context, lb healthcheck.BalancerHandler, backend string, hc *dynamic.HealthCheck) *healthcheck.Options {
-	if hc == nil || hc.Path == "" {
+	if hc == nil || (hc.Path == "" && hc.Mode != healthcheck.ModeGRPC) {
 		return nil
 	}
 
 	logger := log.FromContext(ctx)
 
+	mode := hc.Mode
+	if mode == "" {
+		mode = healthcheck.ModeHTTP
+	}
+
+	if mode != healthcheck.ModeHTTP && mode != healthcheck.ModeGRPC {
+		logger.Errorf("Illegal health check mode for '%s': %s", backend, hc.Mode)
+		return nil
+	}
+
 	interval := defaultHealthCheckInterval
 	if hc.Interval != "" {
 		intervalOverride, err := time.ParseDuration(hc.Interval)
@@ -280,6 +398,7 @@ func buildHealthCheckOptions(ctx context.Context, lb healthcheck.BalancerHandler
 	}
 
 	return &healthcheck.Options{
+		Mode:     mode,
 		Scheme:   hc.Scheme,
 		Path:     hc.Path,
 		Port:     hc.Port,
@@ -295,22 +414,38 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	logger := log.FromContext(ctx)
 	logger.Debug("Creating load-balancer")
 
-	var options []roundrobin.LBOption
-
-	var cookieName string
-	if service.Sticky != nil && service.Sticky.Cookie != nil {
-		cookieName = cookie.GetName(service.Sticky.Cookie.Name, serviceName)
-		opts := roundrobin.CookieOptions{HTTPOnly: service.Sticky.Cookie.HTTPOnly, Secure: service.Sticky.Cookie.Secure}
-		options = append(options, roundrobin.EnableStickySession(roundrobin.NewStickySessionWithOptions(cookieName, opts)))
-		logger.Debugf("Sticky session cookie name: %v", cookieName)
-	}
-
-	lb, err := roundrobin.New(fwd, options...)
+	hashKey, err := hash.NewKeyFunc(service.Sticky)
 	if err != nil {
 		return nil, err
 	}
 
+	var lb healthcheck.BalancerHandler
+	if hashKey != nil {
+		logger.Debug("Sticky sessions based on a consistent hash")
+
+		lb, err = hash.New(fwd, hashKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var options []roundrobin.LBOption
+
+		var cookieName string
+		if service.Sticky != nil && service.Sticky.Cookie != nil {
+			cookieName = cookie.GetName(service.Sticky.Cookie.Name, serviceName)
+			opts := roundrobin.CookieOptions{HTTPOnly: service.Sticky.Cookie.HTTPOnly, Secure: service.Sticky.Cookie.Secure}
+			options = append(options, roundrobin.EnableStickySession(roundrobin.NewStickySessionWithOptions(cookieName, opts)))
+			logger.Debugf("Sticky session cookie name: %v", cookieName)
+		}
+
+		lb, err = roundrobin.New(fwd, options...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
+	m.onServiceInfo(ctx, serviceName, lbsu.SetServiceInfo)
 	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
 		return nil, fmt.Errorf("error configuring load balancer for service %s: %v", serviceName, err)
 	}
@@ -60,6 +60,32 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Succeeds when sticky.header is set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Sticky: &dynamic.Sticky{Header: &dynamic.StickyHeader{Name: "X-Client"}},
+				Servers: []dynamic.Server{
+					{
+						URL: "http://foo",
+					},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails when several sticky sessions are set",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Sticky: &dynamic.Sticky{
+					Cookie:   &dynamic.Cookie{},
+					SourceIP: &dynamic.StickySourceIP{},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -80,7 +106,7 @@ func TestGetLoadBalancer(t *testing.T) {
 }
 
 func TestGetLoadBalancerServiceHandler(t *testing.T) {
-	sm := NewManager(nil, http.DefaultTransport, nil, nil, nil, nil)
+	sm := NewManager(nil, NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
 
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-From", "first")
@@ -332,7 +358,7 @@ func TestManager_Build(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			manager := NewManager(test.configs, http.DefaultTransport, nil, nil, nil, nil)
+			manager := NewManager(test.configs, NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -345,6 +371,82 @@ func TestManager_Build(t *testing.T) {
 	}
 }
 
+func TestManager_runtimeWeightsAcrossReloads(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("server", name)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	v1 := newServer("v1")
+	v2 := newServer("v2")
+
+	store := NewWeightsStore()
+
+	// reload builds the weighted service, as a configuration reload does, and returns its runtime information.
+	reload := func(v2Weight int) (http.Handler, *runtime.ServiceInfo) {
+		v1Weight := 1
+		configs := map[string]*runtime.ServiceInfo{
+			"canary@file": {
+				Service: &dynamic.Service{
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{
+							{Name: "v1@file", Weight: &v1Weight},
+							{Name: "v2@file", Weight: &v2Weight},
+						},
+					},
+				},
+			},
+			"v1@file": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: v1.URL}}},
+				},
+			},
+			"v2@file": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{Servers: []dynamic.Server{{URL: v2.URL}}},
+				},
+			},
+		}
+
+		manager := NewManager(configs, NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
+		manager.SetWeightsStore(store)
+
+		handler, err := manager.BuildHTTP(context.Background(), "canary@file", nil)
+		require.NoError(t, err)
+
+		return handler, configs["canary@file"]
+	}
+
+	servedBy := func(handler http.Handler) []string {
+		var servers []string
+		for i := 0; i < 4; i++ {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+			servers = append(servers, recorder.Header().Get("server"))
+		}
+		return servers
+	}
+
+	_, info := reload(1)
+	require.NoError(t, info.UpdateWeights(map[string]int{"v2@file": 0}))
+
+	// The weights updated at runtime survive a reload keeping the configured weights.
+	handler, info := reload(1)
+	assert.Equal(t, map[string]int{"v2@file": 0}, info.GetWeights())
+	assert.Equal(t, []string{"v1", "v1", "v1", "v1"}, servedBy(handler))
+
+	// They are forgotten once the configured weights change.
+	handler, info = reload(3)
+	assert.Nil(t, info.GetWeights())
+	assert.ElementsMatch(t, []string{"v1", "v2", "v2", "v2"}, servedBy(handler))
+
+	handler, _ = reload(1)
+	assert.ElementsMatch(t, []string{"v1", "v1", "v2", "v2"}, servedBy(handler))
+}
+
 func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	manager := NewManager(map[string]*runtime.ServiceInfo{
 		"test@file": {
@@ -353,7 +455,7 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 				Weighted:     &dynamic.WeightedRoundRobin{},
 			},
 		},
-	}, http.DefaultTransport, nil, nil, nil, nil)
+	}, NewRoundTripperManager(http.DefaultTransport), nil, nil, nil, nil)
 
 	_, err := manager.BuildHTTP(context.Background(), "test@file", nil)
 	assert.Error(t, err, "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
@@ -0,0 +1,125 @@
+package tcp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/server/internal"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/mitchellh/hashstructure"
+)
+
+// HandlerCache keeps the handlers of the TCP services across the configuration reloads,
+// so that a reload only rebuilds the services whose configuration, or the configuration of their weighted services, changed.
+// A nil HandlerCache keeps nothing.
+type HandlerCache struct {
+	lock     sync.Mutex
+	conf     *dynamic.TCPConfiguration
+	handlers map[string]*cachedHandler
+}
+
+type cachedHandler struct {
+	hash    uint64
+	handler tcp.Handler
+}
+
+// NewHandlerCache creates a new HandlerCache.
+func NewHandlerCache() *HandlerCache {
+	return &HandlerCache{
+		conf:     &dynamic.TCPConfiguration{},
+		handlers: make(map[string]*cachedHandler),
+	}
+}
+
+// Update sets the configuration the handlers of the following reload are built from,
+// and evicts the handlers of the services that do not exist anymore.
+// The given configuration must not be mutated while the handlers are built.
+func (c *HandlerCache) Update(conf *dynamic.TCPConfiguration) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if conf == nil {
+		conf = &dynamic.TCPConfiguration{}
+	}
+	c.conf = conf
+
+	for serviceName := range c.handlers {
+		if _, ok := conf.Services[serviceName]; !ok {
+			delete(c.handlers, serviceName)
+		}
+	}
+}
+
+// get returns the handler of the service, if its configuration did not change since it was built.
+func (c *HandlerCache) get(serviceName string) (tcp.Handler, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.handlers[serviceName]
+	if !ok {
+		return nil, false
+	}
+
+	hash, err := c.hash(serviceName)
+	if err != nil || hash != cached.hash {
+		delete(c.handlers, serviceName)
+		return nil, false
+	}
+
+	return cached.handler, true
+}
+
+// set keeps the handler of the service.
+func (c *HandlerCache) set(serviceName string, handler tcp.Handler) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hash, err := c.hash(serviceName)
+	if err != nil {
+		return
+	}
+
+	c.handlers[serviceName] = &cachedHandler{hash: hash, handler: handler}
+}
+
+// hash hashes the configuration of the service, along with the configuration of the services it weights.
+func (c *HandlerCache) hash(serviceName string) (uint64, error) {
+	services := make(map[string]*dynamic.TCPService)
+	c.collect(serviceName, services)
+
+	return hashstructure.Hash(services, nil)
+}
+
+// collect adds the configuration of the service, and of the services it weights, to the given ones.
+// The services which do not exist are collected too, as nil values.
+func (c *HandlerCache) collect(serviceName string, services map[string]*dynamic.TCPService) {
+	if _, ok := services[serviceName]; ok {
+		return
+	}
+
+	service := c.conf.Services[serviceName]
+	services[serviceName] = service
+
+	if service == nil || service.Weighted == nil {
+		return
+	}
+
+	// The weighted services are qualified with the provider of the service, as when they are built.
+	ctx := internal.AddProviderInContext(context.Background(), serviceName)
+	for _, child := range service.Weighted.Services {
+		c.collect(internal.GetQualifiedName(ctx, child.Name), services)
+	}
+}
@@ -0,0 +1,92 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/containous/traefik/v2/pkg/config/runtime"
+	"github.com/containous/traefik/v2/pkg/server/internal"
+	"github.com/containous/traefik/v2/pkg/tcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerCache(t *testing.T) {
+	cache := NewHandlerCache()
+
+	build := func(conf *dynamic.TCPConfiguration) map[string]tcp.Handler {
+		cache.Update(conf)
+
+		// Building the handlers mutates the configuration, which the cache must not see.
+		rtConf := runtime.NewConfig(dynamic.Configuration{TCP: conf.DeepCopy()})
+		manager := NewManager(rtConf)
+		manager.SetCache(cache)
+
+		ctx := internal.AddProviderInContext(context.Background(), "foo@file")
+
+		handlers := make(map[string]tcp.Handler)
+		for _, serviceName := range []string{"foo", "bar", "baz"} {
+			handler, err := manager.BuildTCP(ctx, serviceName)
+			require.NoError(t, err)
+			handlers[serviceName] = handler
+		}
+		return handlers
+	}
+
+	newConf := func() *dynamic.TCPConfiguration {
+		return &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"foo@file": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "127.0.0.1:8080"}},
+					},
+				},
+				"bar@file": {
+					Weighted: &dynamic.TCPWeightedRoundRobin{
+						Services: []dynamic.TCPWRRService{{Name: "foo"}},
+					},
+				},
+				"baz@file": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "127.0.0.1:8081"}},
+					},
+				},
+			},
+		}
+	}
+
+	previous := build(newConf())
+
+	// Nothing changed: the handlers are reused.
+	current := build(newConf())
+
+	assert.True(t, previous["foo"] == current["foo"])
+	assert.True(t, previous["bar"] == current["bar"])
+
+	// Another service changed: only this service is rebuilt.
+	conf := newConf()
+	conf.Services["baz@file"].LoadBalancer.Servers[0].Address = "127.0.0.1:8082"
+	previous = current
+	current = build(conf)
+
+	assert.True(t, previous["foo"] == current["foo"])
+	assert.True(t, previous["bar"] == current["bar"])
+	assert.False(t, previous["baz"] == current["baz"])
+
+	// The service weighted by another one changed: both are rebuilt.
+	conf.Services["foo@file"].LoadBalancer.Servers[0].Address = "127.0.0.1:8082"
+	previous = current
+	current = build(conf)
+
+	assert.False(t, previous["foo"] == current["foo"])
+	assert.False(t, previous["bar"] == current["bar"])
+	assert.True(t, previous["baz"] == current["baz"])
+
+	// The handlers of the removed services are evicted.
+	delete(conf.Services, "baz@file")
+	cache.Update(conf)
+
+	require.Contains(t, cache.handlers, "foo@file")
+	assert.NotContains(t, cache.handlers, "baz@file")
+}
@@ -16,6 +16,7 @@ import (
 // Manager is the TCPHandlers factory
 type Manager struct {
 	configs map[string]*runtime.TCPServiceInfo
+	cache   *HandlerCache
 }
 
 // NewManager creates a new manager
@@ -25,6 +26,11 @@ func NewManager(conf *runtime.Configuration) *Manager {
 	}
 }
 
+// SetCache sets the cache keeping the service handlers across the configuration reloads.
+func (m *Manager) SetCache(cache *HandlerCache) {
+	m.cache = cache
+}
+
 // BuildTCP Creates a tcp.Handler for a service configuration.
 func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Handler, error) {
 	serviceQualifiedName := internal.GetQualifiedName(rootCtx, serviceName)
@@ -42,15 +48,34 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, err
 	}
 
+	if conf.LoadBalancer != nil && conf.LoadBalancer.TerminationDelay == nil {
+		defaultTerminationDelay := 100
+		conf.LoadBalancer.TerminationDelay = &defaultTerminationDelay
+	}
+
+	if handler, ok := m.cache.get(serviceQualifiedName); ok {
+		return handler, nil
+	}
+
+	handler, err := m.buildTCP(ctx, rootCtx, serviceQualifiedName, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cache.set(serviceQualifiedName, handler)
+
+	return handler, nil
+}
+
+func (m *Manager) buildTCP(ctx, rootCtx context.Context, serviceQualifiedName string, conf *runtime.TCPServiceInfo) (tcp.Handler, error) {
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
-
-		if conf.LoadBalancer.TerminationDelay == nil {
-			defaultTerminationDelay := 100
-			conf.LoadBalancer.TerminationDelay = &defaultTerminationDelay
+		if conf.LoadBalancer.Sticky != nil && conf.LoadBalancer.Sticky.SourceIP {
+			loadBalancer.EnableSourceIPAffinity()
 		}
+
 		duration := time.Millisecond * time.Duration(*conf.LoadBalancer.TerminationDelay)
 
 		for name, server := range conf.LoadBalancer.Servers {
@@ -59,25 +84,28 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration)
+			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol)
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddNamedServer(server.Address, handler, nil)
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
+		if conf.Weighted.Sticky != nil && conf.Weighted.Sticky.SourceIP {
+			loadBalancer.EnableSourceIPAffinity()
+		}
 		for _, service := range conf.Weighted.Services {
 			handler, err := m.BuildTCP(rootCtx, service.Name)
 			if err != nil {
 				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
 				return nil, err
 			}
-			loadBalancer.AddWeightServer(handler, service.Weight)
+			loadBalancer.AddNamedServer(service.Name, handler, service.Weight)
 		}
 		return loadBalancer, nil
 	default:
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+)
+
+// WeightsStore keeps the weights of the weighted services updated at runtime across the configuration reloads,
+// until the configured weights of the service change.
+// A nil WeightsStore keeps nothing.
+type WeightsStore struct {
+	lock     sync.Mutex
+	services map[string]*runtimeWeights
+}
+
+type runtimeWeights struct {
+	// configured are the configured weights the runtime weights apply to.
+	configured map[string]int
+	weights    map[string]int
+}
+
+// NewWeightsStore creates a new WeightsStore.
+func NewWeightsStore() *WeightsStore {
+	return &WeightsStore{services: make(map[string]*runtimeWeights)}
+}
+
+// get returns the weights updated at runtime for the service, forgetting them if the service is now configured with other weights.
+func (s *WeightsStore) get(serviceName string, configured map[string]int) map[string]int {
+	if s == nil {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	service, ok := s.services[serviceName]
+	if !ok {
+		return nil
+	}
+
+	if !reflect.DeepEqual(service.configured, configured) {
+		delete(s.services, serviceName)
+		return nil
+	}
+
+	weights := make(map[string]int, len(service.weights))
+	for name, weight := range service.weights {
+		weights[name] = weight
+	}
+	return weights
+}
+
+// set records the weight of a child service updated at runtime, for the service configured with the given weights.
+func (s *WeightsStore) set(serviceName string, configured map[string]int, child string, weight int) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	service, ok := s.services[serviceName]
+	if !ok || !reflect.DeepEqual(service.configured, configured) {
+		service = &runtimeWeights{configured: configured, weights: make(map[string]int)}
+		s.services[serviceName] = service
+	}
+
+	service.weights[child] = weight
+}
+
+// getConfiguredWeights returns the configured weights of the children services of a weighted service.
+func getConfiguredWeights(config *dynamic.WeightedRoundRobin) map[string]int {
+	configured := make(map[string]int, len(config.Services))
+	for _, service := range config.Services {
+		weight := 1
+		if service.Weight != nil {
+			weight = *service.Weight
+		}
+		configured[service.Name] = weight
+	}
+	return configured
+}
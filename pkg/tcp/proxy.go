@@ -1,10 +1,12 @@
 package tcp
 
 import (
+	"fmt"
 	"io"
 	"net"
 	"time"
 
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
@@ -12,16 +14,28 @@ import (
 type Proxy struct {
 	target           *net.TCPAddr
 	terminationDelay time.Duration
+	proxyProtocol    *dynamic.ProxyProtocol
 }
 
 // NewProxy creates a new Proxy
-func NewProxy(address string, terminationDelay time.Duration) (*Proxy, error) {
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol) (*Proxy, error) {
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay}, nil
+	if proxyProtocol != nil {
+		// An unset version, e.g. from the CRDs which do not apply the defaults, is the default version 2.
+		if proxyProtocol.Version == 0 {
+			proxyProtocol = &dynamic.ProxyProtocol{Version: 2}
+		}
+
+		if proxyProtocol.Version < 1 || proxyProtocol.Version > 2 {
+			return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
+		}
+	}
+
+	return &Proxy{target: tcpAddr, terminationDelay: terminationDelay, proxyProtocol: proxyProtocol}, nil
 }
 
 // ServeTCP forwards the connection to a service
@@ -40,6 +54,14 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	if p.proxyProtocol != nil {
+		// The addresses of the connection are the ones of the incoming PROXY protocol header, if any.
+		if err := writeProxyProtocolHeader(connBackend, p.proxyProtocol.Version, conn.RemoteAddr(), conn.LocalAddr()); err != nil {
+			log.Errorf("Error while writing PROXY protocol header: %v", err)
+			return
+		}
+	}
+
 	errChan := make(chan error)
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
@@ -0,0 +1,84 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// proxyProtocolV2Signature is the signature starting the binary PROXY protocol headers.
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// writeProxyProtocolHeader writes a PROXY protocol header holding the source and destination addresses of a connection.
+// If the addresses are not TCP addresses of the same family, the header does not hold them,
+// and the server uses the addresses of the connection as-is.
+func writeProxyProtocolHeader(w io.Writer, version int, src, dst net.Addr) error {
+	var header []byte
+
+	switch version {
+	case 1:
+		header = proxyProtocolV1Header(src, dst)
+	case 2:
+		header = proxyProtocolV2Header(src, dst)
+	default:
+		return fmt.Errorf("unknown proxyProtocol version: %d", version)
+	}
+
+	_, err := w.Write(header)
+	return err
+}
+
+func proxyProtocolV1Header(src, dst net.Addr) []byte {
+	srcAddr, srcOK := src.(*net.TCPAddr)
+	dstAddr, dstOK := dst.(*net.TCPAddr)
+	if !srcOK || !dstOK {
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+
+	var protocol string
+	switch {
+	case srcAddr.IP.To4() != nil && dstAddr.IP.To4() != nil:
+		protocol = "TCP4"
+	case srcAddr.IP.To4() == nil && dstAddr.IP.To4() == nil:
+		protocol = "TCP6"
+	default:
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+
+	return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n", protocol, srcAddr.IP, dstAddr.IP, srcAddr.Port, dstAddr.Port))
+}
+
+func proxyProtocolV2Header(src, dst net.Addr) []byte {
+	var buf bytes.Buffer
+	buf.Write(proxyProtocolV2Signature)
+
+	srcAddr, srcOK := src.(*net.TCPAddr)
+	dstAddr, dstOK := dst.(*net.TCPAddr)
+	if !srcOK || !dstOK {
+		// LOCAL command, with an unspecified family and no addresses.
+		buf.Write([]byte{0x20, 0x00, 0x00, 0x00})
+		return buf.Bytes()
+	}
+
+	var srcIP, dstIP net.IP
+	if srcAddr.IP.To4() != nil && dstAddr.IP.To4() != nil {
+		srcIP, dstIP = srcAddr.IP.To4(), dstAddr.IP.To4()
+		// PROXY command, over TCP/IPv4.
+		buf.Write([]byte{0x21, 0x11})
+	} else {
+		// The IPv4 addresses are mapped to IPv6 if the families differ.
+		srcIP, dstIP = srcAddr.IP.To16(), dstAddr.IP.To16()
+		// PROXY command, over TCP/IPv6.
+		buf.Write([]byte{0x21, 0x21})
+	}
+
+	_ = binary.Write(&buf, binary.BigEndian, uint16(2*len(srcIP)+4))
+	buf.Write(srcIP)
+	buf.Write(dstIP)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(srcAddr.Port))
+	_ = binary.Write(&buf, binary.BigEndian, uint16(dstAddr.Port))
+
+	return buf.Bytes()
+}
@@ -8,6 +8,9 @@ import (
 	"testing"
 	"time"
 
+	proxyprotocol "github.com/c0va23/go-proxyprotocol"
+	"github.com/containous/traefik/v2/pkg/config/dynamic"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,7 +49,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	proxy, err := NewProxy(":"+port, 10*time.Millisecond)
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil)
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -79,3 +82,210 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, int64(4), n)
 	require.Equal(t, "PONG", buffer.String())
 }
+
+func TestProxyProtocol(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		version        int
+		incoming       string
+		expectedSource string
+	}{
+		{
+			desc:    "version 1",
+			version: 1,
+		},
+		{
+			desc:    "version 2",
+			version: 2,
+		},
+		{
+			desc:           "version 1 with incoming header",
+			version:        1,
+			incoming:       "PROXY TCP4 1.2.3.4 5.6.7.8 1111 2222\r\n",
+			expectedSource: "1.2.3.4:1111",
+		},
+		{
+			desc:           "version 2 with incoming header",
+			version:        2,
+			incoming:       "PROXY TCP6 2001:db8::1 2001:db8::2 1111 2222\r\n",
+			expectedSource: "[2001:db8::1]:1111",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer backendListener.Close()
+
+			// The backend accepts the PROXY protocol headers, as the entry points do.
+			sources := make(chan string, 1)
+			go func() {
+				conn, err := trustedProxyProtocolListener(backendListener).Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				sources <- conn.RemoteAddr().String()
+				_, _ = io.Copy(conn, conn)
+			}()
+
+			proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version})
+			require.NoError(t, err)
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer proxyListener.Close()
+
+			go func() {
+				if test.incoming == "" {
+					conn, err := proxyListener.Accept()
+					if err != nil {
+						return
+					}
+					proxy.ServeTCP(conn.(*net.TCPConn))
+					return
+				}
+
+				// The entry point accepts the PROXY protocol headers.
+				conn, err := trustedProxyProtocolListener(proxyListener).Accept()
+				if err != nil {
+					return
+				}
+				proxy.ServeTCP(proxyProtocolConn{Conn: conn, tcpConn: conn.(*proxyprotocol.Conn).Conn.(*net.TCPConn)})
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			_, err = conn.Write([]byte(test.incoming + "ping"))
+			require.NoError(t, err)
+
+			buf := make([]byte, 4)
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+			assert.Equal(t, "ping", string(buf))
+
+			expectedSource := test.expectedSource
+			if expectedSource == "" {
+				expectedSource = conn.LocalAddr().String()
+			}
+			assert.Equal(t, expectedSource, <-sources)
+		})
+	}
+}
+
+func TestNewProxy_proxyProtocolVersion(t *testing.T) {
+	_, err := NewProxy("127.0.0.1:80", 10*time.Millisecond, &dynamic.ProxyProtocol{Version: 3})
+	assert.Error(t, err)
+
+	_, err = NewProxy("127.0.0.1:80", 10*time.Millisecond, &dynamic.ProxyProtocol{Version: -1})
+	assert.Error(t, err)
+
+	// An unset version is the default version.
+	proxy, err := NewProxy("127.0.0.1:80", 10*time.Millisecond, &dynamic.ProxyProtocol{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, proxy.proxyProtocol.Version)
+}
+
+func TestWriteProxyProtocolHeader(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		version  int
+		src      net.Addr
+		dst      net.Addr
+		expected []byte
+	}{
+		{
+			desc:     "version 1 over IPv4",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 1111},
+			dst:      &net.TCPAddr{IP: net.ParseIP("5.6.7.8"), Port: 2222},
+			expected: []byte("PROXY TCP4 1.2.3.4 5.6.7.8 1111 2222\r\n"),
+		},
+		{
+			desc:     "version 1 over IPv6",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 1111},
+			dst:      &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2222},
+			expected: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 1111 2222\r\n"),
+		},
+		{
+			desc:     "version 1 with mixed families",
+			version:  1,
+			src:      &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 1111},
+			dst:      &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2222},
+			expected: []byte("PROXY UNKNOWN\r\n"),
+		},
+		{
+			desc:     "version 1 with unix addresses",
+			version:  1,
+			src:      &net.UnixAddr{Name: "@", Net: "unix"},
+			dst:      &net.UnixAddr{Name: "/var/run/traefik.sock", Net: "unix"},
+			expected: []byte("PROXY UNKNOWN\r\n"),
+		},
+		{
+			desc:    "version 2 over IPv4",
+			version: 2,
+			src:     &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 1111},
+			dst:     &net.TCPAddr{IP: net.ParseIP("5.6.7.8"), Port: 2222},
+			expected: append([]byte("\r\n\r\n\x00\r\nQUIT\n"),
+				0x21, 0x11, 0x00, 0x0C,
+				1, 2, 3, 4,
+				5, 6, 7, 8,
+				0x04, 0x57, 0x08, 0xAE,
+			),
+		},
+		{
+			desc:    "version 2 with mixed families",
+			version: 2,
+			src:     &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 1111},
+			dst:     &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2222},
+			expected: append([]byte("\r\n\r\n\x00\r\nQUIT\n"),
+				0x21, 0x21, 0x00, 0x24,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xFF, 0xFF, 1, 2, 3, 4,
+				0x20, 0x01, 0x0D, 0xB8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2,
+				0x04, 0x57, 0x08, 0xAE,
+			),
+		},
+		{
+			desc:     "version 2 with unix addresses",
+			version:  2,
+			src:      &net.UnixAddr{Name: "@", Net: "unix"},
+			dst:      &net.UnixAddr{Name: "/var/run/traefik.sock", Net: "unix"},
+			expected: append([]byte("\r\n\r\n\x00\r\nQUIT\n"), 0x20, 0x00, 0x00, 0x00),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			err := writeProxyProtocolHeader(&buf, test.version, test.src, test.dst)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buf.Bytes())
+		})
+	}
+}
+
+func trustedProxyProtocolListener(listener net.Listener) net.Listener {
+	return proxyprotocol.NewDefaultListener(listener).
+		WithSourceChecker(func(net.Addr) (bool, error) { return true, nil })
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+	tcpConn *net.TCPConn
+}
+
+func (c proxyProtocolConn) CloseWrite() error {
+	return c.tcpConn.CloseWrite()
+}
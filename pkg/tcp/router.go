@@ -4,64 +4,96 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/containous/traefik/v2/pkg/log"
+	"github.com/containous/traefik/v2/pkg/rules"
 )
 
 // Router is a TCP router
 type Router struct {
-	routingTable      map[string]Handler
-	httpForwarder     Handler
-	httpsForwarder    Handler
-	httpHandler       http.Handler
-	httpsHandler      http.Handler
-	httpsTLSConfig    *tls.Config // default TLS config
-	catchAllNoTLS     Handler
-	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	routes              routes // routes matching the TLS connections
+	routesNoTLS         routes // routes matching the non-TLS connections
+	httpForwarder       Handler
+	httpsForwarder      Handler
+	hostHTTPSForwarders map[string]Handler
+	httpHandler         http.Handler
+	httpsHandler        http.Handler
+	httpsTLSConfig      *tls.Config            // default TLS config
+	hostHTTPTLSConfig   map[string]*tls.Config // TLS configs keyed by SNI
+	http2Disabled       bool
 }
 
 // ServeTCP forwards the connection to the right TCP/HTTP handler
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 && r.httpsHandler == nil {
-		r.catchAllNoTLS.ServeTCP(conn)
-		return
+	remoteIP := getRemoteIP(conn)
+
+	// Without any TLS route, the non-TLS routes can be matched without reading from the connection,
+	// which is needed for the server-first protocols.
+	if len(r.routesNoTLS) > 0 && len(r.routes) == 0 && r.httpsHandler == nil {
+		if route := r.routesNoTLS.match(rules.ConnData{RemoteIP: remoteIP}); route != nil {
+			clearReadDeadline(conn)
+			route.handler.ServeTCP(conn)
+			return
+		}
 	}
 
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked := clientHelloServerName(br)
+	hello, tls, peeked, err := clientHelloInfo(br)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	clearReadDeadline(conn)
+
 	if !tls {
-		switch {
-		case r.catchAllNoTLS != nil:
-			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
-		case r.httpForwarder != nil:
+		if route := r.routesNoTLS.match(rules.ConnData{RemoteIP: remoteIP}); route != nil {
+			route.handler.ServeTCP(r.GetConn(conn, peeked))
+			return
+		}
+
+		if r.httpForwarder != nil {
 			r.httpForwarder.ServeTCP(r.GetConn(conn, peeked))
-		default:
+		} else {
 			conn.Close()
 		}
 		return
 	}
 
-	// FIXME Optimize and test the routing table before helloServerName
-	serverName = strings.ToLower(serverName)
-	if r.routingTable != nil && serverName != "" {
-		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
-			return
-		}
+	serverName := strings.ToLower(hello.serverName)
+
+	connData := rules.ConnData{
+		ServerName: serverName,
+		RemoteIP:   remoteIP,
+		ALPNProtos: hello.protos,
+	}
+	// The routes matching any server name yield to the HTTPS routers of the server name,
+	// which need their own TLS configuration.
+	route := r.routes.match(connData)
+	if route != nil && !route.catchAll {
+		route.handler.ServeTCP(r.GetConn(conn, peeked))
+		return
 	}
 
-	// FIXME Needs tests
-	if target, ok := r.routingTable["*"]; ok {
+	if target, ok := r.hostHTTPSForwarders[serverName]; ok && serverName != "" {
 		target.ServeTCP(r.GetConn(conn, peeked))
 		return
 	}
 
+	if route != nil {
+		route.handler.ServeTCP(r.GetConn(conn, peeked))
+		return
+	}
+
 	if r.httpsForwarder != nil {
 		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
 	} else {
@@ -69,33 +101,75 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 }
 
-// AddRoute defines a handler for a given sniHost (* is the only valid option)
-func (r *Router) AddRoute(sniHost string, target Handler) {
-	if r.routingTable == nil {
-		r.routingTable = map[string]Handler{}
-	}
-	r.routingTable[strings.ToLower(sniHost)] = target
+// AddRoute defines a handler for the TLS connections matching the given rule, without terminating TLS.
+// When priority is 0, the length of the rule is used.
+// The name identifies the route when explaining the routing of a connection.
+func (r *Router) AddRoute(name, rule string, priority int, target Handler) error {
+	return r.routes.add(name, rule, priority, target)
 }
 
-// AddRouteTLS defines a handler for a given sniHost and sets the matching tlsConfig
-func (r *Router) AddRouteTLS(sniHost string, target Handler, config *tls.Config) {
-	r.AddRoute(sniHost, &TLSHandler{
+// AddRouteTLS defines a handler for the TLS connections matching the given rule, and sets the matching tlsConfig.
+// When priority is 0, the length of the rule is used.
+func (r *Router) AddRouteTLS(name, rule string, priority int, target Handler, config *tls.Config) error {
+	return r.AddRoute(name, rule, priority, &TLSHandler{
 		Next:   target,
 		Config: config,
 	})
 }
 
+// AddRouteNoTLS defines a handler for the non-TLS connections matching the given rule.
+// When priority is 0, the length of the rule is used.
+func (r *Router) AddRouteNoTLS(name, rule string, priority int, target Handler) error {
+	return r.routesNoTLS.add(name, rule, priority, target)
+}
+
+// Explain evaluates the routes of the TLS, or non-TLS, connections against the connection data,
+// in the order they are matched, and returns the result for each of them.
+// The connection is handled by the first matching route, if any, and is otherwise forwarded to the HTTP(S) handler.
+// A TLS route matching any server name does not match the server names of the HTTPS routers.
+func (r *Router) Explain(data rules.ConnData, tls bool) []rules.RouteMatch {
+	routes := r.routesNoTLS
+	if tls {
+		routes = r.routes
+	}
+
+	matches := make([]rules.RouteMatch, 0, len(routes))
+	for _, rt := range routes {
+		match := rules.RouteMatch{
+			Name:     rt.name,
+			Rule:     rt.rule,
+			Priority: rt.priority,
+			Matched:  rt.matcher(data),
+		}
+		if match.Matched && tls && rt.catchAll && r.isHTTPSHost(data.ServerName) {
+			match.Matched = false
+			match.Reasons = []string{fmt.Sprintf("the server name %q is handled by the HTTPS routers", data.ServerName)}
+		}
+		if !match.Matched && match.Reasons == nil {
+			reasons, err := rules.ExplainTCP(rt.rule, data)
+			if err != nil {
+				reasons = []string{err.Error()}
+			}
+			match.Reasons = reasons
+		}
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// isHTTPSHost tells whether the server name has its own TLS configuration for the HTTPS routers.
+func (r *Router) isHTTPSHost(serverName string) bool {
+	_, ok := r.hostHTTPTLSConfig[serverName]
+	return ok && serverName != ""
+}
+
 // AddRouteHTTPTLS defines a handler for a given sniHost and sets the matching tlsConfig
 func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	if r.hostHTTPTLSConfig == nil {
 		r.hostHTTPTLSConfig = map[string]*tls.Config{}
 	}
-	r.hostHTTPTLSConfig[sniHost] = config
-}
-
-// AddCatchAllNoTLS defines the fallback tcp handler
-func (r *Router) AddCatchAllNoTLS(handler Handler) {
-	r.catchAllNoTLS = handler
+	r.hostHTTPTLSConfig[strings.ToLower(sniHost)] = config
 }
 
 // GetConn creates a connection proxy with a peeked string
@@ -125,14 +199,42 @@ func (r *Router) HTTPForwarder(handler Handler) {
 
 // HTTPSForwarder sets the tcp handler that will forward the TLS connections to an http handler
 func (r *Router) HTTPSForwarder(handler Handler) {
+	r.hostHTTPSForwarders = make(map[string]Handler, len(r.hostHTTPTLSConfig))
 	for sniHost, tlsConf := range r.hostHTTPTLSConfig {
-		r.AddRouteTLS(sniHost, handler, tlsConf)
+		r.hostHTTPSForwarders[sniHost] = &TLSHandler{
+			Next:   handler,
+			Config: r.forwarderTLSConfig(tlsConf),
+		}
 	}
 
 	r.httpsForwarder = &TLSHandler{
 		Next:   handler,
-		Config: r.httpsTLSConfig,
+		Config: r.forwarderTLSConfig(r.httpsTLSConfig),
+	}
+}
+
+// DisableHTTP2 stops negotiating HTTP/2 on the TLS connections forwarded to the https handler.
+// It must be called before HTTPSForwarder.
+func (r *Router) DisableHTTP2() {
+	r.http2Disabled = true
+}
+
+// forwarderTLSConfig returns the TLS config used to forward the connections to the https handler.
+func (r *Router) forwarderTLSConfig(config *tls.Config) *tls.Config {
+	if !r.http2Disabled || config == nil {
+		return config
+	}
+
+	config = config.Clone()
+	var protos []string
+	for _, proto := range config.NextProtos {
+		if proto != "h2" {
+			protos = append(protos, proto)
+		}
 	}
+	config.NextProtos = protos
+
+	return config
 }
 
 // HTTPHandler attaches http handlers on the router
@@ -146,6 +248,17 @@ func (r *Router) HTTPSHandler(handler http.Handler, config *tls.Config) {
 	r.httpsTLSConfig = config
 }
 
+// GetTLSGetClientInfo returns the function selecting the TLS config of the https handler from the SNI,
+// used to serve the HTTPS routers over QUIC.
+func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+	return func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+		if tlsConfig, ok := r.hostHTTPTLSConfig[strings.ToLower(info.ServerName)]; ok {
+			return tlsConfig, nil
+		}
+		return r.httpsTLSConfig, nil
+	}
+}
+
 // Conn is a connection proxy that handles Peeked bytes
 type Conn struct {
 	// Peeked are the bytes that have been read from Conn for the
@@ -173,44 +286,71 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
-// clientHelloServerName returns the SNI server name inside the TLS ClientHello,
+// clientHello holds the data of the TLS ClientHello used for the routing.
+type clientHello struct {
+	serverName string
+	protos     []string
+}
+
+// clientHelloInfo returns the SNI server name and the ALPN protocols inside the TLS ClientHello,
 // without consuming any bytes from br.
-// On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string) {
+// An error is returned when the first byte cannot be read, or when the read deadline is exceeded,
+// in which case the connection cannot be routed. On any other error, an empty clientHello is returned.
+func clientHelloInfo(br *bufio.Reader) (clientHello, bool, string, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
-		if err != io.EOF {
+		if err != io.EOF && !isTimeout(err) {
 			log.Errorf("Error while Peeking first byte: %s", err)
 		}
-		return "", false, ""
+		return clientHello{}, false, "", err
 	}
 	const recordTypeHandshake = 0x16
 	if hdr[0] != recordTypeHandshake {
 		// log.Errorf("Error not tls")
-		return "", false, getPeeked(br) // Not TLS.
+		return clientHello{}, false, getPeeked(br), nil // Not TLS.
 	}
 
 	const recordHeaderLen = 5
 	hdr, err = br.Peek(recordHeaderLen)
+	if isTimeout(err) {
+		return clientHello{}, false, "", err
+	}
 	if err != nil {
 		log.Errorf("Error while Peeking hello: %s", err)
-		return "", false, getPeeked(br)
+		return clientHello{}, false, getPeeked(br), nil
 	}
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
 	helloBytes, err := br.Peek(recordHeaderLen + recLen)
+	if isTimeout(err) {
+		return clientHello{}, false, "", err
+	}
 	if err != nil {
 		log.Errorf("Error while Hello: %s", err)
-		return "", true, getPeeked(br)
+		return clientHello{}, true, getPeeked(br), nil
 	}
-	sni := ""
+	info := clientHello{}
 	server := tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
 		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
-			sni = hello.ServerName
+			info.serverName = hello.ServerName
+			info.protos = hello.SupportedProtos
 			return nil, nil
 		},
 	})
 	_ = server.Handshake()
-	return sni, true, getPeeked(br)
+	return info, true, getPeeked(br), nil
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// clearReadDeadline removes the read deadline set by the entry point on the connection,
+// which only applies to the bytes read to route it.
+func clearReadDeadline(conn WriteCloser) {
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		log.WithoutContext().Debugf("Error while clearing read deadline: %v", err)
+	}
 }
 
 func getPeeked(br *bufio.Reader) string {
@@ -234,3 +374,76 @@ func (c sniSniffConn) Read(p []byte) (int, error) { return c.r.Read(p) }
 
 // Write crashes all the time
 func (sniSniffConn) Write(p []byte) (int, error) { return 0, io.EOF }
+
+// getRemoteIP returns the IP of the client of the connection.
+func getRemoteIP(conn WriteCloser) string {
+	remoteAddr := conn.RemoteAddr()
+	if remoteAddr == nil {
+		return ""
+	}
+
+	remoteIP, _, err := net.SplitHostPort(remoteAddr.String())
+	if err != nil {
+		return remoteAddr.String()
+	}
+	return remoteIP
+}
+
+type route struct {
+	name     string
+	rule     string
+	matcher  rules.TCPMatcher
+	priority int
+	handler  Handler
+	// catchAll is true when the rule does not match on a given server name.
+	catchAll bool
+}
+
+// routes are the routes of a Router, sorted by decreasing priority, then by name.
+type routes []*route
+
+func (r *routes) add(name, rule string, priority int, handler Handler) error {
+	matcher, err := rules.NewTCPMatcher(rule)
+	if err != nil {
+		return err
+	}
+
+	hosts, err := rules.ParseHostSNI(rule)
+	if err != nil {
+		return err
+	}
+
+	catchAll := true
+	for _, host := range hosts {
+		if host != "*" {
+			catchAll = false
+			break
+		}
+	}
+
+	if priority == 0 {
+		priority = len(rule)
+	}
+
+	*r = append(*r, &route{name: name, rule: rule, matcher: matcher, priority: priority, handler: handler, catchAll: catchAll})
+	// The routes with the same priority are sorted by name,
+	// so that the matching route does not depend on the order the routes are added in.
+	sort.Slice(*r, func(i, j int) bool {
+		if (*r)[i].priority != (*r)[j].priority {
+			return (*r)[i].priority > (*r)[j].priority
+		}
+		return (*r)[i].name < (*r)[j].name
+	})
+
+	return nil
+}
+
+// match returns the route with the highest priority matching the connection, or nil.
+func (r routes) match(data rules.ConnData) *route {
+	for _, rt := range r {
+		if rt.matcher(data) {
+			return rt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/v2/pkg/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		routes     map[string]string
+		routesTLS  map[string]string
+		httpsHosts []string
+		serverName string
+		alpnProtos []string
+		noTLS      bool
+		expected   string
+	}{
+		{
+			desc:     "Non-TLS catch-all",
+			routes:   map[string]string{"HostSNI(`*`)": "catchall"},
+			noTLS:    true,
+			expected: "catchall",
+		},
+		{
+			desc:     "Non-TLS route on client IP",
+			routes:   map[string]string{"HostSNI(`*`) && ClientIP(`127.0.0.1`)": "local"},
+			noTLS:    true,
+			expected: "local",
+		},
+		{
+			desc:     "Non-TLS route on another client IP",
+			routes:   map[string]string{"ClientIP(`10.0.0.0/8`)": "private"},
+			noTLS:    true,
+			expected: "",
+		},
+		{
+			desc:       "TLS route on SNI",
+			routesTLS:  map[string]string{"HostSNI(`foo.bar`)": "foo", "HostSNI(`bar.foo`)": "bar"},
+			serverName: "bar.foo",
+			expected:   "bar",
+		},
+		{
+			desc: "TLS routes on ALPN",
+			routesTLS: map[string]string{
+				"HostSNI(`foo.bar`) && ALPN(`h2`)":       "h2",
+				"HostSNI(`foo.bar`) && ALPN(`custom/1`)": "custom",
+				"HostSNI(`foo.bar`)":                     "default",
+			},
+			serverName: "foo.bar",
+			alpnProtos: []string{"custom/1"},
+			expected:   "custom",
+		},
+		{
+			desc: "TLS routes on negated ALPN",
+			routesTLS: map[string]string{
+				"HostSNI(`foo.bar`) && !ALPN(`h2`)": "other",
+				"HostSNI(`foo.bar`)":                "default",
+			},
+			serverName: "foo.bar",
+			alpnProtos: []string{"h2"},
+			expected:   "default",
+		},
+		{
+			desc: "TLS routes on client IP",
+			routesTLS: map[string]string{
+				"HostSNI(`*`) && ClientIP(`127.0.0.1`)": "local",
+				"HostSNI(`*`)":                          "catchall",
+			},
+			serverName: "foo.bar",
+			expected:   "local",
+		},
+		{
+			desc:       "TLS catch-all yields to the HTTPS routers of the server name",
+			routesTLS:  map[string]string{"HostSNI(`*`)": "catchall"},
+			httpsHosts: []string{"foo.bar"},
+			serverName: "foo.bar",
+			expected:   "https",
+		},
+		{
+			desc:       "TLS catch-all with another server name than the HTTPS routers",
+			routesTLS:  map[string]string{"HostSNI(`*`)": "catchall"},
+			httpsHosts: []string{"foo.bar"},
+			serverName: "bar.foo",
+			expected:   "catchall",
+		},
+		{
+			desc:       "TLS route on SNI before the HTTPS routers",
+			routesTLS:  map[string]string{"HostSNI(`foo.bar`)": "foo"},
+			httpsHosts: []string{"foo.bar"},
+			serverName: "foo.bar",
+			expected:   "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matched := make(chan string, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					matched <- name
+					conn.Close()
+				})
+			}
+
+			router := &Router{}
+			for _, host := range test.httpsHosts {
+				router.AddRouteHTTPTLS(host, &tls.Config{})
+			}
+			router.HTTPForwarder(handler(""))
+			router.HTTPSForwarder(handler("https"))
+
+			for rule, name := range test.routes {
+				require.NoError(t, router.AddRouteNoTLS(name, rule, 0, handler(name)))
+			}
+
+			for rule, name := range test.routesTLS {
+				require.NoError(t, router.AddRoute(name, rule, 0, handler(name)))
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			if test.noTLS {
+				_, err = conn.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+				require.NoError(t, err)
+			} else {
+				// The handshake fails as the connection is closed by the handler.
+				go func() {
+					_ = tls.Client(conn, &tls.Config{
+						ServerName:         test.serverName,
+						NextProtos:         test.alpnProtos,
+						InsecureSkipVerify: true,
+					}).Handshake()
+				}()
+			}
+
+			select {
+			case name := <-matched:
+				assert.Equal(t, test.expected, name)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the connection to be routed")
+			}
+		})
+	}
+}
+
+func TestRouter_ServeTCP_ReadDeadline(t *testing.T) {
+	matched := make(chan WriteCloser, 1)
+	router := &Router{}
+	router.HTTPForwarder(HandlerFunc(func(conn WriteCloser) {
+		matched <- conn
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		for i := 0; i < 2; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+			router.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	// The connection is closed without being routed when the deadline expires before its first bytes are read.
+	idleConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer idleConn.Close()
+
+	require.NoError(t, idleConn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = idleConn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	// The deadline is cleared once the connection is routed.
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("G"))
+	require.NoError(t, err)
+
+	select {
+	case routed := <-matched:
+		defer routed.Close()
+
+		time.Sleep(200 * time.Millisecond)
+		_, err = conn.Write([]byte("ET"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 3)
+		_, err = io.ReadFull(routed, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "GET", string(buf))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the connection to be routed")
+	}
+
+	<-served
+}
+
+func TestRouter_DisableHTTP2(t *testing.T) {
+	config := &tls.Config{NextProtos: []string{"h2", "http/1.1", "acme-tls/1"}}
+	hostConfig := &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+
+	router := &Router{}
+	router.HTTPSHandler(nil, config)
+	router.AddRouteHTTPTLS("foo.bar", hostConfig)
+	router.DisableHTTP2()
+	router.HTTPSForwarder(HandlerFunc(func(conn WriteCloser) {}))
+
+	forwarder, ok := router.httpsForwarder.(*TLSHandler)
+	require.True(t, ok)
+	assert.Equal(t, []string{"http/1.1", "acme-tls/1"}, forwarder.Config.NextProtos)
+
+	hostForwarder, ok := router.hostHTTPSForwarders["foo.bar"].(*TLSHandler)
+	require.True(t, ok)
+	assert.Equal(t, []string{"http/1.1"}, hostForwarder.Config.NextProtos)
+
+	// The configurations, shared with the other entry points, are left untouched.
+	assert.Equal(t, []string{"h2", "http/1.1", "acme-tls/1"}, config.NextProtos)
+	assert.Equal(t, []string{"h2", "http/1.1"}, hostConfig.NextProtos)
+}
+
+func TestRouter_Explain(t *testing.T) {
+	handler := HandlerFunc(func(conn WriteCloser) {})
+
+	router := &Router{}
+	require.NoError(t, router.AddRoute("foo", "HostSNI(`foo.bar`)", 0, handler))
+	require.NoError(t, router.AddRoute("bar", "HostSNI(`*`)", 1, handler))
+	require.NoError(t, router.AddRouteNoTLS("baz", "HostSNI(`*`)", 0, handler))
+
+	expected := []rules.RouteMatch{
+		{Name: "foo", Rule: "HostSNI(`foo.bar`)", Priority: 18, Reasons: []string{"HostSNI(`foo.bar`) does not match"}},
+		{Name: "bar", Rule: "HostSNI(`*`)", Priority: 1, Matched: true},
+	}
+	assert.Equal(t, expected, router.Explain(rules.ConnData{ServerName: "bar.foo"}, true))
+
+	router.AddRouteHTTPTLS("bar.foo", &tls.Config{})
+
+	expected = []rules.RouteMatch{
+		{Name: "foo", Rule: "HostSNI(`foo.bar`)", Priority: 18, Reasons: []string{"HostSNI(`foo.bar`) does not match"}},
+		{Name: "bar", Rule: "HostSNI(`*`)", Priority: 1, Reasons: []string{`the server name "bar.foo" is handled by the HTTPS routers`}},
+	}
+	assert.Equal(t, expected, router.Explain(rules.ConnData{ServerName: "bar.foo"}, true))
+
+	expected = []rules.RouteMatch{
+		{Name: "baz", Rule: "HostSNI(`*`)", Priority: 12, Matched: true},
+	}
+	assert.Equal(t, expected, router.Explain(rules.ConnData{}, false))
+}
+
+func TestRouter_samePriority(t *testing.T) {
+	handler := HandlerFunc(func(conn WriteCloser) {})
+
+	for _, names := range [][]string{{"foo", "bar"}, {"bar", "foo"}} {
+		router := &Router{}
+		for _, name := range names {
+			require.NoError(t, router.AddRouteNoTLS(name, "HostSNI(`*`)", 0, handler))
+		}
+
+		expected := []rules.RouteMatch{
+			{Name: "bar", Rule: "HostSNI(`*`)", Priority: 12, Matched: true},
+			{Name: "foo", Rule: "HostSNI(`*`)", Priority: 12, Matched: true},
+		}
+		assert.Equal(t, expected, router.Explain(rules.ConnData{}, false))
+		assert.Equal(t, "bar", router.routesNoTLS.match(rules.ConnData{}).name)
+	}
+}
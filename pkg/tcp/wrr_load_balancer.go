@@ -2,8 +2,11 @@ package tcp
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"sync"
 
+	"github.com/containous/traefik/v2/pkg/hashring"
 	"github.com/containous/traefik/v2/pkg/log"
 )
 
@@ -18,6 +21,9 @@ type WRRLoadBalancer struct {
 	lock          sync.RWMutex
 	currentWeight int
 	index         int
+	// ring holds the names of the servers, when the source IP affinity is enabled.
+	ring        *hashring.Ring
+	ringServers map[string]Handler
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer
@@ -27,6 +33,14 @@ func NewWRRLoadBalancer() *WRRLoadBalancer {
 	}
 }
 
+// EnableSourceIPAffinity makes the load balancer send the connections of a client IP to the same server,
+// using a consistent hash of the client IP.
+// It must be called before adding the servers.
+func (b *WRRLoadBalancer) EnableSourceIPAffinity() {
+	b.ring = hashring.New()
+	b.ringServers = make(map[string]Handler)
+}
+
 // ServeTCP forwards the connection to the right service
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 	if len(b.servers) == 0 {
@@ -34,6 +48,13 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 		return
 	}
 
+	if b.ring != nil {
+		if next, ok := b.sourceIPServer(conn); ok {
+			next.ServeTCP(conn)
+			return
+		}
+	}
+
 	next, err := b.next()
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
@@ -50,11 +71,45 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 
 // AddWeightServer appends a server to the existing list with a weight
 func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.AddNamedServer(strconv.Itoa(len(b.servers)), serverHandler, weight)
+}
+
+// AddNamedServer appends a server to the existing list with a weight.
+// The name identifies the server in the source IP affinity ring,
+// so that the clients of the other servers keep their server when a server is added or removed.
+func (b *WRRLoadBalancer) AddNamedServer(name string, serverHandler Handler, weight *int) {
 	w := 1
 	if weight != nil {
 		w = *weight
 	}
 	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+
+	// The servers sharing a name share their place in the ring, the first one handling the connections.
+	if b.ring != nil {
+		if _, ok := b.ringServers[name]; !ok {
+			b.ringServers[name] = serverHandler
+			b.ring.Add(name, w)
+		}
+	}
+}
+
+func (b *WRRLoadBalancer) sourceIPServer(conn WriteCloser) (Handler, bool) {
+	if conn.RemoteAddr() == nil {
+		return nil, false
+	}
+
+	clientIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		clientIP = conn.RemoteAddr().String()
+	}
+
+	node, ok := b.ring.Get(clientIP)
+	if !ok {
+		return nil, false
+	}
+
+	handler, ok := b.ringServers[node]
+	return handler, ok
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"net"
+	"strconv"
 	"testing"
 	"time"
 
@@ -10,7 +11,8 @@ import (
 )
 
 type fakeConn struct {
-	call map[string]int
+	call       map[string]int
+	remoteAddr net.Addr
 }
 
 func (f *fakeConn) Read(b []byte) (n int, err error) {
@@ -31,7 +33,7 @@ func (f *fakeConn) LocalAddr() net.Addr {
 }
 
 func (f *fakeConn) RemoteAddr() net.Addr {
-	panic("implement me")
+	return f.remoteAddr
 }
 
 func (f *fakeConn) SetDeadline(t time.Time) error {
@@ -129,3 +131,82 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_sourceIP(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	balancer.EnableSourceIPAffinity()
+
+	for _, server := range []string{"h1", "h2", "h3"} {
+		server := server
+		balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}))
+	}
+
+	for _, clientIP := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"} {
+		conn := &fakeConn{
+			call:       make(map[string]int),
+			remoteAddr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 1234},
+		}
+
+		for i := 0; i < 5; i++ {
+			// The port changes for each connection of a client.
+			conn.remoteAddr.(*net.TCPAddr).Port++
+			balancer.ServeTCP(conn)
+		}
+
+		// All the connections of a client go to the same server.
+		assert.Len(t, conn.call, 1)
+	}
+}
+
+func TestLoadBalancing_sourceIP_serverRemoved(t *testing.T) {
+	newBalancer := func(servers ...string) *WRRLoadBalancer {
+		balancer := NewWRRLoadBalancer()
+		balancer.EnableSourceIPAffinity()
+
+		for _, server := range servers {
+			server := server
+			balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {
+				_, err := conn.Write([]byte(server))
+				require.NoError(t, err)
+			}), nil)
+		}
+		return balancer
+	}
+
+	serverOf := func(balancer *WRRLoadBalancer, clientIP string) string {
+		conn := &fakeConn{
+			call:       make(map[string]int),
+			remoteAddr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 1234},
+		}
+		balancer.ServeTCP(conn)
+
+		require.Len(t, conn.call, 1)
+		for server := range conn.call {
+			return server
+		}
+		return ""
+	}
+
+	before := newBalancer("10.0.1.1:80", "10.0.1.2:80", "10.0.1.3:80")
+	// The middle server is removed.
+	after := newBalancer("10.0.1.1:80", "10.0.1.3:80")
+
+	var moved int
+	for i := 1; i <= 100; i++ {
+		clientIP := "10.0.0." + strconv.Itoa(i)
+
+		server := serverOf(before, clientIP)
+		if server == "10.0.1.2:80" {
+			moved++
+			continue
+		}
+
+		// The clients of the remaining servers keep their server.
+		assert.Equal(t, server, serverOf(after, clientIP), clientIP)
+	}
+
+	assert.NotZero(t, moved)
+}